@@ -94,6 +94,7 @@ type Request struct {
 	MultipartForm   *multipart.Form
 	parsedForm      bool
 	parsedMultipart bool
+	bodyLimit       int
 }
 
 // requestHeader provides http.Header-like access to fasthttp request headers.
@@ -170,7 +171,7 @@ func (q requestQuery) Has(key string) bool {
 }
 
 // newRequest creates a Request adapter from a fasthttp.RequestCtx.
-func newRequest(ctx *fasthttp.RequestCtx, pattern string) *Request {
+func newRequest(ctx *fasthttp.RequestCtx, pattern string, bodyLimit int) *Request {
 	var remoteAddr string
 	if ip := ctx.RemoteIP(); ip != nil {
 		remoteAddr = ip.String()
@@ -190,13 +191,14 @@ func newRequest(ctx *fasthttp.RequestCtx, pattern string) *Request {
 
 	return &Request{
 		ctx:           ctx,
+		bodyLimit:     bodyLimit,
 		Header:        requestHeader{ctx: ctx},
 		Method:        utils.BytesToString(ctx.Method()),
 		URL:           &requestURL{ctx: ctx},
 		Proto:         proto,
 		ProtoMajor:    protoMajor,
 		ProtoMinor:    protoMinor,
-		Body:          io.NopCloser(&bodyReader{ctx: ctx}),
+		Body:          io.NopCloser(&bodyReader{ctx: ctx, limit: bodyLimit}),
 		ContentLength: int64(ctx.Request.Header.ContentLength()),
 		Host:          utils.BytesToString(ctx.Host()),
 		RemoteAddr:    remoteAddr,
@@ -208,17 +210,22 @@ func newRequest(ctx *fasthttp.RequestCtx, pattern string) *Request {
 
 // bodyReader reads the request body from fasthttp.
 type bodyReader struct {
-	ctx  *fasthttp.RequestCtx
-	read bool
-	data []byte
-	pos  int
+	ctx   *fasthttp.RequestCtx
+	limit int
+	read  bool
+	data  []byte
+	err   error
+	pos   int
 }
 
 func (r *bodyReader) Read(p []byte) (int, error) {
 	if !r.read {
-		r.data = r.ctx.PostBody()
+		r.data, r.err = readRequestBody(r.ctx, r.limit)
 		r.read = true
 	}
+	if r.err != nil {
+		return 0, r.err
+	}
 	if r.pos >= len(r.data) {
 		return 0, io.EOF
 	}
@@ -259,6 +266,11 @@ func (r *Request) ParseForm() error {
 	r.PostForm = make(url.Values)
 	r.Form = make(url.Values)
 
+	// Buffer the body through the BodyLimit check before fasthttp parses it.
+	if _, err := readRequestBody(r.ctx, r.bodyLimit); err != nil {
+		return err
+	}
+
 	// Parse Query args into Form
 	for key, value := range r.ctx.QueryArgs().All() {
 		ks, vs := utils.BytesToString(key), utils.BytesToString(value)
@@ -304,6 +316,10 @@ func (r *Request) ParseMultipartForm(maxMemory int64) error {
 	}
 	r.parsedMultipart = true
 
+	if _, err := readRequestBody(r.ctx, r.bodyLimit); err != nil {
+		return err
+	}
+
 	form, err := r.ctx.MultipartForm()
 	if err != nil {
 		return err
@@ -0,0 +1,94 @@
+package gofi
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type aliasSchema struct {
+	Request struct {
+		Body struct {
+			Username string   `json:"username" validate:"username"`
+			Status   string   `json:"status" validate:"status"`
+			Tags     []string `json:"tags" validate:"dive,slug"`
+		}
+	}
+}
+
+func aliasRouter() Router {
+	r := NewRouter()
+	r.RegisterAlias("username", "required,min=3,max=32,alphanum")
+	r.RegisterAlias("status", "required,oneof=active inactive")
+	r.RegisterAlias("slug", "lowercase,short")
+	r.RegisterAlias("short", "max=8")
+	return r
+}
+
+func TestRegisterAlias(t *testing.T) {
+	bind := func(body string) error {
+		var bindErr error
+		_, err := aliasRouter().Inject(InjectOptions{
+			Path:    "/users",
+			Method:  "POST",
+			Headers: map[string]string{"Content-Type": "application/json"},
+			Body:    strings.NewReader(body),
+			Handler: &RouteOptions{
+				Schema: &aliasSchema{},
+				Handler: func(c Context) error {
+					_, bindErr = ValidateAndBind[aliasSchema](c)
+					return nil
+				},
+			},
+		})
+		require.Nil(t, err)
+		return bindErr
+	}
+
+	assert.NoError(t, bind(`{"username":"gofi42","status":"active","tags":["web"]}`))
+
+	var verr ValidationError
+	require.True(t, errors.As(bind(`{"username":"go","status":"active"}`), &verr))
+	assert.Equal(t, "/username", verr.Pointer())
+	assert.Equal(t, "min", verr.Rule())
+
+	require.True(t, errors.As(bind(`{"username":"gofi","status":"active","tags":["Web"]}`), &verr))
+	assert.Equal(t, "/tags/0", verr.Pointer())
+	assert.Equal(t, "lowercase", verr.Rule())
+
+	require.True(t, errors.As(bind(`{"username":"gofi","status":"active","tags":["webservers"]}`), &verr))
+	assert.Equal(t, "max", verr.Rule())
+}
+
+func TestRegisterAlias_Docs(t *testing.T) {
+	r := aliasRouter()
+	r.Post("/users", RouteOptions{Schema: &aliasSchema{}, Handler: func(c Context) error { return nil }})
+
+	doc := OpenAPISpec(r, DocsOptions{})
+	body := (*doc.Paths)["/users"]["post"].RequestBody.Content["*/*"].Schema
+	assert.ElementsMatch(t, []string{"username", "status"}, body.Required)
+	assert.Equal(t, []any{"active", "inactive"}, body.Properties["status"].Enum)
+	require.NotNil(t, body.Properties["username"].Maximum)
+	assert.Equal(t, float64(32), *body.Properties["username"].Maximum)
+}
+
+func TestRegisterAlias_Rejected(t *testing.T) {
+	r := NewRouter()
+	r.RegisterAlias("a", "b,min=1")
+	r.RegisterAlias("b", "c")
+
+	r.RegisterAlias("c", "required,a")
+	r.RegisterAlias("required", "min=1")
+
+	errs := r.Errors()
+	require.Len(t, errs, 2)
+	assert.EqualError(t, errs[0], "validation alias cycle: c -> a -> b -> c")
+	assert.EqualError(t, errs[1], "validation alias 'required' shadows a built-in rule")
+
+	// The rejected alias is not kept.
+	r.RegisterAlias("c", "required")
+	assert.Len(t, r.Errors(), 2)
+}
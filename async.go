@@ -0,0 +1,178 @@
+package gofi
+
+import (
+	stdcontext "context"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"sync"
+)
+
+// AsyncValidatorFn checks a bound value against the request or an outside store, e.g. that an
+// email is not already registered. ctx is cancelled when the client goes away or once every
+// async rule of the request has finished. c is the live request context shared by every
+// validator of the request, so it must only be read from.
+type AsyncValidatorFn = func(ctx stdcontext.Context, c Context, val any) error
+
+// AsyncValidator is a validator that needs the request or I/O. Its rules are used in validate
+// tags like any other rule, but run concurrently after the synchronous rules of the request pass.
+type AsyncValidator interface {
+	Name() string
+	Rule(c ValidatorContext) AsyncValidatorFn
+}
+
+const defaultAsyncWorkers = 4
+
+// asyncCheck is one async rule to run against one bound value.
+type asyncCheck struct {
+	val   any
+	rule  ruleOpts
+	field schemaField
+	keys  []string
+}
+
+func (a *asyncCheck) run(ctx stdcontext.Context, c Context) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("async validator %q panicked: %v", a.rule.rule, r)
+		}
+	}()
+
+	if err := a.rule.async(ctx, c, a.val); err != nil {
+		report := newKeysReport(RequestErr, a.field, a.keys, a.rule.rule, err)
+		report.args, report.value = a.rule.args, a.val
+		return report
+	}
+	return nil
+}
+
+// setAsync marks whether r or any of its children carries async rules, so values without
+// them are never walked.
+func (r *RuleDef) setAsync() {
+	if r == nil {
+		return
+	}
+
+	r.asynced = (r.item != nil && r.item.asynced) ||
+		(r.additionalProperties != nil && r.additionalProperties.asynced) ||
+		(r.keys != nil && r.keys.asynced)
+	for _, rule := range r.rules {
+		r.asynced = r.asynced || rule.async != nil
+	}
+	for _, p := range r.orderedProps {
+		r.asynced = r.asynced || p.asynced
+	}
+}
+
+// collectAsyncChecks walks v along def and appends a check for every async rule that applies
+// to a non-empty value. Empty values are left to the synchronous rules such as required.
+func collectAsyncChecks(dst []asyncCheck, v reflect.Value, def *RuleDef, field schemaField, keys []string) []asyncCheck {
+	if def == nil || !def.asynced || !v.IsValid() {
+		return dst
+	}
+
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return dst
+		}
+		v = v.Elem()
+	}
+	if def.isWrapper() {
+		var ok bool
+		if v, ok = wrappedValue(v); !ok {
+			return dst
+		}
+		return collectAsyncChecks(dst, v, def.item, field, keys)
+	}
+
+	if !v.IsZero() {
+		for _, rule := range def.rules {
+			if rule.async != nil {
+				dst = append(dst, asyncCheck{val: v.Interface(), rule: rule, field: field, keys: slices.Clone(keys)})
+			}
+		}
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		for _, child := range def.orderedProps {
+			if !child.asynced {
+				continue
+			}
+			if fv, err := v.FieldByIndexErr(child.accessor.index); err == nil {
+				dst = collectAsyncChecks(dst, fv, child, field, append(keys, child.field))
+			}
+		}
+
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			dst = collectAsyncChecks(dst, v.Index(i), def.item, field, append(keys, strconv.Itoa(i)))
+		}
+
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			key := fmt.Sprint(iter.Key().Interface())
+			dst = collectAsyncChecks(dst, iter.Key(), def.keys, field, append(keys, key))
+			dst = collectAsyncChecks(dst, iter.Value(), def.additionalProperties, field, append(keys, key))
+		}
+	}
+	return dst
+}
+
+// runAsyncValidation runs the async rules of the bound request parts selected by mask, at most
+// Config.AsyncValidationWorkers at a time, and returns every failure in schema order.
+func (c *context) runAsyncValidation(reqStruct reflect.Value, mask requestPartMask) ValidationErrors {
+	var checks []asyncCheck
+	for _, p := range requestParts {
+		if mask&p.mask == 0 {
+			continue
+		}
+		if pdef := c.rules().getReqRules(p.field); pdef != nil && pdef.asynced {
+			checks = collectAsyncChecks(checks, reqStruct.FieldByName(string(p.field)), pdef, p.field, nil)
+		}
+	}
+	if len(checks) == 0 {
+		return nil
+	}
+
+	ctx, cancel := stdcontext.WithCancel(c.Context())
+	defer cancel()
+
+	results := make([]error, len(checks))
+	if len(checks) == 1 {
+		results[0] = checks[0].run(ctx, c)
+	} else {
+		workers := c.serverOpts.asyncWorkers
+		if workers <= 0 {
+			workers = defaultAsyncWorkers
+		}
+		workers = min(workers, len(checks))
+
+		next := make(chan int)
+		var wg sync.WaitGroup
+		wg.Add(workers)
+		for range workers {
+			go func() {
+				defer wg.Done()
+				for i := range next {
+					results[i] = checks[i].run(ctx, c)
+				}
+			}()
+		}
+		for i := range checks {
+			next <- i
+		}
+		close(next)
+		wg.Wait()
+	}
+
+	var errs ValidationErrors
+	for _, err := range results {
+		if err != nil {
+			errs = appendValidationErrors(errs, err, RequestErr, schemaReq)
+		}
+	}
+	return errs
+}
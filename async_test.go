@@ -0,0 +1,186 @@
+package gofi
+
+import (
+	stdcontext "context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type uniqueEmailValidator struct {
+	taken map[string]bool
+	calls atomic.Int32
+}
+
+func (v *uniqueEmailValidator) Name() string { return "unique_email" }
+
+func (v *uniqueEmailValidator) Rule(_ ValidatorContext) AsyncValidatorFn {
+	return func(ctx stdcontext.Context, c Context, val any) error {
+		v.calls.Add(1)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if v.taken[c.Param("tenant")+"/"+val.(string)] {
+			return errors.New("email is already registered")
+		}
+		return nil
+	}
+}
+
+type asyncSchema struct {
+	Request struct {
+		Body struct {
+			Email   string `json:"email" validate:"required,email,unique_email"`
+			Members []struct {
+				Email string `json:"email" validate:"unique_email"`
+			} `json:"members"`
+		}
+	}
+}
+
+func bindAsync(t *testing.T, r Router, body string) error {
+	t.Helper()
+
+	var bindErr error
+	_, err := r.Inject(InjectOptions{
+		Path:    "/tenants/:tenant/users",
+		Method:  "POST",
+		Paths:   map[string]string{"tenant": "acme"},
+		Headers: map[string]string{"Content-Type": "application/json"},
+		Body:    strings.NewReader(body),
+		Handler: &RouteOptions{
+			Schema: &asyncSchema{},
+			Handler: func(c Context) error {
+				_, bindErr = ValidateAndBind[asyncSchema](c)
+				return nil
+			},
+		},
+	})
+	require.Nil(t, err)
+	return bindErr
+}
+
+func TestAsyncValidator(t *testing.T) {
+	v := &uniqueEmailValidator{taken: map[string]bool{"acme/taken@example.com": true}}
+	r := NewRouter()
+	r.RegisterAsyncValidator(v)
+
+	assert.NoError(t, bindAsync(t, r, `{"email":"new@example.com","members":[{"email":"other@example.com"}]}`))
+	assert.Equal(t, int32(2), v.calls.Load())
+
+	err := bindAsync(t, r, `{"email":"taken@example.com","members":[{"email":"ok@example.com"},{"email":"taken@example.com"}]}`)
+	var verrs ValidationErrors
+	require.True(t, errors.As(err, &verrs))
+	require.Len(t, verrs, 2)
+	assert.Equal(t, "/email", verrs[0].Pointer())
+	assert.Equal(t, "unique_email", verrs[0].Rule())
+	assert.Equal(t, "email is already registered", verrs[0].Message())
+	assert.Equal(t, "/members/1/email", verrs[1].Pointer())
+}
+
+func TestAsyncValidator_SkippedWhenSyncRulesFail(t *testing.T) {
+	v := &uniqueEmailValidator{}
+	r := NewRouter()
+	r.RegisterAsyncValidator(v)
+
+	err := bindAsync(t, r, `{"email":"not-an-email","members":[{"email":"a@example.com"}]}`)
+	var verrs ValidationErrors
+	require.True(t, errors.As(err, &verrs))
+	require.Len(t, verrs, 1)
+	assert.Equal(t, "email", verrs[0].Rule())
+	assert.Equal(t, int32(0), v.calls.Load())
+
+	// Empty values are left to the synchronous rules.
+	assert.NoError(t, bindAsync(t, r, `{"email":"a@example.com","members":[{"email":""}]}`))
+	assert.Equal(t, int32(1), v.calls.Load())
+}
+
+type slowValidator struct {
+	running atomic.Int32
+	peak    atomic.Int32
+}
+
+func (v *slowValidator) Name() string { return "slow" }
+
+func (v *slowValidator) Rule(_ ValidatorContext) AsyncValidatorFn {
+	return func(ctx stdcontext.Context, _ Context, _ any) error {
+		n := v.running.Add(1)
+		defer v.running.Add(-1)
+		for {
+			peak := v.peak.Load()
+			if n <= peak || v.peak.CompareAndSwap(peak, n) {
+				break
+			}
+		}
+		select {
+		case <-time.After(20 * time.Millisecond):
+		case <-ctx.Done():
+		}
+		return nil
+	}
+}
+
+func TestAsyncValidator_Workers(t *testing.T) {
+	type schema struct {
+		Request struct {
+			Body struct {
+				Ids []string `json:"ids" validate:"dive,slow"`
+			}
+		}
+	}
+
+	v := &slowValidator{}
+	r := NewRouter()
+	r.Configure(Config{AsyncValidationWorkers: 2})
+	r.RegisterAsyncValidator(v)
+
+	var bindErr error
+	_, err := r.Inject(InjectOptions{
+		Path:    "/ids",
+		Method:  "POST",
+		Headers: map[string]string{"Content-Type": "application/json"},
+		Body:    strings.NewReader(`{"ids":["a","b","c","d","e","f"]}`),
+		Handler: &RouteOptions{
+			Schema: &schema{},
+			Handler: func(c Context) error {
+				_, bindErr = ValidateAndBind[schema](c)
+				return nil
+			},
+		},
+	})
+	require.Nil(t, err)
+
+	assert.NoError(t, bindErr)
+	assert.Equal(t, int32(2), v.peak.Load())
+}
+
+func TestAsyncValidator_NotRunForDocs(t *testing.T) {
+	v := &uniqueEmailValidator{}
+	r := NewRouter()
+	r.RegisterAsyncValidator(v)
+	r.Post("/users", RouteOptions{Schema: &asyncSchema{}, Handler: func(c Context) error { return nil }})
+
+	doc := OpenAPISpec(r, DocsOptions{})
+	body := (*doc.Paths)["/users"]["post"].RequestBody.Content["*/*"].Schema
+	assert.Equal(t, "email", body.Properties["email"].Format)
+	assert.Equal(t, int32(0), v.calls.Load())
+}
+
+func TestAsyncValidator_RegisteredAfterRoutes(t *testing.T) {
+	r := NewRouter()
+	r.Post("/users", RouteOptions{Schema: &asyncSchema{}, Handler: func(c Context) error { return nil }})
+	require.Empty(t, r.Errors())
+
+	r.RegisterAsyncValidator(&uniqueEmailValidator{})
+	errs := r.Errors()
+	require.Len(t, errs, 2)
+	assert.Equal(t, "Request.Body.email", errs[0].Field)
+	assert.Equal(t, `validate:"unique_email"`, errs[0].Tag)
+	assert.Equal(t, "Request.Body.members.email", errs[1].Field)
+	assert.ErrorContains(t, r.Build(), "async validator 'unique_email' was registered after this route")
+}
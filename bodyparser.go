@@ -1,9 +1,14 @@
 package gofi
 
 import (
+	"encoding/base64"
+	"fmt"
 	"io"
 	"reflect"
+	"strconv"
+	"time"
 
+	"github.com/michaelolof/gofi/utils"
 	"github.com/valyala/fastjson"
 )
 
@@ -19,6 +24,8 @@ type ResponseOptions struct {
 	Context     ParserContext
 	SchemaRules *RuleDef
 	Body        reflect.Value
+	// ContentType is the media type declared on the response schema for the status code being sent.
+	ContentType string
 }
 
 type BodyParser interface {
@@ -32,11 +39,15 @@ type ParserContext interface {
 	Writer() ResponseWriter
 	Request() *Request
 	CustomSpecs() CustomSpecs
+	// SetContentType overrides the response Content-Type written by c.Send.
+	// Encoders use it to add parameters such as a multipart boundary.
+	SetContentType(contentType string)
 	getParser() *fastjson.Parser
 }
 
 type parserContext struct {
-	c *context
+	c           *context
+	contentType string
 }
 
 func (p *parserContext) Writer() ResponseWriter {
@@ -51,6 +62,61 @@ func (p *parserContext) CustomSpecs() CustomSpecs {
 	return p.c.serverOpts.customSpecs
 }
 
+func (p *parserContext) SetContentType(contentType string) {
+	p.contentType = contentType
+}
+
 func (p *parserContext) getParser() *fastjson.Parser {
 	return p.c.getParser()
 }
+
+// encodeTextValue renders a scalar schema value as text for encoders that have no
+// native representation for JSON types (csv, form and multipart). Nil pointers
+// render as an empty string.
+func encodeTextValue(c ParserContext, val reflect.Value, rules *RuleDef) (string, error) {
+	if rules != nil {
+		if spec, ok := c.CustomSpecs().Find(string(rules.format)); ok {
+			return spec.Encode(val.Interface())
+		}
+	}
+
+	for val.Kind() == reflect.Pointer || val.Kind() == reflect.Interface {
+		if val.IsNil() {
+			return "", nil
+		}
+		val = val.Elem()
+	}
+
+	if val.Type() == utils.TimeType {
+		t := val.Interface().(time.Time)
+		if t.IsZero() {
+			return "", nil
+		}
+		layout := time.RFC3339Nano
+		if rules != nil && rules.pattern != "" {
+			layout = rules.pattern
+		}
+		return t.Format(layout), nil
+	}
+
+	if utils.IsByteSlice(val.Type()) {
+		return base64.StdEncoding.EncodeToString(val.Bytes()), nil
+	}
+
+	switch val.Kind() {
+	case reflect.String:
+		return val.String(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(val.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(val.Uint(), 10), nil
+	case reflect.Float32:
+		return strconv.FormatFloat(val.Float(), 'f', -1, 32), nil
+	case reflect.Float64:
+		return strconv.FormatFloat(val.Float(), 'f', -1, 64), nil
+	case reflect.Bool:
+		return strconv.FormatBool(val.Bool()), nil
+	default:
+		return "", fmt.Errorf("cannot encode value of type %s as text", val.Type())
+	}
+}
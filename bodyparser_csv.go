@@ -0,0 +1,242 @@
+package gofi
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"io"
+	"mime"
+	"reflect"
+	"slices"
+	"strconv"
+)
+
+// BodyStreamEncoder is an optional interface a BodyParser can implement to write
+// responses directly to the connection instead of returning a buffered payload.
+// When the matched parser implements it, c.Send calls ValidateResponse first, so a
+// failing body still produces an error response, and then streams the body through
+// SetBodyStreamWriter.
+type BodyStreamEncoder interface {
+	// ValidateResponse checks the whole body against the schema before any bytes are sent.
+	ValidateResponse(obj any, opts ResponseOptions) error
+	// StreamResponse writes a body that has already passed ValidateResponse. It runs after
+	// the handler has returned, so opts.Context is detached from the request.
+	StreamResponse(w *bufio.Writer, obj any, opts ResponseOptions) error
+}
+
+// CSVBodyParser encodes slice-of-struct response bodies as text/csv.
+// The header row is built from the item struct's json tag names and each row is
+// validated against the item rules before it is written.
+type CSVBodyParser struct {
+	// Comma is the field delimiter. Defaults to ','.
+	Comma rune
+	// UseCRLF terminates rows with \r\n instead of \n.
+	UseCRLF bool
+	// FlushEvery sets how many rows are written between flushes to the client. Defaults to 100.
+	FlushEvery int
+}
+
+func (p *CSVBodyParser) Match(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return mediaType == "text/csv"
+}
+
+func (p *CSVBodyParser) ValidateAndDecodeRequest(r io.ReadCloser, opts RequestOptions) error {
+	return errors.New("csv body parser does not support request decoding")
+}
+
+func (p *CSVBodyParser) ValidateAndEncodeResponse(obj any, opts ResponseOptions) ([]byte, error) {
+	if err := p.ValidateResponse(obj, opts); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	w := bufio.NewWriter(&buf)
+	if err := p.StreamResponse(w, obj, opts); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (p *CSVBodyParser) ValidateResponse(obj any, opts ResponseOptions) error {
+	rules := opts.SchemaRules
+	if rules == nil {
+		return newErrReport(ResponseErr, schemaBody, "", "required", errors.New("csv body schema is not defined"))
+	}
+
+	body := csvBodyRows(opts.Body)
+	if body.IsValid() && body.Kind() != reflect.Slice && body.Kind() != reflect.Array {
+		return newErrReport(ResponseErr, schemaBody, "", "typeMismatch", errors.New("csv body must be a slice of structs"))
+	}
+
+	item := rules.item
+	if item == nil || item.kind != reflect.Struct {
+		return newErrReport(ResponseErr, schemaBody, "", "typeMismatch", errors.New("csv body items must be structs"))
+	}
+
+	var bodyVal any
+	if body.IsValid() {
+		bodyVal = body.Interface()
+	}
+	if err := runValidation(bodyVal, ResponseErr, schemaBody, "", rules.rules); err != nil {
+		return err
+	}
+
+	if !body.IsValid() {
+		return nil
+	}
+
+	columns := csvColumns(item)
+	for i := 0; i < body.Len(); i++ {
+		if err := p.validateRow(body.Index(i), item, columns, i); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *CSVBodyParser) StreamResponse(w *bufio.Writer, obj any, opts ResponseOptions) error {
+	body := csvBodyRows(opts.Body)
+	columns := csvColumns(opts.SchemaRules.item)
+
+	cw := csv.NewWriter(w)
+	if p.Comma != 0 {
+		cw.Comma = p.Comma
+	}
+	cw.UseCRLF = p.UseCRLF
+
+	record := make([]string, len(columns))
+	for i, col := range columns {
+		record[i] = col.field
+	}
+	if err := cw.Write(record); err != nil {
+		return err
+	}
+
+	flushEvery := p.FlushEvery
+	if flushEvery <= 0 {
+		flushEvery = 100
+	}
+
+	rows := 0
+	if body.IsValid() {
+		rows = body.Len()
+	}
+
+	for i := 0; i < rows; i++ {
+		if err := p.encodeRow(opts.Context, body.Index(i), columns, i, record); err != nil {
+			return err
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+
+		if (i+1)%flushEvery == 0 {
+			cw.Flush()
+			if err := cw.Error(); err != nil {
+				return err
+			}
+			if err := w.Flush(); err != nil {
+				return err
+			}
+		}
+	}
+
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return err
+	}
+	return w.Flush()
+}
+
+// csvBodyRows dereferences the response body down to its slice value.
+// An invalid value is returned for a nil body.
+func csvBodyRows(body reflect.Value) reflect.Value {
+	for body.IsValid() && (body.Kind() == reflect.Pointer || body.Kind() == reflect.Interface) {
+		if body.IsNil() {
+			return reflect.Value{}
+		}
+		body = body.Elem()
+	}
+	return body
+}
+
+func (p *CSVBodyParser) validateRow(row reflect.Value, item *RuleDef, columns []*RuleDef, idx int) error {
+	rowKey := strconv.Itoa(idx)
+
+	var rowVal any
+	if row.IsValid() {
+		rowVal = row.Interface()
+	}
+	if err := runValidation(rowVal, ResponseErr, schemaBody, rowKey, item.rules); err != nil {
+		return err
+	}
+
+	for row.Kind() == reflect.Pointer || row.Kind() == reflect.Interface {
+		if row.IsNil() {
+			return newErrReport(ResponseErr, schemaBody, rowKey, "required", errors.New("csv row must not be nil"))
+		}
+		row = row.Elem()
+	}
+
+	for _, col := range columns {
+		fv, err := row.FieldByIndexErr(col.accessor.index)
+		if err != nil {
+			fv = reflect.Value{}
+		}
+
+		var fany any
+		if fv.IsValid() {
+			fany = fv.Interface()
+		}
+		if err := runValidation(fany, ResponseErr, schemaBody, rowKey+"."+col.field, col.rules); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (p *CSVBodyParser) encodeRow(c ParserContext, row reflect.Value, columns []*RuleDef, idx int, record []string) error {
+	for row.Kind() == reflect.Pointer || row.Kind() == reflect.Interface {
+		row = row.Elem()
+	}
+
+	for i, col := range columns {
+		fv, err := row.FieldByIndexErr(col.accessor.index)
+		if err != nil {
+			fv = reflect.Value{}
+		}
+
+		cell, err := p.encodeCell(c, fv, col)
+		if err != nil {
+			return newErrReport(ResponseErr, schemaBody, strconv.Itoa(idx)+"."+col.field, "encoder", err)
+		}
+		record[i] = cell
+	}
+
+	return nil
+}
+
+func (p *CSVBodyParser) encodeCell(c ParserContext, val reflect.Value, col *RuleDef) (string, error) {
+	if !val.IsValid() || (col.defStr != "" && val.IsZero()) {
+		return col.defaultText(), nil
+	}
+	return encodeTextValue(c, val, col)
+}
+
+// csvColumns returns the item properties that should be written as columns, in struct order.
+func csvColumns(item *RuleDef) []*RuleDef {
+	columns := make([]*RuleDef, 0, len(item.orderedProps))
+	for _, prop := range item.orderedProps {
+		if slices.Contains(prop.tags["json"], "-") {
+			continue
+		}
+		columns = append(columns, prop)
+	}
+	return columns
+}
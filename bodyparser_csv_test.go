@@ -0,0 +1,123 @@
+package gofi
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type csvRow struct {
+	ID      int       `json:"id" validate:"required"`
+	Name    string    `json:"name" validate:"max=10"`
+	Active  *bool     `json:"active"`
+	Created time.Time `json:"created" pattern:"2006-01-02"`
+	Secret  string    `json:"-"`
+}
+
+type csvSchema struct {
+	Ok struct {
+		Header struct {
+			ContentType string `json:"content-type" default:"text/csv"`
+		}
+		Body []csvRow `validate:"required"`
+	}
+}
+
+func TestCSVBodyParser_StreamsRows(t *testing.T) {
+	yes := true
+	mux := NewRouter()
+	handler := RouteOptions{
+		Schema: &csvSchema{},
+		Handler: func(c Context) error {
+			var s csvSchema
+			s.Ok.Body = []csvRow{
+				{ID: 1, Name: "ada", Active: &yes, Created: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), Secret: "x"},
+				{ID: 2, Name: "grace, h", Created: time.Date(2024, 6, 2, 0, 0, 0, 0, time.UTC)},
+			}
+			return c.Send(200, s.Ok)
+		},
+	}
+
+	res, err := mux.Inject(InjectOptions{Path: "/export", Method: "GET", Handler: &handler})
+	assert.Nil(t, err)
+	assert.Equal(t, 200, res.StatusCode)
+	assert.Equal(t, "text/csv", res.Header("Content-Type"))
+	assert.Equal(t, "id,name,active,created\n1,ada,true,2024-05-01\n2,\"grace, h\",,2024-06-02\n", string(res.Body))
+}
+
+func TestCSVBodyParser_ValidatesRows(t *testing.T) {
+	mux := NewRouter()
+	var sendErr error
+	handler := RouteOptions{
+		Schema: &csvSchema{},
+		Handler: func(c Context) error {
+			var s csvSchema
+			s.Ok.Body = []csvRow{
+				{ID: 1, Name: "ok"},
+				{ID: 2, Name: "far too long a name"},
+			}
+			sendErr = c.Send(200, s.Ok)
+			return sendErr
+		},
+	}
+
+	res, err := mux.Inject(InjectOptions{Path: "/export", Method: "GET", Handler: &handler})
+	assert.Nil(t, err)
+	assert.Equal(t, 500, res.StatusCode)
+
+	var verr ValidationError
+	assert.ErrorAs(t, sendErr, &verr)
+	assert.Equal(t, "max", verr.Rule())
+	assert.Equal(t, "1.name", verr.SchemaValue())
+}
+
+func TestCSVBodyParser_EmptyBodyWritesHeader(t *testing.T) {
+	type schema struct {
+		Ok struct {
+			Header struct {
+				ContentType string `json:"content-type" default:"text/csv"`
+			}
+			Body []*csvRow
+		}
+	}
+
+	mux := NewRouter()
+	handler := RouteOptions{
+		Schema: &schema{},
+		Handler: func(c Context) error {
+			var s schema
+			return c.Send(200, s.Ok)
+		},
+	}
+
+	res, err := mux.Inject(InjectOptions{Path: "/export", Method: "GET", Handler: &handler})
+	assert.Nil(t, err)
+	assert.Equal(t, "id,name,active,created\n", string(res.Body))
+}
+
+func TestCSVBodyParser_StreamsManyRows(t *testing.T) {
+	const total = 2000
+	mux := NewRouter()
+	handler := RouteOptions{
+		Schema: &csvSchema{},
+		Handler: func(c Context) error {
+			var s csvSchema
+			s.Ok.Body = make([]csvRow, total)
+			for i := range s.Ok.Body {
+				s.Ok.Body[i] = csvRow{ID: i + 1, Name: "row" + strconv.Itoa(i+1)}
+			}
+			return c.Send(200, s.Ok)
+		},
+	}
+
+	res, err := mux.Inject(InjectOptions{Path: "/export", Method: "GET", Handler: &handler})
+	assert.Nil(t, err)
+	assert.Equal(t, 200, res.StatusCode)
+
+	lines := strings.Split(strings.TrimSuffix(string(res.Body), "\n"), "\n")
+	assert.Len(t, lines, total+1)
+	assert.Equal(t, "2000,row2000,,", lines[total])
+}
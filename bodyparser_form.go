@@ -8,6 +8,8 @@ import (
 	"mime"
 	"net/url"
 	"reflect"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
@@ -43,6 +45,7 @@ func (f *FormBodyParser) ValidateAndDecodeRequest(r io.ReadCloser, opts RequestO
 	if err != nil {
 		return newErrReport(RequestErr, schemaBody, "", "parser", err)
 	}
+	normalizeFormKeys(formValues)
 
 	if opts.SchemaRules == nil {
 		return nil
@@ -51,6 +54,7 @@ func (f *FormBodyParser) ValidateAndDecodeRequest(r io.ReadCloser, opts RequestO
 	if opts.SchemaRules.kind != reflect.Struct {
 		return newErrReport(RequestErr, schemaBody, "", "typeMismatch", errors.New("form body must be a struct"))
 	}
+	applyFormDefaults(formValues, opts.SchemaRules, "")
 
 	if opts.ShouldBind && opts.Body != nil {
 		bodyStruct := f.getFieldStruct(*opts.Body, string(schemaBody))
@@ -60,7 +64,8 @@ func (f *FormBodyParser) ValidateAndDecodeRequest(r io.ReadCloser, opts RequestO
 		// Capture custom specs for use in parseVal closures
 		customSpecs := opts.Context.CustomSpecs()
 
-		for key, rule := range opts.SchemaRules.properties {
+		for _, rule := range opts.SchemaRules.orderedProps {
+			key := rule.field
 			vals, ok := formValues[key]
 
 			// Check if we have nested fields or the exact key
@@ -89,6 +94,7 @@ func (f *FormBodyParser) ValidateAndDecodeRequest(r io.ReadCloser, opts RequestO
 
 			// Helper to parse string to value, respecting custom specs
 			parseVal := func(v string, r *RuleDef) (any, error) {
+				v = r.modify(v)
 				if r.format == utils.TimeObjectFormat {
 					return time.Parse(r.pattern, v)
 				}
@@ -105,7 +111,7 @@ func (f *FormBodyParser) ValidateAndDecodeRequest(r io.ReadCloser, opts RequestO
 					elemRule = &RuleDef{kind: fieldVal.Type().Elem().Kind()}
 				}
 
-				if elemRule.kind == reflect.Struct {
+				if _, isCustom := customSpecs.Find(string(elemRule.format)); elemRule.kind == reflect.Struct && !isCustom {
 					// Struct array (Deep binding)
 					i := 0
 					var nslice reflect.Value
@@ -226,6 +232,10 @@ func (f *FormBodyParser) ValidateAndDecodeRequest(r io.ReadCloser, opts RequestO
 				}
 			}
 		}
+
+		if err := validateCrossFields(opts.SchemaRules, RequestErr, schemaBody, nil, structFieldLookup(bodyStruct)); err != nil {
+			return err
+		}
 	}
 
 	return nil
@@ -259,7 +269,8 @@ func (f *FormBodyParser) bindStruct(form map[string][]string, dest reflect.Value
 	if dest.Kind() == reflect.Pointer {
 		dest = dest.Elem()
 	}
-	for key, rule := range rules.properties {
+	for _, rule := range rules.orderedProps {
+		key := rule.field
 		vals, ok := form[key]
 		if !ok {
 			// Check for nested fields even if the top-level key isn't there
@@ -279,6 +290,7 @@ func (f *FormBodyParser) bindStruct(form map[string][]string, dest reflect.Value
 		}
 
 		parseVal := func(v string, r *RuleDef) (any, error) {
+			v = r.modify(v)
 			if r.format == utils.TimeObjectFormat {
 				return time.Parse(r.pattern, v)
 			}
@@ -291,7 +303,7 @@ func (f *FormBodyParser) bindStruct(form map[string][]string, dest reflect.Value
 
 		if rule.kind == reflect.Slice || rule.kind == reflect.Array {
 			elemRule := rule.item
-			if elemRule.kind == reflect.Struct {
+			if _, isCustom := customSpecs.Find(string(elemRule.format)); elemRule.kind == reflect.Struct && !isCustom {
 				// Nested struct array
 				i := 0
 				var nslice reflect.Value
@@ -394,7 +406,192 @@ func (f *FormBodyParser) bindStruct(form map[string][]string, dest reflect.Value
 }
 
 func (f *FormBodyParser) ValidateAndEncodeResponse(s any, opts ResponseOptions) ([]byte, error) {
-	return nil, errors.New("form body parser does not support response encoding")
+	body, err := formResponseBody(opts, "form")
+	if err != nil || !body.IsValid() {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	visitor := formFieldVisitor{
+		c: opts.Context,
+		text: func(key, value string) error {
+			if buf.Len() > 0 {
+				buf.WriteByte('&')
+			}
+			buf.WriteString(url.QueryEscape(key))
+			buf.WriteByte('=')
+			buf.WriteString(url.QueryEscape(value))
+			return nil
+		},
+	}
+
+	if err := visitor.visitStruct("", body, opts.SchemaRules); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// formResponseBody resolves and validates the struct body encoded by the form and multipart parsers.
+// An invalid value is returned when an optional body is not set.
+func formResponseBody(opts ResponseOptions, parser string) (reflect.Value, error) {
+	rules := opts.SchemaRules
+	if rules == nil {
+		return reflect.Value{}, newErrReport(ResponseErr, schemaBody, "", "required", errors.New(parser+" body schema is not defined"))
+	}
+
+	body := opts.Body
+	var bodyVal any
+	if body.IsValid() {
+		bodyVal = body.Interface()
+	}
+	if err := runValidation(bodyVal, ResponseErr, schemaBody, "", rules.rules); err != nil {
+		return reflect.Value{}, err
+	}
+
+	for body.IsValid() && (body.Kind() == reflect.Pointer || body.Kind() == reflect.Interface) {
+		if body.IsNil() {
+			body = reflect.Value{}
+			break
+		}
+		body = body.Elem()
+	}
+
+	if !body.IsValid() {
+		if rules.required || rules.present {
+			return body, newErrReport(ResponseErr, schemaBody, "", "required", errors.New("value is required for body"))
+		}
+		return body, nil
+	}
+
+	if body.Kind() != reflect.Struct || rules.kind != reflect.Struct {
+		return reflect.Value{}, newErrReport(ResponseErr, schemaBody, "", "typeMismatch", errors.New(parser+" body must be a struct"))
+	}
+	return body, nil
+}
+
+// formFieldVisitor walks a schema struct using the same key conventions the form
+// decoders accept: nested structs as "parent.child", struct slices as
+// "parent.0.child" and primitive slices as repeated keys.
+type formFieldVisitor struct {
+	c    ParserContext
+	text func(key, value string) error
+	// file optionally writes val as a file part. It reports whether val was handled.
+	file func(key string, val reflect.Value) (bool, error)
+}
+
+func (v *formFieldVisitor) visitStruct(prefix string, val reflect.Value, rules *RuleDef) error {
+	for _, prop := range rules.orderedProps {
+		jsonTags := prop.tags["json"]
+		if slices.Contains(jsonTags, "-") {
+			continue
+		}
+
+		key := prop.field
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+
+		fv, err := val.FieldByIndexErr(prop.accessor.index)
+		if err != nil {
+			fv = reflect.Value{}
+		}
+
+		if slices.Contains(jsonTags, "omitempty") && (!fv.IsValid() || fv.IsZero()) {
+			continue
+		}
+
+		if err := v.visitField(key, fv, prop); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (v *formFieldVisitor) visitField(key string, val reflect.Value, rules *RuleDef) error {
+	var vany any
+	if val.IsValid() {
+		vany = val.Interface()
+	}
+	if err := runValidation(vany, ResponseErr, schemaBody, key, rules.rules); err != nil {
+		return err
+	}
+
+	if (!val.IsValid() || val.IsZero()) && rules.defStr != "" {
+		return v.text(key, rules.defaultText())
+	}
+
+	if !val.IsValid() {
+		return nil
+	}
+
+	if v.file != nil {
+		if handled, err := v.file(key, val); handled || err != nil {
+			return err
+		}
+	}
+
+	if _, ok := v.c.CustomSpecs().Find(string(rules.format)); ok {
+		return v.emitText(key, val, rules)
+	}
+
+	for val.Kind() == reflect.Pointer || val.Kind() == reflect.Interface {
+		if val.IsNil() {
+			return nil
+		}
+		val = val.Elem()
+	}
+
+	switch {
+	case val.Type() == utils.TimeType || utils.IsByteSlice(val.Type()):
+		return v.emitText(key, val, rules)
+
+	case val.Kind() == reflect.Struct:
+		return v.visitStruct(key, val, rules)
+
+	case val.Kind() == reflect.Slice || val.Kind() == reflect.Array:
+		item := rules.item
+		if item == nil {
+			item = getItemRuleDef(val.Type().Elem())
+		}
+
+		indexed := v.isNestedStruct(val.Type().Elem(), item)
+		for i := 0; i < val.Len(); i++ {
+			ikey := key
+			if indexed {
+				ikey = key + "." + strconv.Itoa(i)
+			}
+			if err := v.visitField(ikey, val.Index(i), item); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case val.Kind() == reflect.Map:
+		return newErrReport(ResponseErr, schemaBody, key, "typeMismatch", errors.New("map fields cannot be form encoded"))
+
+	default:
+		return v.emitText(key, val, rules)
+	}
+}
+
+func (v *formFieldVisitor) emitText(key string, val reflect.Value, rules *RuleDef) error {
+	s, err := encodeTextValue(v.c, val, rules)
+	if err != nil {
+		return newErrReport(ResponseErr, schemaBody, key, "encoder", err)
+	}
+	return v.text(key, s)
+}
+
+// isNestedStruct reports whether slice items of typ are encoded as indexed nested keys.
+func (v *formFieldVisitor) isNestedStruct(typ reflect.Type, item *RuleDef) bool {
+	if typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+	if typ.Kind() != reflect.Struct || typ == utils.TimeType || isMultipartFileType(typ) {
+		return false
+	}
+	_, isCustom := v.c.CustomSpecs().Find(string(item.format))
+	return !isCustom
 }
 
 func (f *FormBodyParser) getFieldStruct(strct reflect.Value, fieldname string) reflect.Value {
@@ -406,6 +603,3 @@ func (f *FormBodyParser) getFieldStruct(strct reflect.Value, fieldname string) r
 	}
 	return strct.FieldByName(fieldname)
 }
-
-// Helper function to suppress unused import warning
-var _ = bytes.NewReader
@@ -0,0 +1,81 @@
+package gofi
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type formAddress struct {
+	City string `json:"city" validate:"required"`
+	Zip  string `json:"zip" default:"00000"`
+}
+
+type formResponseSchema struct {
+	Ok struct {
+		Header struct {
+			ContentType string `json:"content-type" default:"application/x-www-form-urlencoded"`
+		}
+		Body struct {
+			Name     string        `json:"name" validate:"max=10"`
+			Tags     []string      `json:"tags"`
+			Joined   time.Time     `json:"joined" pattern:"2006-01-02"`
+			Address  formAddress   `json:"address"`
+			Previous []formAddress `json:"previous"`
+			Note     string        `json:"note,omitempty"`
+			Secret   string        `json:"-"`
+		}
+	}
+}
+
+func TestFormBodyParser_EncodesResponse(t *testing.T) {
+	mux := NewRouter()
+	handler := RouteOptions{
+		Schema: &formResponseSchema{},
+		Handler: func(c Context) error {
+			var s formResponseSchema
+			s.Ok.Body.Name = "ada"
+			s.Ok.Body.Tags = []string{"a", "b&c"}
+			s.Ok.Body.Joined = time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+			s.Ok.Body.Address = formAddress{City: "London"}
+			s.Ok.Body.Previous = []formAddress{{City: "Paris", Zip: "75001"}}
+			s.Ok.Body.Secret = "hidden"
+			return c.Send(200, s.Ok)
+		},
+	}
+
+	res, err := mux.Inject(InjectOptions{Path: "/form", Method: "GET", Handler: &handler})
+	assert.Nil(t, err)
+	assert.Equal(t, 200, res.StatusCode)
+	assert.Equal(t, "application/x-www-form-urlencoded", res.Header("Content-Type"))
+	assert.Equal(t,
+		"name=ada&tags=a&tags=b%26c&joined=2024-05-01&address.city=London&address.zip=00000&previous.0.city=Paris&previous.0.zip=75001",
+		string(res.Body),
+	)
+}
+
+func TestFormBodyParser_ValidatesResponse(t *testing.T) {
+	mux := NewRouter()
+	var sendErr error
+	handler := RouteOptions{
+		Schema: &formResponseSchema{},
+		Handler: func(c Context) error {
+			var s formResponseSchema
+			s.Ok.Body.Name = "ok"
+			s.Ok.Body.Address = formAddress{City: "London"}
+			s.Ok.Body.Previous = []formAddress{{City: "Paris"}, {}}
+			sendErr = c.Send(200, s.Ok)
+			return sendErr
+		},
+	}
+
+	res, err := mux.Inject(InjectOptions{Path: "/form", Method: "GET", Handler: &handler})
+	assert.Nil(t, err)
+	assert.Equal(t, 500, res.StatusCode)
+
+	var verr ValidationError
+	assert.ErrorAs(t, sendErr, &verr)
+	assert.Equal(t, "required", verr.Rule())
+	assert.Equal(t, "previous.1.city", verr.SchemaValue())
+}
@@ -154,26 +154,22 @@ func (j *JSONBodyParser) ValidateAndEncodeResponse(obj any, opts ResponseOptions
 }
 
 func (j *JSONBodyParser) walkStruct(node *fastjson.Value, schemaField schemaField, opts RequestOptions, keys []string) (*walkFinishStatus, error) {
-	if j.MaxDepth == 0 {
-		j.MaxDepth = 100
-	}
-	if len(keys) > j.MaxDepth {
-		return nil, newErrReport(RequestErr, schemaField, strings.Join(keys, "."), "depth", errors.New("max recursion depth exceeded"))
+	if err := j.checkDepth(schemaField, keys); err != nil {
+		return nil, err
 	}
 
-	val, err := cont.GetNodeByKind(node, opts.SchemaRules.kind, opts.SchemaRules.format)
-	if err != nil {
-		return nil, newErrReport(RequestErr, schemaField, strings.Join(keys, "."), "parser", err)
+	if opts.SchemaRules.isWrapper() {
+		return j.walkWrapped(node, schemaField, opts, keys)
 	}
 
-	if (val == nil || val == cont.EOF) && opts.SchemaRules.defVal != nil {
-		val = opts.SchemaRules.defVal
-	} else if val == cont.EOF {
-		val = nil
+	node, err := j.defaultNode(node, schemaField, opts.SchemaRules, keys)
+	if err != nil {
+		return nil, err
 	}
 
-	if !opts.SchemaRules.required && !opts.SchemaRules.present && val == nil {
-		return nil, nil
+	val, ok, err := j.nodeValue(node, schemaField, opts.SchemaRules, keys)
+	if err != nil || !ok {
+		return nil, err
 	}
 
 	if opts.ShouldBind && opts.Body != nil && opts.Body.Kind() == reflect.Pointer {
@@ -187,7 +183,7 @@ func (j *JSONBodyParser) walkStruct(node *fastjson.Value, schemaField schemaFiel
 
 		if opts.ShouldBind && opts.Body != nil {
 			if err = j.decodeFieldValue(opts.Body, val, opts.SchemaRules.pattern); err != nil {
-				return nil, newErrReport(RequestErr, schemaField, strings.Join(keys, "."), "decode", err)
+				return nil, newKeysReport(RequestErr, schemaField, keys, "decode", err)
 			}
 		}
 
@@ -198,7 +194,7 @@ func (j *JSONBodyParser) walkStruct(node *fastjson.Value, schemaField schemaFiel
 	if spec, ok := opts.Context.CustomSpecs().Find(string(opts.SchemaRules.format)); ok {
 		decoded, err := spec.Decode(val)
 		if err != nil {
-			return nil, newErrReport(RequestErr, schemaField, strings.Join(keys, "."), "typeCast", err)
+			return nil, newKeysReport(RequestErr, schemaField, keys, "typeCast", err)
 		}
 
 		if err := runValidationLazy(decoded, RequestErr, schemaField, keys, opts.SchemaRules.rules); err != nil {
@@ -207,7 +203,7 @@ func (j *JSONBodyParser) walkStruct(node *fastjson.Value, schemaField schemaFiel
 
 		if opts.ShouldBind && opts.Body != nil {
 			if err = j.decodeFieldValue(opts.Body, decoded, ""); err != nil {
-				return nil, newErrReport(RequestErr, schemaField, strings.Join(keys, "."), "decode", err)
+				return nil, newKeysReport(RequestErr, schemaField, keys, "decode", err)
 			}
 		}
 
@@ -218,7 +214,7 @@ func (j *JSONBodyParser) walkStruct(node *fastjson.Value, schemaField schemaFiel
 	if opts.SchemaRules.format == utils.RawJSONFormat {
 		raw, ok := val.([]byte)
 		if !ok {
-			return nil, newErrReport(RequestErr, schemaField, strings.Join(keys, "."), "parser", errors.New("expected raw JSON bytes"))
+			return nil, newKeysReport(RequestErr, schemaField, keys, "parser", errors.New("expected raw JSON bytes"))
 		}
 
 		if err := runValidationLazy(raw, RequestErr, schemaField, keys, opts.SchemaRules.rules); err != nil {
@@ -227,7 +223,7 @@ func (j *JSONBodyParser) walkStruct(node *fastjson.Value, schemaField schemaFiel
 
 		if opts.ShouldBind && opts.Body != nil {
 			if err := j.decodeRawJSONField(opts.Body, raw); err != nil {
-				return nil, newErrReport(RequestErr, schemaField, strings.Join(keys, "."), "decode", err)
+				return nil, newKeysReport(RequestErr, schemaField, keys, "decode", err)
 			}
 		}
 
@@ -238,12 +234,12 @@ func (j *JSONBodyParser) walkStruct(node *fastjson.Value, schemaField schemaFiel
 	if opts.SchemaRules.format == utils.ByteFormat {
 		raw, ok := val.([]byte)
 		if !ok {
-			return nil, newErrReport(RequestErr, schemaField, strings.Join(keys, "."), "parser", errors.New("expected raw JSON bytes"))
+			return nil, newKeysReport(RequestErr, schemaField, keys, "parser", errors.New("expected raw JSON bytes"))
 		}
 
 		decoded, err := decodeBase64Field(raw)
 		if err != nil {
-			return nil, newErrReport(RequestErr, schemaField, strings.Join(keys, "."), "decode", err)
+			return nil, newKeysReport(RequestErr, schemaField, keys, "decode", err)
 		}
 
 		if err := runValidationLazy(decoded, RequestErr, schemaField, keys, opts.SchemaRules.rules); err != nil {
@@ -265,6 +261,16 @@ func (j *JSONBodyParser) walkStruct(node *fastjson.Value, schemaField schemaFiel
 
 	switch opts.SchemaRules.kind {
 	case reflect.Struct:
+		if val == nil && !opts.SchemaRules.defaulted {
+			// A null or missing struct has no fields to walk, only its own rules to break.
+			return nil, runValidationLazy(nil, RequestErr, schemaField, keys, opts.SchemaRules.rules)
+		}
+		if opts.SchemaRules.strict {
+			if err := checkUnknownFields(node, opts.SchemaRules, schemaField, keys); err != nil {
+				return nil, err
+			}
+		}
+
 		// Fast path: use pre-ordered props slice when available
 		if len(opts.SchemaRules.orderedProps) > 0 {
 			for _, childDef := range opts.SchemaRules.orderedProps {
@@ -321,6 +327,10 @@ func (j *JSONBodyParser) walkStruct(node *fastjson.Value, schemaField schemaFiel
 			}
 		}
 
+		if err := validateCrossFields(opts.SchemaRules, RequestErr, schemaField, keys, jsonFieldLookup(node)); err != nil {
+			return nil, err
+		}
+
 		return &walkFinished, nil
 
 	case reflect.Map:
@@ -330,36 +340,31 @@ func (j *JSONBodyParser) walkStruct(node *fastjson.Value, schemaField schemaFiel
 
 		obj, err := node.Object()
 		if err != nil {
-			return nil, newErrReport(RequestErr, schemaField, strings.Join(keys, "."), "parser", err)
+			return nil, newKeysReport(RequestErr, schemaField, keys, "parser", err)
 		}
 
 		if opts.ShouldBind && opts.Body != nil {
 			opts.Body.Set(reflect.MakeMap(opts.Body.Type()))
 		}
 
-		var mapErr error
-		obj.Visit(func(key []byte, v *fastjson.Value) {
+		err = j.visitEntries(obj, schemaField, opts.SchemaRules, keys, func(ckey string, v *fastjson.Value, ckeys []string) error {
 			var cstrct reflect.Value
 			if opts.ShouldBind && opts.Body != nil {
 				cstrct = reflect.New(opts.Body.Type().Elem()).Elem()
 			}
 
-			ckey := string(key)
 			copts := j.getFieldOptions(opts, &cstrct, opts.SchemaRules.additionalProperties)
-			_, err := j.walkStruct(v, schemaBody, copts, append(keys, ckey))
-			if err != nil {
-				mapErr = err
-				return
+			if _, err := j.walkStruct(v, schemaBody, copts, ckeys); err != nil {
+				return err
 			}
 
 			if opts.ShouldBind && opts.Body != nil {
 				opts.Body.SetMapIndex(reflect.ValueOf(ckey), cstrct)
 			}
-
+			return nil
 		})
-
-		if mapErr != nil {
-			return nil, mapErr
+		if err != nil {
+			return nil, err
 		}
 
 		return &walkFinished, nil
@@ -373,33 +378,23 @@ func (j *JSONBodyParser) walkStruct(node *fastjson.Value, schemaField schemaFiel
 		rules := opts.SchemaRules
 
 		switch true {
-		case utils.IsPrimitiveKind(opts.SchemaRules.item.kind):
+		case rules.hasPrimitiveItems():
 			// Handle array of primitive values
-			arrNodes, err := node.Array()
+			arr, err := j.primitiveItems(node, schemaField, rules, keys)
 			if err != nil {
-				return nil, newErrReport(RequestErr, schemaField, strings.Join(keys, "."), "parser", err)
-			}
-			arr, err := cont.GetPrimitiveArrValsFromNode(arrNodes, rules.item.kind, rules.format, size)
-			if rules.max != nil && len(arr) > int(*rules.max) {
-				return nil, newErrReport(RequestErr, schemaField, strings.Join(keys, "."), "max", errors.New("array size too large"))
-			} else if err != nil {
-				return nil, newErrReport(RequestErr, schemaField, strings.Join(keys, "."), "parser", err)
-			}
-
-			if err := runValidationLazy(arr, RequestErr, schemaField, keys, opts.SchemaRules.rules); err != nil {
 				return nil, err
 			}
 
 			if opts.ShouldBind && opts.Body != nil {
 				if err = j.decodeFieldValue(opts.Body, arr, ""); err != nil {
-					return nil, newErrReport(RequestErr, schemaField, strings.Join(keys, "."), "decode", err)
+					return nil, newKeysReport(RequestErr, schemaField, keys, "decode", err)
 				}
 			}
 
 			return &walkFinished, nil
 
-		case utils.NotPrimitiveKind(opts.SchemaRules.item.kind):
-			// Handle array of Non primitives
+		default:
+			// Handle array of Non primitives (and of values decoded by a spec)
 			var nslice reflect.Value
 			if opts.ShouldBind && opts.Body != nil {
 				sliceType := opts.Body.Type()
@@ -409,17 +404,9 @@ func (j *JSONBodyParser) walkStruct(node *fastjson.Value, schemaField schemaFiel
 				nslice = reflect.MakeSlice(sliceType, 0, size)
 			}
 
-			arrNodes, err := node.Array()
+			arrNodes, err := j.itemNodes(node, schemaField, rules, keys)
 			if err != nil {
-				return nil, newErrReport(RequestErr, schemaField, strings.Join(keys, "."), "parser", err)
-			}
-
-			if len(arrNodes) == 0 && rules.required && !rules.present {
-				_keys := append(keys, "0")
-				return nil, newErrReport(RequestErr, schemaField, strings.Join(_keys, "."), "required", errors.New("value must not be empty"))
-			} else if rules.max != nil && len(arrNodes) > int(*rules.max) {
-				_keys := append(keys, strconv.Itoa(len(arrNodes)))
-				return nil, newErrReport(RequestErr, schemaField, strings.Join(_keys, "."), "max", fmt.Errorf("array length must not be greater than %f", *rules.max))
+				return nil, err
 			}
 
 			for i, childNode := range arrNodes {
@@ -466,7 +453,7 @@ func (j *JSONBodyParser) walkStruct(node *fastjson.Value, schemaField schemaFiel
 	case reflect.Interface:
 		v, err := cont.GetAnyValueFromNode(node)
 		if err != nil {
-			return nil, newErrReport(RequestErr, schemaField, strings.Join(keys, "."), "parser", err)
+			return nil, newKeysReport(RequestErr, schemaField, keys, "parser", err)
 		}
 
 		if err := runValidationLazy(v, RequestErr, schemaField, keys, opts.SchemaRules.rules); err != nil {
@@ -475,7 +462,7 @@ func (j *JSONBodyParser) walkStruct(node *fastjson.Value, schemaField schemaFiel
 
 		if opts.ShouldBind && opts.Body != nil {
 			if err = j.decodeFieldValue(opts.Body, v, ""); err != nil {
-				return nil, newErrReport(RequestErr, schemaField, strings.Join(keys, "."), "decode", err)
+				return nil, newKeysReport(RequestErr, schemaField, keys, "decode", err)
 			}
 		}
 
@@ -488,14 +475,164 @@ func (j *JSONBodyParser) walkStruct(node *fastjson.Value, schemaField schemaFiel
 
 		if opts.ShouldBind && opts.Body != nil {
 			if err = j.decodeFieldValue(opts.Body, val, opts.SchemaRules.pattern); err != nil {
-				return nil, newErrReport(RequestErr, schemaField, strings.Join(keys, "."), "decode", err)
+				return nil, newKeysReport(RequestErr, schemaField, keys, "decode", err)
 			}
 		}
 
 		return &walkFinished, nil
 	}
+}
+
+// walkWrapped binds an Optional or Nullable field. A missing field is left unset, and the rules
+// of the wrapped value only run on a value that was sent.
+func (j *JSONBodyParser) walkWrapped(node *fastjson.Value, schemaField schemaField, opts RequestOptions, keys []string) (*walkFinishStatus, error) {
+	if node == nil {
+		return nil, nil
+	}
+
+	null := node.Type() == fastjson.TypeNull
+	var inner reflect.Value
+	if opts.ShouldBind && opts.Body != nil && opts.Body.IsValid() {
+		inner = setWrapped(*opts.Body, null)
+	}
+	if null {
+		return &walkFinished, nil
+	}
+	return j.walkStruct(node, schemaField, j.getFieldOptions(opts, &inner, opts.SchemaRules.item), keys)
+}
+
+// checkDepth rejects values nested deeper than MaxDepth.
+func (j *JSONBodyParser) checkDepth(schemaField schemaField, keys []string) error {
+	if j.MaxDepth == 0 {
+		j.MaxDepth = 100
+	}
+	if len(keys) > j.MaxDepth {
+		return newKeysReport(RequestErr, schemaField, keys, "depth", errors.New("max recursion depth exceeded"))
+	}
+	return nil
+}
+
+// nodeValue reads node as the kind of def, applying the modifiers and the default. It returns
+// false when an optional value is missing.
+func (j *JSONBodyParser) nodeValue(node *fastjson.Value, schemaField schemaField, def *RuleDef, keys []string) (any, bool, error) {
+	val, err := cont.GetNodeByKind(node, def.kind, def.format)
+	if err != nil {
+		return nil, false, newKeysReport(RequestErr, schemaField, keys, "parser", err)
+	}
+	if str, ok := val.(string); ok && len(def.mods) > 0 {
+		val = def.modify(str)
+	}
+
+	if (val == nil || val == cont.EOF) && def.defVal != nil {
+		val = def.defVal
+	} else if val == cont.EOF {
+		val = nil
+	}
+
+	if !def.required && !def.present && val == nil {
+		// A missing struct whose fields have defaults is bound as if it were sent empty.
+		if def.defaulted && def.kind == reflect.Struct && def.typ.Kind() == reflect.Struct && def.format == "" {
+			return nil, true, nil
+		}
+		return nil, false, nil
+	}
+	if val == nil && def.format == "" && (def.kind == reflect.Slice || def.kind == reflect.Array || def.kind == reflect.Map) {
+		// A missing array or object has no items to walk, only its own rules to break.
+		return nil, false, runValidationLazy(nil, RequestErr, schemaField, keys, def.rules)
+	}
+	return val, true, nil
+}
+
+// defaultNode returns the JSON value of the default of def when node is missing or null.
+// Primitive defaults are left to nodeValue, which applies the value parsed at startup.
+func (j *JSONBodyParser) defaultNode(node *fastjson.Value, schemaField schemaField, def *RuleDef, keys []string) (*fastjson.Value, error) {
+	if !def.nodeDefault() || (node != nil && node.Type() != fastjson.TypeNull) {
+		return node, nil
+	}
 
-	return &walkFinished, nil
+	// Parsed values are not safe for concurrent use, so the default is parsed on every use.
+	v, err := fastjson.ParseBytes(def.defaultJSON(def.defaultText()))
+	if err != nil {
+		return nil, newKeysReport(RequestErr, schemaField, keys, "default", err)
+	}
+	return v, nil
+}
+
+// primitiveItems reads an array of primitive values and validates it and each of its items.
+func (j *JSONBodyParser) primitiveItems(node *fastjson.Value, schemaField schemaField, rules *RuleDef, keys []string) ([]any, error) {
+	size := DEFAULT_ARRAY_SIZE
+	if rules.max != nil {
+		size = int(*rules.max)
+	}
+
+	arrNodes, err := node.Array()
+	if err != nil {
+		return nil, newKeysReport(RequestErr, schemaField, keys, "parser", err)
+	}
+	arr, err := cont.GetPrimitiveArrValsFromNode(arrNodes, rules.item.kind, rules.format, size)
+	if rules.max != nil && len(arr) > int(*rules.max) {
+		return nil, newKeysReport(RequestErr, schemaField, keys, "max", errors.New("array size too large"))
+	} else if err != nil {
+		return nil, newKeysReport(RequestErr, schemaField, keys, "parser", err)
+	}
+
+	if err := runValidationLazy(arr, RequestErr, schemaField, keys, rules.rules); err != nil {
+		return nil, err
+	}
+
+	if len(rules.item.mods) > 0 {
+		for i, v := range arr {
+			if str, ok := v.(string); ok {
+				arr[i] = rules.item.modify(str)
+			}
+		}
+	}
+
+	if len(rules.item.rules) > 0 {
+		for i, v := range arr {
+			if err := runValidationLazy(v, RequestErr, schemaField, append(keys, strconv.Itoa(i)), rules.item.rules); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return arr, nil
+}
+
+// itemNodes returns the items of an array of non-primitive values after checking its length.
+func (j *JSONBodyParser) itemNodes(node *fastjson.Value, schemaField schemaField, rules *RuleDef, keys []string) ([]*fastjson.Value, error) {
+	arrNodes, err := node.Array()
+	if err != nil {
+		return nil, newKeysReport(RequestErr, schemaField, keys, "parser", err)
+	}
+
+	if len(arrNodes) == 0 && rules.required && !rules.present {
+		_keys := append(keys, "0")
+		return nil, newKeysReport(RequestErr, schemaField, _keys, "required", errors.New("value must not be empty"))
+	} else if rules.max != nil && len(arrNodes) > int(*rules.max) {
+		_keys := append(keys, strconv.Itoa(len(arrNodes)))
+		return nil, newKeysReport(RequestErr, schemaField, _keys, "max", fmt.Errorf("array length must not be greater than %f", *rules.max))
+	}
+	return arrNodes, nil
+}
+
+// visitEntries validates the keys of a JSON object against the map rules and calls fn with each
+// entry. Every entry is visited and the last failure is returned.
+func (j *JSONBodyParser) visitEntries(obj *fastjson.Object, schemaField schemaField, rules *RuleDef, keys []string, fn func(key string, v *fastjson.Value, keys []string) error) error {
+	var mapErr error
+	obj.Visit(func(key []byte, v *fastjson.Value) {
+		ckey := string(key)
+		if rules.keys != nil {
+			if err := runValidationLazy(ckey, RequestErr, schemaField, append(keys, ckey), rules.keys.rules); err != nil {
+				mapErr = err
+				return
+			}
+		}
+
+		if err := fn(ckey, v, append(keys, ckey)); err != nil {
+			mapErr = err
+		}
+	})
+	return mapErr
 }
 
 func (j *JSONBodyParser) decodeFieldValue(field *reflect.Value, val any, timeLayout string) error {
@@ -681,45 +818,47 @@ func (j *JSONBodyParser) getFieldOptions(opts RequestOptions, fieldStruct *refle
 	return rtn
 }
 
-func (j *JSONBodyParser) encodeFieldValue(c ParserContext, buf *bytes.Buffer, val reflect.Value, rules *RuleDef, kp []string) error {
-	isEmptyValue := func(v reflect.Value) bool {
-		switch v.Kind() {
-		case reflect.String:
-			return v.String() == ""
-		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-			return v.Int() == 0
-		case reflect.Bool:
-			return !v.Bool()
-		case reflect.Slice, reflect.Array, reflect.Map:
-			return v.Len() == 0
-		case reflect.Ptr, reflect.Interface:
-			return v.IsNil()
+// isEmptyJSONValue reports whether v is empty for omitempty and default tags.
+func isEmptyJSONValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.String:
+		return v.String() == ""
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return v.Len() == 0
+	case reflect.Ptr, reflect.Interface:
+		return v.IsNil()
+	default:
+		return false
+	}
+}
+
+// encodeJSONString writes s as a quoted JSON string.
+func encodeJSONString(b *bytes.Buffer, s string) {
+	b.WriteRune('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			b.WriteString(`\"`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
 		default:
-			return false
-		}
-	}
-
-	encodeString := func(b *bytes.Buffer, s string) {
-		b.WriteRune('"')
-		for _, r := range s {
-			switch r {
-			case '"':
-				b.WriteString(`\"`)
-			case '\\':
-				b.WriteString(`\\`)
-			case '\n':
-				b.WriteString(`\n`)
-			case '\r':
-				b.WriteString(`\r`)
-			case '\t':
-				b.WriteString(`\t`)
-			default:
-				b.WriteRune(r)
-			}
+			b.WriteRune(r)
 		}
-		b.WriteRune('"')
 	}
+	b.WriteRune('"')
+}
 
+func (j *JSONBodyParser) encodeFieldValue(c ParserContext, buf *bytes.Buffer, val reflect.Value, rules *RuleDef, kp []string) error {
 	// We need to define encodeArr and encodeMap recursing with method call j.encodeFieldValue
 	// BUT closures capturing method receiver? Yes.
 
@@ -753,9 +892,10 @@ func (j *JSONBodyParser) encodeFieldValue(c ParserContext, buf *bytes.Buffer, va
 	encodeMap = func(buf *bytes.Buffer, val reflect.Value, rules *RuleDef, kp []string) error {
 		buf.WriteString("{")
 
-		var mrules *RuleDef
+		var mrules, krules *RuleDef
 		if rules != nil {
 			mrules = rules.additionalProperties
+			krules = rules.keys
 		}
 
 		mr := val.MapRange()
@@ -767,10 +907,10 @@ func (j *JSONBodyParser) encodeFieldValue(c ParserContext, buf *bytes.Buffer, va
 			key := mr.Key()
 			keyStr, ok := key.Interface().(string)
 			if !ok {
-				return newErrReport(ResponseErr, schemaBody, strings.Join(kp, "."), "typeMismatch", errors.New("map key must be of type string"))
+				return newKeysReport(ResponseErr, schemaBody, kp, "typeMismatch", errors.New("map key must be of type string"))
 			}
 
-			if err := j.encodeFieldValue(c, buf, key, mrules, append(kp, keyStr)); err != nil {
+			if err := j.encodeFieldValue(c, buf, key, krules, append(kp, keyStr)); err != nil {
 				return err
 			}
 			buf.WriteString(":")
@@ -798,9 +938,12 @@ func (j *JSONBodyParser) encodeFieldValue(c ParserContext, buf *bytes.Buffer, va
 					fieldValue = val.FieldByName(frules.fieldName)
 				}
 
-				if (slices.Contains(frules.tags["json"], "omitempty") && isEmptyValue(fieldValue)) || slices.Contains(frules.tags["json"], "-") {
+				if (slices.Contains(frules.tags["json"], "omitempty") && isEmptyJSONValue(fieldValue)) || slices.Contains(frules.tags["json"], "-") {
 					continue
 				}
+				if frules.isWrapper() && !fieldValue.Field(1).Bool() {
+					continue // unset Optional and Nullable fields are left out
+				}
 
 				if !first {
 					buf.WriteByte(',')
@@ -832,7 +975,7 @@ func (j *JSONBodyParser) encodeFieldValue(c ParserContext, buf *bytes.Buffer, va
 					name = field.Name
 				}
 
-				if slices.Contains(jsonTags, "-") || (slices.Contains(jsonTags, "omitempty") && isEmptyValue(val.FieldByName(field.Name))) {
+				if slices.Contains(jsonTags, "-") || (slices.Contains(jsonTags, "omitempty") && isEmptyJSONValue(val.FieldByName(field.Name))) {
 					continue
 				}
 
@@ -853,16 +996,25 @@ func (j *JSONBodyParser) encodeFieldValue(c ParserContext, buf *bytes.Buffer, va
 		return nil
 	}
 
+	if rules != nil && rules.isWrapper() && val.IsValid() {
+		inner, ok := wrappedValue(val)
+		if !ok {
+			_, err := buf.WriteString("null")
+			return err
+		}
+		return j.encodeFieldValue(c, buf, inner, rules.item, kp)
+	}
+
 	var vIsValid bool
 	var vany any
 	if val.IsValid() {
-		if rules != nil && rules.defStr != "" && isEmptyValue(val) {
-			if val.CanAddr() {
-				val.Set(reflect.ValueOf(rules.defVal).Convert(val.Type()))
-			} else {
-				ptr := reflect.New(val.Type())
-				ptr.Elem().Set(reflect.ValueOf(rules.defVal).Convert(val.Type()))
-				val = ptr.Elem()
+		if rules != nil && rules.defStr != "" && isEmptyJSONValue(val) {
+			if dv, ok := rules.defaultValue(val.Type(), c.CustomSpecs()); ok {
+				if val.CanAddr() {
+					val.Set(dv)
+				} else {
+					val = dv
+				}
 			}
 		}
 
@@ -871,7 +1023,7 @@ func (j *JSONBodyParser) encodeFieldValue(c ParserContext, buf *bytes.Buffer, va
 	}
 
 	if rules != nil {
-		if err := runValidation(vany, ResponseErr, schemaBody, strings.Join(kp, "."), rules.rules); err != nil {
+		if err := runValidationLazy(vany, ResponseErr, schemaBody, kp, rules.rules); err != nil {
 			return err
 		}
 	}
@@ -889,13 +1041,13 @@ func (j *JSONBodyParser) encodeFieldValue(c ParserContext, buf *bytes.Buffer, va
 			if vIsValid {
 				v, err := spec.Encode(vany)
 				if err != nil {
-					return newErrReport(ResponseErr, schemaBody, strings.Join(kp, "."), "typeMismatch", err)
+					return newKeysReport(ResponseErr, schemaBody, kp, "typeMismatch", err)
 				}
-				encodeString(buf, v)
+				encodeJSONString(buf, v)
 				return nil
 
 			} else {
-				return newErrReport(ResponseErr, schemaBody, strings.Join(kp, "."), "typeMismatch", errors.New("could not cast given type to string"))
+				return newKeysReport(ResponseErr, schemaBody, kp, "typeMismatch", errors.New("could not cast given type to string"))
 			}
 		}
 	}
@@ -913,7 +1065,7 @@ func (j *JSONBodyParser) encodeFieldValue(c ParserContext, buf *bytes.Buffer, va
 			}
 			raw, err := m.MarshalJSON()
 			if err != nil {
-				return newErrReport(ResponseErr, schemaBody, strings.Join(kp, "."), "typeMismatch", err)
+				return newKeysReport(ResponseErr, schemaBody, kp, "typeMismatch", err)
 			}
 			buf.Write(raw) // already valid JSON
 			return nil
@@ -925,7 +1077,7 @@ func (j *JSONBodyParser) encodeFieldValue(c ParserContext, buf *bytes.Buffer, va
 			_, err := buf.WriteString("null")
 			return err
 		}
-		encodeString(buf, base64.StdEncoding.EncodeToString(val.Bytes()))
+		encodeJSONString(buf, base64.StdEncoding.EncodeToString(val.Bytes()))
 		return nil
 	}
 
@@ -947,7 +1099,7 @@ func (j *JSONBodyParser) encodeFieldValue(c ParserContext, buf *bytes.Buffer, va
 		}
 		return j.encodeFieldValue(c, buf, val.Elem(), rules, kp)
 	case reflect.String:
-		encodeString(buf, val.String())
+		encodeJSONString(buf, val.String())
 		return nil
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
 		b := strconv.AppendInt(buf.AvailableBuffer(), val.Int(), 10)
@@ -972,10 +1124,10 @@ func (j *JSONBodyParser) encodeFieldValue(c ParserContext, buf *bytes.Buffer, va
 	case reflect.Struct:
 		if rules.format == utils.TimeObjectFormat {
 			if v, ok := (vany).(time.Time); ok {
-				encodeString(buf, v.Format(rules.pattern))
+				encodeJSONString(buf, v.Format(rules.pattern))
 				return nil
 			} else {
-				return newErrReport(ResponseErr, schemaBody, strings.Join(kp, "."), "typeMismatch", errors.New("cannot cast time field to string"))
+				return newKeysReport(ResponseErr, schemaBody, kp, "typeMismatch", errors.New("cannot cast time field to string"))
 			}
 		} else {
 			return encodeStruct(buf, val, rules, kp)
@@ -1,11 +1,13 @@
 package gofi
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"io"
 	"mime"
 	"mime/multipart"
+	"net/textproto"
 	"reflect"
 	"strings"
 	"time"
@@ -17,8 +19,27 @@ type MultipartBodyParser struct {
 	MaxRequestSize int64
 }
 
+// FilePart is a file written into a multipart response body.
+// Fields of type FilePart, *FilePart, *multipart.FileHeader, []byte or io.Reader
+// (and slices of them) are encoded as file parts by MultipartBodyParser.
+type FilePart struct {
+	Filename    string
+	ContentType string
+	Content     io.Reader
+}
+
 // multipart file-field classification helpers
 var fileHeaderPtrType = reflect.TypeOf(&multipart.FileHeader{})
+var filePartType = reflect.TypeOf(FilePart{})
+var readerType = reflect.TypeOf((*io.Reader)(nil)).Elem()
+
+// isMultipartFileType reports whether typ is documented and encoded as a binary file part.
+func isMultipartFileType(typ reflect.Type) bool {
+	if typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+	return typ == utils.MultipartFile || typ == filePartType || (typ.Kind() == reflect.Interface && typ.Implements(readerType))
+}
 
 func isSingleFileField(fieldVal reflect.Value) bool {
 	return fieldVal.IsValid() && fieldVal.Type() == fileHeaderPtrType
@@ -40,7 +61,7 @@ func (m *MultipartBodyParser) Match(contentType string) bool {
 	if err != nil {
 		return false
 	}
-	return mediaType == "multipart/form-data"
+	return mediaType == "multipart/form-data" || mediaType == "multipart/mixed"
 }
 
 func (m *MultipartBodyParser) ValidateAndDecodeRequest(r io.ReadCloser, opts RequestOptions) error {
@@ -60,6 +81,8 @@ func (m *MultipartBodyParser) ValidateAndDecodeRequest(r io.ReadCloser, opts Req
 	if err != nil {
 		return newErrReport(RequestErr, schemaBody, "", "parser", err)
 	}
+	normalizeFormKeys(form.Value)
+	normalizeFormKeys(form.File)
 
 	if opts.SchemaRules == nil {
 		return nil
@@ -68,6 +91,7 @@ func (m *MultipartBodyParser) ValidateAndDecodeRequest(r io.ReadCloser, opts Req
 	if opts.SchemaRules.kind != reflect.Struct {
 		return newErrReport(RequestErr, schemaBody, "", "typeMismatch", errors.New("multipart body must be a struct"))
 	}
+	applyFormDefaults(form.Value, opts.SchemaRules, "")
 
 	if opts.ShouldBind && opts.Body != nil {
 		bodyStruct := m.getFieldStruct(*opts.Body, string(schemaBody))
@@ -76,7 +100,8 @@ func (m *MultipartBodyParser) ValidateAndDecodeRequest(r io.ReadCloser, opts Req
 		}
 		// Capture custom specs for use in parseVal closures
 		customSpecs := opts.Context.CustomSpecs()
-		for key, rule := range opts.SchemaRules.properties {
+		for _, rule := range opts.SchemaRules.orderedProps {
+			key := rule.field
 			// Check form values first
 			vals, ok := form.Value[key]
 			// If not in values, check files
@@ -155,6 +180,7 @@ func (m *MultipartBodyParser) ValidateAndDecodeRequest(r io.ReadCloser, opts Req
 			}
 
 			parseVal := func(v string, r *RuleDef) (any, error) {
+				v = r.modify(v)
 				if r.format == utils.TimeObjectFormat {
 					return time.Parse(r.pattern, v)
 				}
@@ -171,7 +197,7 @@ func (m *MultipartBodyParser) ValidateAndDecodeRequest(r io.ReadCloser, opts Req
 					elemRule = &RuleDef{kind: fieldVal.Type().Elem().Kind()}
 				}
 
-				if elemRule.kind == reflect.Struct {
+				if _, isCustom := customSpecs.Find(string(elemRule.format)); elemRule.kind == reflect.Struct && !isCustom {
 					i := 0
 					var nslice reflect.Value
 					sliceType := fieldVal.Type()
@@ -295,6 +321,10 @@ func (m *MultipartBodyParser) ValidateAndDecodeRequest(r io.ReadCloser, opts Req
 				}
 			}
 		}
+
+		if err := validateCrossFields(opts.SchemaRules, RequestErr, schemaBody, nil, structFieldLookup(bodyStruct)); err != nil {
+			return err
+		}
 	}
 
 	return nil
@@ -328,7 +358,8 @@ func (m *MultipartBodyParser) bindStruct(form map[string][]string, dest reflect.
 	if dest.Kind() == reflect.Pointer {
 		dest = dest.Elem()
 	}
-	for key, rule := range rules.properties {
+	for _, rule := range rules.orderedProps {
+		key := rule.field
 		vals, ok := form[key]
 		if !ok {
 			if rule.kind == reflect.Slice || rule.kind == reflect.Array || rule.kind == reflect.Struct {
@@ -347,6 +378,7 @@ func (m *MultipartBodyParser) bindStruct(form map[string][]string, dest reflect.
 		}
 
 		parseVal := func(v string, r *RuleDef) (any, error) {
+			v = r.modify(v)
 			if r.format == utils.TimeObjectFormat {
 				return time.Parse(r.pattern, v)
 			}
@@ -359,7 +391,7 @@ func (m *MultipartBodyParser) bindStruct(form map[string][]string, dest reflect.
 
 		if rule.kind == reflect.Slice || rule.kind == reflect.Array {
 			elemRule := rule.item
-			if elemRule.kind == reflect.Struct {
+			if _, isCustom := customSpecs.Find(string(elemRule.format)); elemRule.kind == reflect.Struct && !isCustom {
 				i := 0
 				var nslice reflect.Value
 				sliceType := fieldVal.Type()
@@ -459,7 +491,119 @@ func (m *MultipartBodyParser) bindStruct(form map[string][]string, dest reflect.
 }
 
 func (m *MultipartBodyParser) ValidateAndEncodeResponse(s any, opts ResponseOptions) ([]byte, error) {
-	return nil, errors.New("multipart body parser does not support response encoding")
+	body, err := formResponseBody(opts, "multipart")
+	if err != nil {
+		return nil, err
+	}
+
+	mediaType := "multipart/form-data"
+	if mt, _, err := mime.ParseMediaType(opts.ContentType); err == nil && strings.HasPrefix(mt, "multipart/") {
+		mediaType = mt
+	}
+	mixed := mediaType != "multipart/form-data"
+
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+
+	visitor := formFieldVisitor{
+		c: opts.Context,
+		text: func(key, value string) error {
+			h := make(textproto.MIMEHeader)
+			if mixed {
+				h.Set("Content-Disposition", fmt.Sprintf(`inline; name="%s"`, multipartQuoteEscaper.Replace(key)))
+				h.Set("Content-Type", "text/plain; charset=utf-8")
+			} else {
+				h.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"`, multipartQuoteEscaper.Replace(key)))
+			}
+			pw, err := mw.CreatePart(h)
+			if err != nil {
+				return err
+			}
+			_, err = io.WriteString(pw, value)
+			return err
+		},
+		file: func(key string, val reflect.Value) (bool, error) {
+			return m.writeFilePart(mw, mixed, key, val)
+		},
+	}
+
+	if body.IsValid() {
+		if err := visitor.visitStruct("", body, opts.SchemaRules); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := mw.Close(); err != nil {
+		return nil, newErrReport(ResponseErr, schemaBody, "", "encoder", err)
+	}
+
+	opts.Context.SetContentType(mime.FormatMediaType(mediaType, map[string]string{"boundary": mw.Boundary()}))
+	return buf.Bytes(), nil
+}
+
+var multipartQuoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+// writeFilePart writes val as a file part when it holds one of the supported file types.
+func (m *MultipartBodyParser) writeFilePart(mw *multipart.Writer, mixed bool, key string, val reflect.Value) (bool, error) {
+	var filename, contentType string
+	var content io.Reader
+
+	switch v := val.Interface().(type) {
+	case *multipart.FileHeader:
+		if v == nil {
+			return true, nil
+		}
+		f, err := v.Open()
+		if err != nil {
+			return true, newErrReport(ResponseErr, schemaBody, key, "encoder", err)
+		}
+		defer f.Close()
+		filename, contentType, content = v.Filename, v.Header.Get("Content-Type"), f
+	case FilePart:
+		filename, contentType, content = v.Filename, v.ContentType, v.Content
+	case *FilePart:
+		if v == nil {
+			return true, nil
+		}
+		filename, contentType, content = v.Filename, v.ContentType, v.Content
+	case []byte:
+		if v == nil {
+			return true, nil
+		}
+		content = bytes.NewReader(v)
+	case io.Reader:
+		content = v
+	default:
+		return false, nil
+	}
+
+	if content == nil {
+		return true, nil
+	}
+	if filename == "" {
+		filename = key
+	}
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	disposition := "form-data"
+	if mixed {
+		disposition = "attachment"
+	}
+
+	h := make(textproto.MIMEHeader)
+	h.Set("Content-Disposition", fmt.Sprintf(`%s; name="%s"; filename="%s"`, disposition, multipartQuoteEscaper.Replace(key), multipartQuoteEscaper.Replace(filename)))
+	h.Set("Content-Type", contentType)
+
+	pw, err := mw.CreatePart(h)
+	if err != nil {
+		return true, newErrReport(ResponseErr, schemaBody, key, "encoder", err)
+	}
+	if _, err := io.Copy(pw, content); err != nil {
+		return true, newErrReport(ResponseErr, schemaBody, key, "encoder", err)
+	}
+	return true, nil
 }
 
 func (m *MultipartBodyParser) getFieldStruct(strct reflect.Value, fieldname string) reflect.Value {
@@ -0,0 +1,130 @@
+package gofi
+
+import (
+	"bytes"
+	"io"
+	"mime"
+	"mime/multipart"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type multipartPart struct {
+	name        string
+	filename    string
+	contentType string
+	disposition string
+	body        string
+}
+
+func readMultipartResponse(t *testing.T, res *InjectResponse) (string, []multipartPart) {
+	t.Helper()
+	mediaType, params, err := mime.ParseMediaType(res.Header("Content-Type"))
+	assert.Nil(t, err)
+	assert.NotEmpty(t, params["boundary"])
+
+	parts := []multipartPart{}
+	mr := multipart.NewReader(bytes.NewReader(res.Body), params["boundary"])
+	for {
+		p, err := mr.NextPart()
+		if err == io.EOF {
+			break
+		}
+		assert.Nil(t, err)
+		b, _ := io.ReadAll(p)
+		disposition, dparams, _ := mime.ParseMediaType(p.Header.Get("Content-Disposition"))
+		parts = append(parts, multipartPart{
+			name:        dparams["name"],
+			filename:    p.FileName(),
+			contentType: p.Header.Get("Content-Type"),
+			disposition: disposition,
+			body:        string(b),
+		})
+	}
+	return mediaType, parts
+}
+
+func TestMultipartBodyParser_EncodesFormData(t *testing.T) {
+	type schema struct {
+		Ok struct {
+			Header struct {
+				ContentType string `json:"content-type" default:"multipart/form-data"`
+			}
+			Body struct {
+				Title  string    `json:"title" validate:"required"`
+				Labels []string  `json:"labels"`
+				Avatar FilePart  `json:"avatar"`
+				Raw    io.Reader `json:"raw"`
+			}
+		}
+	}
+
+	mux := NewRouter()
+	handler := RouteOptions{
+		Schema: &schema{},
+		Handler: func(c Context) error {
+			var s schema
+			s.Ok.Body.Title = "report"
+			s.Ok.Body.Labels = []string{"x", "y"}
+			s.Ok.Body.Avatar = FilePart{Filename: "me.png", ContentType: "image/png", Content: strings.NewReader("png-bytes")}
+			s.Ok.Body.Raw = strings.NewReader("raw-bytes")
+			return c.Send(200, s.Ok)
+		},
+	}
+
+	res, err := mux.Inject(InjectOptions{Path: "/upload", Method: "GET", Handler: &handler})
+	assert.Nil(t, err)
+	assert.Equal(t, 200, res.StatusCode)
+
+	mediaType, parts := readMultipartResponse(t, res)
+	assert.Equal(t, "multipart/form-data", mediaType)
+	assert.Equal(t, []multipartPart{
+		{name: "title", disposition: "form-data", body: "report"},
+		{name: "labels", disposition: "form-data", body: "x"},
+		{name: "labels", disposition: "form-data", body: "y"},
+		{name: "avatar", filename: "me.png", contentType: "image/png", disposition: "form-data", body: "png-bytes"},
+		{name: "raw", filename: "raw", contentType: "application/octet-stream", disposition: "form-data", body: "raw-bytes"},
+	}, parts)
+}
+
+func TestMultipartBodyParser_EncodesMixed(t *testing.T) {
+	type schema struct {
+		Ok struct {
+			Header struct {
+				ContentType string `json:"content-type" default:"multipart/mixed"`
+			}
+			Body struct {
+				Summary string      `json:"summary"`
+				Files   []*FilePart `json:"files"`
+			}
+		}
+	}
+
+	mux := NewRouter()
+	handler := RouteOptions{
+		Schema: &schema{},
+		Handler: func(c Context) error {
+			var s schema
+			s.Ok.Body.Summary = "two files"
+			s.Ok.Body.Files = []*FilePart{
+				{Filename: "a.txt", ContentType: "text/plain", Content: strings.NewReader("A")},
+				{Filename: "b.txt", Content: strings.NewReader("B")},
+			}
+			return c.Send(200, s.Ok)
+		},
+	}
+
+	res, err := mux.Inject(InjectOptions{Path: "/bundle", Method: "GET", Handler: &handler})
+	assert.Nil(t, err)
+	assert.Equal(t, 200, res.StatusCode)
+
+	mediaType, parts := readMultipartResponse(t, res)
+	assert.Equal(t, "multipart/mixed", mediaType)
+	assert.Equal(t, []multipartPart{
+		{name: "summary", contentType: "text/plain; charset=utf-8", disposition: "inline", body: "two files"},
+		{name: "files", filename: "a.txt", contentType: "text/plain", disposition: "attachment", body: "A"},
+		{name: "files", filename: "b.txt", contentType: "application/octet-stream", disposition: "attachment", body: "B"},
+	}, parts)
+}
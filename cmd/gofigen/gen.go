@@ -0,0 +1,670 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+type nodeKind int
+
+const (
+	reflectNode nodeKind = iota // left to the reflective path of gofi
+	scalarNode
+	pointerNode
+	structNode
+	sliceNode
+	mapNode
+)
+
+// node is the shape of a schema field as far as the generated code is concerned.
+type node struct {
+	kind nodeKind
+	// rkind is the reflect.Kind gofi compiles the field to. Pointers take the kind of their
+	// element.
+	rkind string
+	// family names the gofi helpers of a scalar: String, Bool, Int, Uint or Float.
+	family string
+	elem   *node
+	fields []field
+}
+
+type field struct {
+	name string
+	node *node
+}
+
+var reflected = &node{kind: reflectNode, rkind: "reflect"}
+
+var basics = map[string]*node{
+	"string":  {kind: scalarNode, rkind: "string", family: "String"},
+	"bool":    {kind: scalarNode, rkind: "bool", family: "Bool"},
+	"int":     {kind: scalarNode, rkind: "int", family: "Int"},
+	"int8":    {kind: scalarNode, rkind: "int8", family: "Int"},
+	"int16":   {kind: scalarNode, rkind: "int16", family: "Int"},
+	"int32":   {kind: scalarNode, rkind: "int32", family: "Int"},
+	"rune":    {kind: scalarNode, rkind: "int32", family: "Int"},
+	"int64":   {kind: scalarNode, rkind: "int64", family: "Int"},
+	"uint":    {kind: scalarNode, rkind: "uint", family: "Uint"},
+	"uint8":   {kind: scalarNode, rkind: "uint8", family: "Uint"},
+	"byte":    {kind: scalarNode, rkind: "uint8", family: "Uint"},
+	"uint16":  {kind: scalarNode, rkind: "uint16", family: "Uint"},
+	"uint32":  {kind: scalarNode, rkind: "uint32", family: "Uint"},
+	"uint64":  {kind: scalarNode, rkind: "uint64", family: "Uint"},
+	"float32": {kind: scalarNode, rkind: "float32", family: "Float"},
+	"float64": {kind: scalarNode, rkind: "float64", family: "Float"},
+}
+
+// marshalers are the methods that make encoding/json, and so gofi, treat a type specially.
+var marshalers = []string{"MarshalJSON", "UnmarshalJSON", "MarshalText", "UnmarshalText"}
+
+// pkg is the parsed package the schemas are declared in.
+type pkg struct {
+	name  string
+	fset  *token.FileSet
+	types map[string]*ast.TypeSpec
+	files map[string]*ast.File
+	// marshaled holds the types with one of the marshalers methods.
+	marshaled map[string]bool
+}
+
+func loadPackage(dir string, skip string) (*pkg, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &pkg{
+		fset:      token.NewFileSet(),
+		types:     make(map[string]*ast.TypeSpec),
+		files:     make(map[string]*ast.File),
+		marshaled: make(map[string]bool),
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") || name == skip {
+			continue
+		}
+		file, err := parser.ParseFile(p.fset, filepath.Join(dir, name), nil, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+		p.name = file.Name.Name
+
+		for _, decl := range file.Decls {
+			switch d := decl.(type) {
+			case *ast.GenDecl:
+				for _, spec := range d.Specs {
+					if ts, ok := spec.(*ast.TypeSpec); ok {
+						p.types[ts.Name.Name] = ts
+						p.files[ts.Name.Name] = file
+					}
+				}
+			case *ast.FuncDecl:
+				if d.Recv != nil && len(d.Recv.List) == 1 && slices.Contains(marshalers, d.Name.Name) {
+					p.marshaled[receiverName(d.Recv.List[0].Type)] = true
+				}
+			}
+		}
+	}
+	if p.name == "" {
+		return nil, fmt.Errorf("no Go files in %s", dir)
+	}
+	return p, nil
+}
+
+func receiverName(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.StarExpr:
+		return receiverName(t.X)
+	case *ast.IndexExpr:
+		return receiverName(t.X)
+	case *ast.IndexListExpr:
+		return receiverName(t.X)
+	case *ast.Ident:
+		return t.Name
+	}
+	return ""
+}
+
+// nodeOf works out how the generated code handles a field of type expr. visiting holds the
+// named types being expanded, so recursive types are left to reflection.
+func (p *pkg) nodeOf(expr ast.Expr, visiting map[string]bool) *node {
+	switch t := expr.(type) {
+	case *ast.ParenExpr:
+		return p.nodeOf(t.X, visiting)
+
+	case *ast.Ident:
+		spec, ok := p.types[t.Name]
+		if !ok {
+			if n, ok := basics[t.Name]; ok {
+				return n
+			}
+			return reflected
+		}
+		if spec.Assign.IsValid() || spec.TypeParams != nil || p.marshaled[t.Name] || visiting[t.Name] {
+			return reflected
+		}
+		switch u := spec.Type.(type) {
+		case *ast.Ident:
+			if _, shadowed := p.types[u.Name]; !shadowed {
+				if n, ok := basics[u.Name]; ok {
+					return n
+				}
+			}
+		case *ast.StructType:
+			visiting[t.Name] = true
+			defer delete(visiting, t.Name)
+			return p.structNode(u, visiting)
+		}
+		return reflected
+
+	case *ast.StructType:
+		return p.structNode(t, visiting)
+
+	case *ast.StarExpr:
+		elem := p.nodeOf(t.X, visiting)
+		if elem.kind != scalarNode && elem.kind != structNode {
+			return reflected
+		}
+		return &node{kind: pointerNode, rkind: elem.rkind, elem: elem}
+
+	case *ast.ArrayType:
+		if t.Len != nil {
+			return reflected
+		}
+		elem := p.nodeOf(t.Elt, visiting)
+		if elem.rkind == "uint8" {
+			return reflected // []byte is sent as base64
+		}
+		return &node{kind: sliceNode, rkind: "slice", elem: elem}
+
+	case *ast.MapType:
+		if key, ok := t.Key.(*ast.Ident); !ok || key.Name != "string" || p.types["string"] != nil {
+			return reflected
+		}
+		return &node{kind: mapNode, rkind: "map", elem: p.nodeOf(t.Value, visiting)}
+	}
+	return reflected
+}
+
+func (p *pkg) structNode(st *ast.StructType, visiting map[string]bool) *node {
+	n := &node{kind: structNode, rkind: "struct"}
+	for _, f := range st.Fields.List {
+		if len(f.Names) == 0 {
+			return reflected // embedded fields are promoted by gofi
+		}
+		skip := jsonName(f) == "-"
+		for _, name := range f.Names {
+			if skip {
+				continue
+			}
+			if !name.IsExported() {
+				return reflected
+			}
+			n.fields = append(n.fields, field{name: name.Name, node: p.nodeOf(f.Type, visiting)})
+		}
+	}
+	return n
+}
+
+// structOf returns the struct type expr stands for, if it is one.
+func (p *pkg) structOf(expr ast.Expr) *ast.StructType {
+	switch t := expr.(type) {
+	case *ast.StructType:
+		return t
+	case *ast.Ident:
+		if spec, ok := p.types[t.Name]; ok && !spec.Assign.IsValid() && spec.TypeParams == nil {
+			return p.structOf(spec.Type)
+		}
+	}
+	return nil
+}
+
+func jsonName(f *ast.Field) string {
+	if f.Tag == nil {
+		return ""
+	}
+	tag, err := strconv.Unquote(f.Tag.Value)
+	if err != nil {
+		return ""
+	}
+	name, _, _ := strings.Cut(reflect.StructTag(tag).Get("json"), ",")
+	return name
+}
+
+// fieldOf returns the field called name of st.
+func fieldOf(st *ast.StructType, name string) *ast.Field {
+	for _, f := range st.Fields.List {
+		for _, n := range f.Names {
+			if n.Name == name {
+				return f
+			}
+		}
+	}
+	return nil
+}
+
+// generator writes the code of the schemas of a package.
+type generator struct {
+	pkg     *pkg
+	out     bytes.Buffer
+	imports map[string]string
+	strconv bool
+
+	// The state of the schema being written.
+	schema string
+	file   *ast.File
+	fields []string
+	buf    *bytes.Buffer
+	vars   int
+}
+
+func generate(dir string, output string, names []string) ([]byte, error) {
+	p, err := loadPackage(dir, output)
+	if err != nil {
+		return nil, err
+	}
+
+	g := &generator{pkg: p, imports: map[string]string{"github.com/michaelolof/gofi": "gofi"}}
+	for _, name := range names {
+		if err := g.generateSchema(name); err != nil {
+			return nil, err
+		}
+	}
+
+	var src bytes.Buffer
+	src.WriteString("// Code generated by gofigen. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&src, "package %s\n\nimport (\n", p.name)
+	if g.strconv {
+		src.WriteString("\t\"strconv\"\n\n")
+	}
+	paths := make([]string, 0, len(g.imports))
+	for path := range g.imports {
+		paths = append(paths, path)
+	}
+	slices.Sort(paths)
+	for _, ip := range paths {
+		if name := g.imports[ip]; name != importBase(ip) {
+			fmt.Fprintf(&src, "\t%s %q\n", name, ip)
+		} else {
+			fmt.Fprintf(&src, "\t%q\n", ip)
+		}
+	}
+	src.WriteString(")\n")
+	src.Write(g.out.Bytes())
+
+	out, err := format.Source(src.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("formatting generated code: %w\n%s", err, src.Bytes())
+	}
+	return out, nil
+}
+
+func importBase(ip string) string {
+	base := path.Base(ip)
+	if len(base) > 1 && base[0] == 'v' && strings.Trim(base[1:], "0123456789") == "" {
+		base = path.Base(path.Dir(ip)) // major version suffix
+	}
+	base, _, _ = strings.Cut(base, ".")
+	return strings.ReplaceAll(base, "-", "_")
+}
+
+func (g *generator) printf(format string, args ...any) {
+	fmt.Fprintf(g.buf, format, args...)
+}
+
+func (g *generator) newVar() int {
+	g.vars++
+	return g.vars
+}
+
+// add lists a field in GofiFields and returns its index.
+func (g *generator) add(path string, n *node) int {
+	g.fields = append(g.fields, path+" "+n.rkind)
+	return len(g.fields) - 1
+}
+
+// typeString prints the type expr of the schema file, importing the packages it refers to.
+func (g *generator) typeString(expr ast.Expr) (string, error) {
+	var err error
+	ast.Inspect(expr, func(n ast.Node) bool {
+		sel, ok := n.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		if id, ok := sel.X.(*ast.Ident); ok && err == nil {
+			err = g.importName(id.Name)
+		}
+		return false
+	})
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	if err := printer.Fprint(&buf, g.pkg.fset, expr); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+func (g *generator) importName(name string) error {
+	for _, spec := range g.file.Imports {
+		ip, _ := strconv.Unquote(spec.Path.Value)
+		if (spec.Name != nil && spec.Name.Name == name) || (spec.Name == nil && importBase(ip) == name) {
+			if used, ok := g.imports[ip]; ok && used != name {
+				return fmt.Errorf("package %s is imported as both %s and %s", ip, used, name)
+			}
+			g.imports[ip] = name
+			return nil
+		}
+	}
+	return fmt.Errorf("cannot find the import of %s", name)
+}
+
+func (g *generator) generateSchema(name string) error {
+	spec, ok := g.pkg.types[name]
+	if !ok {
+		return fmt.Errorf("type %s not found", name)
+	}
+	st, ok := spec.Type.(*ast.StructType)
+	if !ok || spec.TypeParams != nil {
+		return fmt.Errorf("%s is not a struct type", name)
+	}
+	g.schema, g.file, g.fields, g.vars = name, g.pkg.files[name], nil, 0
+
+	var bind, body, encode, responses bytes.Buffer
+
+	// Request parameters and body.
+	var req *ast.StructType
+	if f := fieldOf(st, "Request"); f != nil {
+		req = g.pkg.structOf(f.Type)
+	}
+
+	g.buf = &bind
+	g.printf("func (v *%s) GofiBind(b *gofi.GenBinder) {\n", name)
+	if req != nil {
+		for _, part := range []string{"Header", "Query", "Path", "Cookie"} {
+			f := fieldOf(req, part)
+			if f == nil {
+				continue
+			}
+			if ps := g.pkg.structOf(f.Type); ps != nil {
+				g.bindParams(part, ps)
+			}
+		}
+		if f := fieldOf(req, "Body"); f != nil && jsonName(f) == "" {
+			if n := g.pkg.nodeOf(f.Type, map[string]bool{}); n.kind == structNode {
+				g.buf = &body
+				g.bindBody(n)
+				g.buf = &bind
+				g.printf("b.BodyDone(gofiBind%sBody(b, v))\n", name)
+			}
+		}
+	}
+	g.printf("}\n\n")
+
+	// Response bodies.
+	g.buf = &responses
+	var cases bytes.Buffer
+	for _, f := range st.Fields.List {
+		for _, fname := range f.Names {
+			if !fname.IsExported() || fname.Name == "Request" || fname.Name == "WebSocket" {
+				continue
+			}
+			rs := g.pkg.structOf(f.Type)
+			if rs == nil {
+				continue
+			}
+			bf := fieldOf(rs, "Body")
+			if bf == nil || jsonName(bf) != "" {
+				continue
+			}
+			n := g.pkg.nodeOf(bf.Type, map[string]bool{})
+			if n.kind != structNode {
+				continue
+			}
+			typ, err := g.typeString(f.Type)
+			if err != nil {
+				return fmt.Errorf("%s.%s: %w", name, fname.Name, err)
+			}
+
+			fn := "gofiEncode" + name + fname.Name
+			fmt.Fprintf(&cases, "case %q:\nif r, ok := gofi.GenResponse[%s](obj); ok {\nreturn true, %s(e, r)\n}\n", fname.Name, typ, fn)
+			g.printf("func %s(e *gofi.GenEncoder, r *%s) error {\n", fn, typ)
+			g.printf("p0 := &r.Body\nvar kp0 []string\n")
+			g.encodeNode(n, fname.Name+".Body", "p0", "kp0")
+			g.printf("return nil\n}\n\n")
+		}
+	}
+
+	g.buf = &encode
+	g.printf("func (*%s) GofiEncode(e *gofi.GenEncoder, key string, obj any) (bool, error) {\n", name)
+	if cases.Len() > 0 {
+		g.printf("switch key {\n%s}\n", cases.Bytes())
+	}
+	g.printf("return false, nil\n}\n\n")
+
+	fmt.Fprintf(&g.out, "\nvar gofiFields%s = []string{\n", name)
+	for _, f := range g.fields {
+		fmt.Fprintf(&g.out, "%q,\n", f)
+	}
+	fmt.Fprintf(&g.out, "}\n\nfunc (*%s) GofiFields() []string {\nreturn gofiFields%s\n}\n\n", name, name)
+	g.out.Write(bind.Bytes())
+	g.out.Write(body.Bytes())
+	g.out.Write(encode.Bytes())
+	g.out.Write(responses.Bytes())
+	return nil
+}
+
+// bindParams binds the scalar parameters of a request part. gofi binds the others itself.
+func (g *generator) bindParams(part string, ps *ast.StructType) {
+	for _, f := range ps.Fields.List {
+		if jsonName(f) == "-" {
+			continue
+		}
+		n := g.pkg.nodeOf(f.Type, map[string]bool{})
+		if n.kind == pointerNode && n.elem.kind == scalarNode {
+			n = &node{kind: pointerNode, rkind: n.rkind, family: n.elem.family}
+		} else if n.kind != scalarNode {
+			continue
+		}
+
+		for _, name := range f.Names {
+			if !name.IsExported() {
+				continue
+			}
+			dst := "v.Request." + part + "." + name.Name
+			i := g.add("Request."+part+"."+name.Name, n)
+			g.printf("if val, ok := b.Param(%d); ok {\n", i)
+			if n.kind == pointerNode {
+				g.printf("gofi.GenAlloc(&%s)\n", dst)
+				g.printf("b.Bound(%d, gofi.Gen%s(val, %s))\n}\n", i, convFamily(n.family), dst)
+			} else {
+				g.printf("b.Bound(%d, gofi.Gen%s(val, &%s))\n}\n", i, convFamily(n.family), dst)
+			}
+		}
+	}
+}
+
+// convFamily names the helper that stores a bound value of a scalar family.
+func convFamily(family string) string {
+	switch family {
+	case "Int", "Uint", "Float":
+		return "Number"
+	}
+	return family
+}
+
+func (g *generator) bindBody(n *node) {
+	i := g.add("Request.Body", n)
+	g.printf("func gofiBind%sBody(b *gofi.GenBinder, v *%s) error {\n", g.schema, g.schema)
+	g.printf("n0, ok, err := b.Body(%d)\nif err != nil || !ok {\nreturn err\n}\n", i)
+	g.printf("var k0 []string\np0 := &v.Request.Body\n")
+	g.bindFields(n, "Request.Body", "p0", "n0", "k0")
+	g.printf("return b.ObjectEnd(%d, n0, k0)\n}\n\n", i)
+}
+
+func (g *generator) bindFields(n *node, path, p, nv, kv string) {
+	for _, f := range n.fields {
+		c := g.newVar()
+		g.printf("{\n")
+		g.printf("n%d, k%d := b.Field(%d, %s, %s)\n", c, c, len(g.fields), nv, kv)
+		g.printf("p%d := &%s.%s\n", c, p, f.name)
+		g.bindNode(f.node, path+"."+f.name, fmt.Sprintf("p%d", c), fmt.Sprintf("n%d", c), fmt.Sprintf("k%d", c))
+		g.printf("}\n")
+	}
+}
+
+// bindNode writes the code that binds the JSON node nv to the field p points to.
+func (g *generator) bindNode(n *node, path, p, nv, kv string) {
+	i := g.add(path, n)
+	switch n.kind {
+	case reflectNode:
+		g.printf("if err := b.Reflect(%d, %s, %s, %s); err != nil {\nreturn err\n}\n", i, nv, kv, p)
+
+	case scalarNode:
+		g.printf("if val, ok, err := b.Value(%d, %s, %s, %s); err != nil {\nreturn err\n} else if ok {\n", i, nv, kv, p)
+		g.printf("if err := gofi.Gen%s(val, %s); err != nil {\nreturn b.DecodeError(%s, err)\n}\n}\n", convFamily(n.family), p, kv)
+
+	case pointerNode:
+		if n.elem.kind == scalarNode {
+			g.printf("if val, ok, err := b.Value(%d, %s, %s, %s); err != nil {\nreturn err\n} else if ok {\n", i, nv, kv, p)
+			g.printf("gofi.GenAlloc(%s)\n", p)
+			g.printf("if err := gofi.Gen%s(val, *%s); err != nil {\nreturn b.DecodeError(%s, err)\n}\n}\n", convFamily(n.elem.family), p, kv)
+			return
+		}
+		g.printf("if ok, err := b.Object(%d, %s, %s, %s); err != nil {\nreturn err\n} else if ok {\n", i, nv, kv, p)
+		g.printf("gofi.GenAlloc(%s)\n", p)
+		g.bindFields(n.elem, path, "(*"+p+")", nv, kv)
+		g.printf("if err := b.ObjectEnd(%d, %s, %s); err != nil {\nreturn err\n}\n}\n", i, nv, kv)
+
+	case structNode:
+		g.printf("if ok, err := b.Object(%d, %s, %s, %s); err != nil {\nreturn err\n} else if ok {\n", i, nv, kv, p)
+		g.bindFields(n, path, p, nv, kv)
+		g.printf("if err := b.ObjectEnd(%d, %s, %s); err != nil {\nreturn err\n}\n}\n", i, nv, kv)
+
+	case sliceNode:
+		c := g.newVar()
+		if n.elem.kind == scalarNode {
+			g.printf("if arr, ok, err := b.Values(%d, %s, %s, %s); err != nil {\nreturn err\n} else if ok {\n", i, nv, kv, p)
+			g.printf("gofi.GenMakeSlice(%s, len(arr))\n", p)
+			g.printf("for j%d, val := range arr {\n", c)
+			g.printf("if err := gofi.Gen%s(val, &(*%s)[j%d]); err != nil {\nreturn b.DecodeError(%s, err)\n}\n}\n}\n", convFamily(n.elem.family), p, c, kv)
+			g.add(path+".[]", n.elem)
+			return
+		}
+		g.strconv = true
+		g.printf("if items, ok, err := b.Items(%d, %s, %s, %s); err != nil {\nreturn err\n} else if ok {\n", i, nv, kv, p)
+		g.printf("gofi.GenMakeSlice(%s, len(items))\n", p)
+		g.printf("for j%d, n%d := range items {\n", c, c)
+		g.printf("k%d := append(%s, strconv.Itoa(j%d))\n", c, kv, c)
+		g.printf("p%d := &(*%s)[j%d]\n", c, p, c)
+		g.bindNode(n.elem, path+".[]", fmt.Sprintf("p%d", c), fmt.Sprintf("n%d", c), fmt.Sprintf("k%d", c))
+		g.printf("}\n")
+		g.printf("if err := b.ItemsEnd(%d, %s, *%s); err != nil {\nreturn err\n}\n}\n", i, kv, p)
+
+	case mapNode:
+		c := g.newVar()
+		g.printf("if obj, ok, err := b.Map(%d, %s, %s, %s); err != nil {\nreturn err\n} else if ok {\n", i, nv, kv, p)
+		g.printf("gofi.GenMakeMap(%s)\n", p)
+		g.printf("m%d := *%s\n", c, p)
+		g.printf("if err := b.Entries(%d, obj, %s, func(key string, n%d *gofi.JSONValue, k%d []string) error {\n", i, kv, c, c)
+		g.printf("p%d := gofi.GenElem(m%d)\n", c, c)
+		g.bindNode(n.elem, path+".{}", fmt.Sprintf("p%d", c), fmt.Sprintf("n%d", c), fmt.Sprintf("k%d", c))
+		g.printf("m%d[key] = *p%d\nreturn nil\n}); err != nil {\nreturn err\n}\n}\n", c, c)
+	}
+}
+
+// encodeNode writes the code that validates and encodes the field p points to.
+func (g *generator) encodeNode(n *node, path, p, kv string) {
+	i := g.add(path, n)
+	g.encodeValue(n, i, path, p, kv)
+}
+
+func (g *generator) encodeValue(n *node, i int, path, p, kv string) {
+	switch n.kind {
+	case reflectNode:
+		g.printf("if err := e.Value(%d, %s, %s); err != nil {\nreturn err\n}\n", i, p, kv)
+
+	case scalarNode:
+		g.printf("if err := gofi.GenEncode%s(e, %d, %s, %s); err != nil {\nreturn err\n}\n", n.family, i, p, kv)
+
+	case pointerNode:
+		g.printf("if done, err := gofi.GenEncodePointer(e, %d, %s, %s); err != nil {\nreturn err\n} else if !done {\n", i, p, kv)
+		g.encodeValue(n.elem, i, path, "*"+p, kv)
+		g.printf("}\n")
+
+	case structNode:
+		g.printf("if done, err := gofi.GenEncodeStruct(e, %d, %s, %s); err != nil {\nreturn err\n} else if !done {\n", i, p, kv)
+		owner := p
+		if strings.HasPrefix(p, "*") {
+			owner = "(" + p + ")"
+		}
+		for _, f := range n.fields {
+			c := g.newVar()
+			member := owner + "." + f.name
+			if f.node.kind == reflectNode {
+				g.printf("if err := e.Member(%d, &%s, %s); err != nil {\nreturn err\n}\n", g.add(path+"."+f.name, f.node), member, kv)
+				continue
+			}
+			g.printf("if kp%d, ok := e.Key(%d, %s, %s); ok {\n", c, len(g.fields), kv, emptyCheck(f.node, member))
+			g.printf("p%d := &%s\n", c, member)
+			g.encodeNode(f.node, path+"."+f.name, fmt.Sprintf("p%d", c), fmt.Sprintf("kp%d", c))
+			g.printf("}\n")
+		}
+		g.printf("e.End('}')\n}\n")
+
+	case sliceNode:
+		c := g.newVar()
+		g.strconv = true
+		g.printf("if done, err := gofi.GenEncodeSlice(e, %d, %s, %s); err != nil {\nreturn err\n} else if !done {\n", i, p, kv)
+		g.printf("for j%d := range *%s {\n", c, p)
+		g.printf("kp%d := append(%s, strconv.Itoa(j%d))\n", c, kv, c)
+		g.printf("e.Sep()\np%d := &(*%s)[j%d]\n", c, p, c)
+		g.encodeNode(n.elem, path+".[]", fmt.Sprintf("p%d", c), fmt.Sprintf("kp%d", c))
+		g.printf("}\ne.End(']')\n}\n")
+
+	case mapNode:
+		c := g.newVar()
+		g.printf("if done, err := gofi.GenEncodeMap(e, %d, %s, %s); err != nil {\nreturn err\n} else if !done {\n", i, p, kv)
+		g.printf("for key%d, val%d := range *%s {\n", c, c, p)
+		g.printf("kp%d := append(%s, key%d)\n", c, kv, c)
+		g.printf("e.Sep()\nif err := e.MapKey(%d, key%d, kp%d); err != nil {\nreturn err\n}\n", i, c, c)
+		g.printf("p%d := &val%d\n", c, c)
+		g.encodeNode(n.elem, path+".{}", fmt.Sprintf("p%d", c), fmt.Sprintf("kp%d", c))
+		g.printf("}\ne.End('}')\n}\n")
+	}
+}
+
+// emptyCheck returns the condition under which gofi leaves out an omitempty field.
+func emptyCheck(n *node, expr string) string {
+	switch n.kind {
+	case scalarNode:
+		switch n.family {
+		case "String":
+			return expr + ` == ""`
+		case "Int":
+			return expr + " == 0"
+		case "Bool":
+			return "!" + expr
+		}
+	case pointerNode:
+		return expr + " == nil"
+	case sliceNode, mapNode:
+		return "len(" + expr + ") == 0"
+	}
+	return "false"
+}
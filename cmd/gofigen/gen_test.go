@@ -0,0 +1,37 @@
+package main
+
+import (
+	"os"
+	"testing"
+)
+
+func TestGenerate_UpToDate(t *testing.T) {
+	want, err := os.ReadFile("../../internal/gentest/schema_gofi.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := generate("../../internal/gentest", "schema_gofi.go", []string{"OrderSchema"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != string(want) {
+		t.Errorf("internal/gentest/schema_gofi.go is out of date, run go generate ./internal/gentest")
+	}
+}
+
+func TestGenerate_Errors(t *testing.T) {
+	tests := []struct {
+		name  string
+		types []string
+	}{
+		{"unknown type", []string{"Missing"}},
+		{"not a struct", []string{"Status"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := generate("../../internal/gentest", "schema_gofi.go", tt.types); err == nil {
+				t.Errorf("generate(%v) succeeded, want an error", tt.types)
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+// Command gofigen writes the validation, binding and JSON encoding code of gofi route schemas,
+// so that routes using them skip the reflective path.
+//
+//	//go:generate go run github.com/michaelolof/gofi/cmd/gofigen -type CreateUserSchema,GetUserSchema
+//
+// It reads the package in the current directory, or in the directory given as argument, and
+// writes the code of the named schema structs to <first type>_gofi.go. The rules still come
+// from the struct tags at runtime, so the code only needs to be generated again when the Go
+// types of a schema change. gofi stops at startup when it finds generated code that no longer
+// matches its schema.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("gofigen: ")
+
+	types := flag.String("type", "", "comma-separated list of schema type names; required")
+	output := flag.String("output", "", "output file name; default <first type>_gofi.go")
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: gofigen -type T[,T...] [-output file] [dir]")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if *types == "" || flag.NArg() > 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	dir := "."
+	if flag.NArg() == 1 {
+		dir = flag.Arg(0)
+	}
+
+	names := strings.Split(*types, ",")
+	out := *output
+	if out == "" {
+		out = strings.ToLower(names[0]) + "_gofi.go"
+	}
+	out = filepath.Join(dir, out)
+
+	src, err := generate(dir, filepath.Base(out), names)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(out, src, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
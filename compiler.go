@@ -1,13 +1,15 @@
 package gofi
 
 import (
-	"log"
+	"errors"
 	"reflect"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/michaelolof/gofi/cont"
 	"github.com/michaelolof/gofi/utils"
 )
 
@@ -19,11 +21,24 @@ type compiledSchema struct {
 }
 
 func (s *serveMux) compileSchema(schema any, info Info) compiledSchema {
+	return s.compileStrictSchema(schema, info, nil)
+}
+
+// compileStrictSchema is compileSchema with the strict settings of a route, or of the router
+// when strict is nil.
+func (s *serveMux) compileStrictSchema(schema any, info Info, strict *Strict) compiledSchema {
+	if strict == nil {
+		strict = &s.opts.strict
+	}
 
 	var strct = reflect.TypeOf(schema)
 	if strct.Kind() == reflect.Pointer || strct.Kind() == reflect.Interface {
 		strct = strct.Elem()
 	}
+	if strct.Kind() != reflect.Struct {
+		s.reg.fail("", errors.New("schema "+strct.String()+" must be a struct"))
+		return compiledSchema{specs: initOpenapiOperationObject(), rules: newSchemaRules(nil)}
+	}
 
 	optsObj := initOpenapiOperationObject()
 	sRules := newSchemaRules(strct)
@@ -47,10 +62,14 @@ func (s *serveMux) compileSchema(schema any, info Info) compiledSchema {
 		}
 
 		obj := reflect.ValueOf(schema).Elem().FieldByName(sf.Name)
+		s.reg.at(0, sf.Name)
 
 		if sf.Name == string(schemaReq) {
+			sRules.reqHook = structHookOf(sf.Type)
+			sRules.strictQuery = strict.Query
 			for _, rqf := range reflect.VisibleFields(sf.Type) {
 				rqn := schemaField(rqf.Name)
+				s.reg.at(1, rqf.Name)
 				kind := rqf.Type.Kind()
 
 				switch rqn {
@@ -67,7 +86,7 @@ func (s *serveMux) compileSchema(schema any, info Info) compiledSchema {
 						if isPromotedEmbed(rqff) {
 							continue // children are emitted as their own promoted entries
 						}
-						if rqn == schemaCookies && !utils.ValidCookieType(rqff.Type) {
+						if rqn == schemaCookies && !s.validCookieType(repeatedElem(rqff.Type)) {
 							continue
 						}
 
@@ -79,6 +98,7 @@ func (s *serveMux) compileSchema(schema any, info Info) compiledSchema {
 						if in == "header" {
 							name = strings.ToLower(name)
 						}
+						s.reg.at(2, name)
 						ruleDefs := s.getFieldRuleDefs(rqff, name, val)
 						pruleDefs.attach(name, ruleDefs)
 						var required *bool
@@ -87,18 +107,46 @@ func (s *serveMux) compileSchema(schema any, info Info) compiledSchema {
 						}
 
 						tInfo := s.getTypeInfo(rqff.Type, val, name, ruleDefs)
-						optsObj.Parameters = append(
-							optsObj.Parameters,
-							newOpenapiParameter(in, name, required, tInfo),
-						)
+						param := newOpenapiParameter(in, name, required, tInfo)
+						if rqn == schemaQuery {
+							if err := ruleDefs.setQueryStyle(); err != nil {
+								s.reg.fail("", err)
+							}
+							if ruleDefs.style != "" {
+								explode := ruleDefs.explode
+								param.Style, param.Explode = ruleDefs.style, &explode
+							}
+						}
+						optsObj.Parameters = append(optsObj.Parameters, param)
+					}
+					s.reg.leave(2)
+					if v, ok := rqf.Tag.Lookup("strict"); ok && rqn == schemaQuery {
+						if strictQuery, err := strconv.ParseBool(v); err != nil {
+							s.reg.fail(tagOf("strict", v), errors.New("invalid strict tag"))
+						} else {
+							sRules.strictQuery = strictQuery
+						}
+					}
+					pruleDefs.setHook(rqf.Type)
+					pruleDefs.setAsync()
+					if len(pruleDefs.properties) > 0 || pruleDefs.hooked {
 						sRules.setReq(sf.Name, pruleDefs)
 					}
+					if err := pruleDefs.checkCrossFields(); err != nil {
+						s.reg.fail("", err)
+					}
 
 				case schemaBody:
 					val := getPrimitiveValFromParent(obj, rqf)
 					name := getFieldName(rqf)
 					ruleDefs := s.getFieldRuleDefs(rqf, name, val)
 					optsObj.bodySchema = s.getTypeInfo(rqf.Type, val, name, ruleDefs)
+					if err := ruleDefs.setStrict(strict.Body, &optsObj.bodySchema); err != nil {
+						s.reg.fail("", err)
+					}
+					if err := optsObj.setPatchMedia(ruleDefs); err != nil {
+						s.reg.fail("", err)
+					}
 					sRules.setReq(sf.Name, ruleDefs)
 				}
 			}
@@ -108,8 +156,10 @@ func (s *serveMux) compileSchema(schema any, info Info) compiledSchema {
 				sRules.websocket = compileWebSocketContract(protocol)
 			}
 		} else if _, ok := statuses[sf.Name]; ok {
+			sRules.respHooks[sf.Name] = structHookOf(sf.Type)
 			for _, rqf := range reflect.VisibleFields(sf.Type) {
 				rqn := schemaField(rqf.Name)
+				s.reg.at(1, rqf.Name)
 				kind := rqf.Type.Kind()
 				responseParameters := make(openapiParameters, 0, 10)
 
@@ -128,7 +178,7 @@ func (s *serveMux) compileSchema(schema any, info Info) compiledSchema {
 						if isPromotedEmbed(rqff) {
 							continue // children are emitted as their own promoted entries
 						}
-						if rqn == schemaCookies && !utils.ValidCookieType(rqff.Type) {
+						if rqn == schemaCookies && !s.validCookieType(rqff.Type) {
 							continue
 						}
 
@@ -137,6 +187,7 @@ func (s *serveMux) compileSchema(schema any, info Info) compiledSchema {
 						if name == "-" {
 							continue
 						}
+						s.reg.at(2, name)
 						ruleDefs := s.getFieldRuleDefs(rqff, name, val)
 						pruleDefs.attach(name, ruleDefs)
 						var required *bool
@@ -149,8 +200,12 @@ func (s *serveMux) compileSchema(schema any, info Info) compiledSchema {
 							responseParameters,
 							newOpenapiParameter(in, name, required, tInfo),
 						)
+					}
+					pruleDefs.setHook(rqf.Type)
+					if len(pruleDefs.properties) > 0 || pruleDefs.hooked {
 						sRules.setResps(sf.Name, pruleDefs)
 					}
+					s.reg.leave(2)
 					optsObj.responsesParameters[sf.Name] = responseParameters
 
 				case schemaBody:
@@ -159,18 +214,49 @@ func (s *serveMux) compileSchema(schema any, info Info) compiledSchema {
 					ruleDefs := s.getFieldRuleDefs(rqf, name, val)
 					optsObj.responsesSchema[sf.Name] = s.getTypeInfo(rqf.Type, val, name, ruleDefs)
 					sRules.setResps(sf.Name, ruleDefs)
+
+				case schemaEvents:
+					if kind != reflect.Struct {
+						continue
+					}
+
+					val := getPrimitiveValFromParent(obj, rqf)
+					name := getFieldName(rqf)
+					ruleDefs := s.getFieldRuleDefs(rqf, name, val)
+					optsObj.responsesSchema[sf.Name] = eventStreamSchema(s.getTypeInfo(rqf.Type, val, name, ruleDefs))
+					sRules.setResps(sf.Name, ruleDefs)
+					sRules.setRespContent(sf.Name, cont.TextEventStream)
 				}
 			}
 		}
 	}
 
+	s.reg.leave(0)
+	if gs, ok := schema.(GeneratedSchema); ok {
+		gen, err := compileGenerated(&sRules, gs, strct)
+		if err != nil {
+			s.reg.fail("", err)
+		}
+		sRules.gen = gen
+	}
+
 	return compiledSchema{
 		specs: optsObj,
 		rules: sRules,
 	}
 }
 
-func (s *serveMux) getFieldRuleDefs(sf reflect.StructField, _ string, defVal any) *RuleDef {
+func (s *serveMux) getFieldRuleDefs(sf reflect.StructField, name string, defVal any) *RuleDef {
+	// The tags of an Optional or Nullable field describe the value it wraps.
+	if _, wt, ok := wrapperOf(sf.Type); ok {
+		inner := sf
+		inner.Type = wt
+		item := s.getFieldRuleDefs(inner, name, defVal)
+		rtn := newRuleDef(sf, "", nil, nil, false, false, nil, nil, item, nil)
+		rtn.tags = item.tags
+		return rtn
+	}
+
 	supportedTags := []string{
 		"json",
 		"validate",
@@ -180,14 +266,22 @@ func (s *serveMux) getFieldRuleDefs(sf reflect.StructField, _ string, defVal any
 		"description",
 		"pattern",
 		"spec",
+		"mod",
+		"style",
+		"explode",
+		"patch",
+		"strict",
 	}
 
 	tagList := make(map[string][]string)
 	var defStr string
+	var defFn DefaultFunc
 	var rules []ruleOpts
 	var required bool
 	var present bool
 	var max *float64
+	var dive string
+	var mods []modifierOpts
 
 	for _, stag := range supportedTags {
 		if tag, ok := sf.Tag.Lookup(stag); ok {
@@ -198,7 +292,7 @@ func (s *serveMux) getFieldRuleDefs(sf reflect.StructField, _ string, defVal any
 				}
 
 				tagList[stag] = strings.Split(tag, ",")
-			case "example", "deprecated", "description", "pattern", "spec":
+			case "example", "deprecated", "description", "pattern", "spec", "style", "explode", "patch", "strict":
 				if len(strings.TrimSpace(tag)) == 0 {
 					continue
 				}
@@ -210,12 +304,48 @@ func (s *serveMux) getFieldRuleDefs(sf reflect.StructField, _ string, defVal any
 				}
 
 				defStr = parseTagValue(tag, sf.Type)
+				if name, ok := strings.CutPrefix(defStr, "$"); ok && !strings.HasPrefix(name, "$") {
+					fn, ok := s.opts.lookupDefault(name)
+					if !ok {
+						s.reg.fail(tagOf("default", tag), errors.New("unknown default provider '"+name+"'"))
+						defStr, defVal = "", nil
+						continue
+					}
+					defFn, defVal = fn, nil
+				} else if ok {
+					defStr = name
+					if _, isStr := defVal.(string); isStr {
+						defVal = name
+					}
+				}
+			case "mod":
+				for _, m := range strings.Split(tag, ",") {
+					maches := tagFieldRegex.FindStringSubmatch(strings.TrimSpace(m))
+					if maches == nil {
+						continue
+					}
+					fn, ok := s.opts.lookupModifier(maches[1])
+					if !ok {
+						s.reg.fail(tagOf("mod", tag), errors.New("unknown modifier '"+maches[1]+"'"))
+						continue
+					}
+					var args []string
+					if len(maches[2]) > 0 {
+						args = strings.Split(maches[2], " ")
+					}
+					mods = append(mods, modifierOpts{name: maches[1], args: args, fn: fn})
+				}
 			case "validate":
 				if len(strings.TrimSpace(tag)) == 0 {
 					continue
 				}
 
-				vtags := strings.Split(tag, ",")
+				vtags := strings.Split(s.opts.aliases.Expand(tag), ",")
+				// Rules after "dive" apply to each slice item or map value.
+				if i := slices.Index(vtags, "dive"); i >= 0 {
+					dive = strings.Join(vtags[i+1:], ",")
+					vtags = vtags[:i]
+				}
 
 				var allowZero bool
 				rules = make([]ruleOpts, 0, len(vtags))
@@ -253,6 +383,9 @@ func (s *serveMux) getFieldRuleDefs(sf reflect.StructField, _ string, defVal any
 					}
 
 					rules = append(rules, newRuleOpts(sf.Type, sf.Type.Kind(), ruleName, options, s.opts))
+					if !s.opts.knownRule(ruleName) {
+						s.reg.unknownRule(ruleName, tagOf("validate", tag))
+					}
 				}
 
 				// Normalize required+allow_zero → present: relax zero-value check to nil-only.
@@ -272,9 +405,42 @@ func (s *serveMux) getFieldRuleDefs(sf reflect.StructField, _ string, defVal any
 
 	rtn := newRuleDef(sf, defStr, defVal, rules, required, present, max, nil, nil, nil)
 	rtn.tags = tagList
+	rtn.dive = dive
+	rtn.mods = mods
+	rtn.defFn = defFn
 	return rtn
 }
 
+// getItemRuleDefs builds the rules for slice items, map values or map keys of type typ from the
+// part of a validate tag that follows "dive" (or sits between "keys" and "endkeys").
+func (s *serveMux) getItemRuleDefs(typ reflect.Type, tag string) *RuleDef {
+	if tag == "" {
+		return getItemRuleDef(typ)
+	}
+
+	sf := reflect.StructField{
+		Type: typ,
+		Tag:  reflect.StructTag(`validate:` + strconv.Quote(tag)),
+	}
+	return s.getFieldRuleDefs(sf, "", nil)
+}
+
+// splitKeysTag separates the "keys,...,endkeys" section at the start of a dive tag into the
+// map key rules and the map value rules.
+func splitKeysTag(dive string) (keys string, values string, err error) {
+	rest, ok := strings.CutPrefix(dive, "keys,")
+	if !ok {
+		return "", dive, nil
+	}
+
+	parts := strings.Split(rest, ",")
+	i := slices.Index(parts, "endkeys")
+	if i < 0 {
+		return "", dive, errors.New("validate tag 'keys' must be closed with 'endkeys' in '" + dive + "'")
+	}
+	return strings.Join(parts[:i], ","), strings.Join(parts[i+1:], ","), nil
+}
+
 func (s *serveMux) getTypeInfo(typ reflect.Type, value any, name string, ruleDefs *RuleDef) openapiSchema {
 	return s.getTypeInfoRecursive(typ, value, name, ruleDefs)
 }
@@ -341,6 +507,9 @@ func (s *serveMux) getTypeInfoRecursive(typ reflect.Type, value any, name string
 		if v, ok := ruleDefs.tags["description"]; ok && len(v) > 0 {
 			description = v[0]
 		}
+		if v := crossFieldDescription(ruleDefs); v != "" {
+			description = strings.TrimSpace(description + " " + v)
+		}
 
 		if v, ok := ruleDefs.tags["pattern"]; ok && len(v) > 0 {
 			pattern = v[0]
@@ -351,6 +520,21 @@ func (s *serveMux) getTypeInfoRecursive(typ reflect.Type, value any, name string
 		}
 	}
 
+	if wf, wt, ok := wrapperOf(typ); ok && ruleDefs != nil && ruleDefs.item != nil {
+		ruleDefs.format = wf
+		rtn := s.getTypeInfoRecursive(wt, value, name, ruleDefs.item)
+		rtn.Nullable = wf == utils.NullableFormat
+		rtn.ParentRequired = false
+		ruleDefs.setHook(typ)
+		ruleDefs.setAsync()
+		ruleDefs.setDefaulted()
+		return rtn
+	}
+
+	if specTag == "" && ruleDefs != nil {
+		specTag = s.typeSpecID(typ)
+	}
+
 	isCustom := false
 	if v, ok := s.opts.customSpecs.Find(specTag); ok {
 		enum = optsMapper(optStr, nil)
@@ -360,6 +544,10 @@ func (s *serveMux) getTypeInfoRecursive(typ reflect.Type, value any, name string
 		isCustom = true
 	}
 
+	oneofErr := func(opt string) {
+		s.reg.fail(tagOf("validate", "oneof="+strings.Join(optStr, " ")), errors.New("option '"+opt+"' of oneof is not a number"))
+	}
+
 	if !isCustom {
 		switch kind {
 		case reflect.String:
@@ -371,7 +559,8 @@ func (s *serveMux) getTypeInfoRecursive(typ reflect.Type, value any, name string
 			enum = optsMapper(optStr, func(s string) any {
 				v, err := strconv.Atoi(s)
 				if err != nil {
-					log.Fatalln("unsupported type in schema validate option 'oneof=" + s + "' at " + name)
+					oneofErr(s)
+					return nil
 				}
 				return int32(v)
 			})
@@ -382,7 +571,8 @@ func (s *serveMux) getTypeInfoRecursive(typ reflect.Type, value any, name string
 			enum = optsMapper(optStr, func(s string) any {
 				v, err := strconv.Atoi(s)
 				if err != nil {
-					log.Fatalln("unsupported type in schema validate option 'oneof=" + s + "' at " + name)
+					oneofErr(s)
+					return nil
 				}
 				return int64(v)
 			})
@@ -393,7 +583,8 @@ func (s *serveMux) getTypeInfoRecursive(typ reflect.Type, value any, name string
 			enum = optsMapper(optStr, func(s string) any {
 				v, err := strconv.ParseFloat(s, 32)
 				if err != nil {
-					log.Fatalln("unsupported type in schema validate option 'oneof=" + s + "' at " + name)
+					oneofErr(s)
+					return nil
 				}
 				return float64(v)
 			})
@@ -421,18 +612,39 @@ func (s *serveMux) getTypeInfoRecursive(typ reflect.Type, value any, name string
 				break
 			}
 			typeStr = "array"
-			_ruleDefs := getItemRuleDef(typ.Elem())
+			_ruleDefs := s.getItemRuleDefs(typ.Elem(), ruleDefs.diveTag())
+			_ruleDefs.inheritMods(ruleDefs)
+			// The pattern of a time slice is the layout of its items.
+			if p := ruleDefs.tags["pattern"]; len(p) > 0 && (typ.Elem() == utils.TimeType || typ.Elem() == reflect.PointerTo(utils.TimeType)) {
+				if _ruleDefs.tags == nil {
+					_ruleDefs.tags = make(map[string][]string)
+				}
+				_ruleDefs.tags["pattern"] = p
+			}
 			ruleDefs.append(_ruleDefs)
 			i := s.getTypeInfoRecursive(typ.Elem(), value, name, _ruleDefs)
 			items = &i
 
 		case reflect.Map:
 			typeStr = "object"
-			_ruleDefs := getItemRuleDef(typ.Elem())
+			keyTag, valueTag, err := splitKeysTag(ruleDefs.diveTag())
+			if err != nil {
+				s.reg.fail("", err)
+			}
+			_ruleDefs := s.getItemRuleDefs(typ.Elem(), valueTag)
+			_ruleDefs.inheritMods(ruleDefs)
 			ruleDefs.addProps(_ruleDefs)
 			i := s.getTypeInfoRecursive(typ.Elem(), value, name, _ruleDefs)
 			addProps = &i
 
+			if keyTag != "" {
+				keyDefs := s.getItemRuleDefs(typ.Key(), keyTag)
+				ruleDefs.setKeys(keyDefs)
+				s.getTypeInfoRecursive(typ.Key(), nil, name, keyDefs)
+				// propertyNames is not an OpenAPI 3.0 keyword, so key rules are described instead.
+				description = strings.TrimSpace(description + " Keys must satisfy `" + keyTag + "`.")
+			}
+
 		case reflect.Struct:
 			switch typ {
 			case utils.TimeType:
@@ -450,7 +662,7 @@ func (s *serveMux) getTypeInfoRecursive(typ reflect.Type, value any, name string
 				format = string(utils.CookieObjectFormat)
 				ruleDefs.format = utils.CookieObjectFormat
 
-			case utils.MultipartFile:
+			case utils.MultipartFile, filePartType:
 				typeStr = "string"
 				format = "binary"
 
@@ -467,14 +679,28 @@ func (s *serveMux) getTypeInfoRecursive(typ reflect.Type, value any, name string
 						continue
 					}
 
+					depth := s.reg.enter(name)
 					_ruleDefs := s.getFieldRuleDefs(sf, name, val)
+					if strings.HasPrefix(sf.Tag.Get("default"), "$") {
+						val = _ruleDefs.defVal // dynamic defaults are not documented
+					}
 					ruleDefs.attach(name, _ruleDefs)
 					if _ruleDefs.hasRule("required") || _ruleDefs.hasRule("present") {
 						requiredProps = append(requiredProps, name)
 					}
 					properties[name] = s.getTypeInfoRecursive(sf.Type, val, name, _ruleDefs)
+					s.reg.leave(depth)
 				}
+				if err := ruleDefs.checkCrossFields(); err != nil {
+					s.reg.fail("", err)
+				}
+
+			}
 
+		case reflect.Interface:
+			if typ.Implements(readerType) {
+				typeStr = "string"
+				format = "binary"
 			}
 
 		case reflect.Pointer:
@@ -485,6 +711,12 @@ func (s *serveMux) getTypeInfoRecursive(typ reflect.Type, value any, name string
 	}
 
 	ruleDefs.pattern = pattern
+	ruleDefs.setHook(typ)
+	ruleDefs.setAsync()
+	if v := s.compileDefault(ruleDefs, name); v != nil && value == nil {
+		value = v
+	}
+	ruleDefs.setDefaulted()
 
 	rtn := newOpenapiSchema(
 		format,
@@ -176,6 +176,27 @@ func GetNodeByKind(node *fastjson.Value, kind reflect.Kind, format utils.ObjectF
 		return EOF, nil
 	}
 
+	// Text-encoded values are decoded by their spec. Strings are passed unquoted and any other
+	// JSON value as its literal, so numbers keep their full precision.
+	if format.IsText() {
+		if node.Type() == fastjson.TypeString {
+			v, err := node.StringBytes()
+			if err != nil {
+				return nil, err
+			}
+			return string(v), nil
+		}
+		return string(node.MarshalTo(nil)), nil
+	}
+	// Custom spec fields of a primitive kind may also be sent as strings, e.g. "1h" for a duration.
+	if format != "" && utils.IsPrimitiveKind(kind) && node.Type() == fastjson.TypeString {
+		v, err := node.StringBytes()
+		if err != nil {
+			return nil, err
+		}
+		return string(v), nil
+	}
+
 	switch kind {
 	case reflect.String:
 		v, err := node.StringBytes()
@@ -12,6 +12,10 @@ const (
 	ApplicationZip            ContentType = "application/zip"
 	ApplicationOgg            ContentType = "application/ogg"
 	ApplicationFormUrlEncoded ContentType = "application/x-www-form-urlencoded"
+	ApplicationNdjson         ContentType = "application/x-ndjson"
+	ApplicationProblemJson    ContentType = "application/problem+json"
+	ApplicationJsonPatch      ContentType = "application/json-patch+json"
+	ApplicationMergePatch     ContentType = "application/merge-patch+json"
 
 	AudioMpeg      ContentType = "audio/mpeg"
 	AudioXMsWma    ContentType = "audio/x-ms-wma"
@@ -25,12 +29,13 @@ const (
 	ImageXIcon  ContentType = "image/x-icon"
 	ImageSvgXml ContentType = "image/svg+xml"
 
-	TextCss        ContentType = "text/css"
-	TextCsv        ContentType = "text/csv"
-	TextHtml       ContentType = "text/html"
-	TextJavaScript ContentType = "text/javascript"
-	TextPlain      ContentType = "text/plain"
-	TextXml        ContentType = "text/xml"
+	TextCss         ContentType = "text/css"
+	TextCsv         ContentType = "text/csv"
+	TextEventStream ContentType = "text/event-stream"
+	TextHtml        ContentType = "text/html"
+	TextJavaScript  ContentType = "text/javascript"
+	TextPlain       ContentType = "text/plain"
+	TextXml         ContentType = "text/xml"
 
 	// video/mpeg
 	// video/mp4
@@ -38,10 +38,15 @@ type Context interface {
 	SendString(code int, s string) error
 	SendBytes(code int, b []byte) error
 
-	// SetBodyStreamWriter sets a chunked stream writer for the response body
+	// SetBodyStreamWriter sets a chunked stream writer for the response body. The writer runs
+	// after the handler returns, so it must not use the Context; errors it returns are logged.
 	SetBodyStreamWriter(sw func(w *bufio.Writer) error) error
 	// SendStream simplifies SSE. Sets headers based on schema definition and takes over the connection.
 	SendStream(code int, s any, sw func(w *bufio.Writer) error) error
+	// SendEvents streams typed server-sent events declared on the response schema's Events field.
+	// Response headers are validated from s before the stream starts. fn runs after the handler
+	// returns, like a SetBodyStreamWriter callback, and the error it returns is logged.
+	SendEvents(code int, s any, fn func(ew *EventWriter) error) error
 
 	GetSchemaRules(pattern, method string) any
 	// Next calls the next handler in the middleware chain
@@ -54,7 +59,8 @@ type Context interface {
 	HeaderVal(name string) string
 	// HeaderBytes returns the request header value as raw bytes (zero-copy from fasthttp)
 	HeaderBytes(name string) []byte
-	// Body returns the raw request body bytes
+	// Body returns the raw request body bytes. With Config.StreamRequestBody enabled, a body
+	// larger than BodyLimit is not buffered and Body returns nil.
 	Body() []byte
 	// Path returns the request URL path
 	Path() string
@@ -143,7 +149,7 @@ func (c *context) Writer() ResponseWriter {
 
 func (c *context) Request() *Request {
 	if c.req == nil {
-		c.req = newRequest(c.fctx, c.opts.Pattern)
+		c.req = newRequest(c.fctx, c.opts.Pattern, c.bodyLimit())
 	}
 	return c.req
 }
@@ -257,7 +263,16 @@ func (c *context) HeaderVal(name string) string {
 }
 
 func (c *context) Body() []byte {
-	return c.fctx.PostBody()
+	body, _ := readRequestBody(c.fctx, c.bodyLimit())
+	return body
+}
+
+// bodyLimit returns the BodyLimit to enforce when buffering a streamed request body, or 0 for none.
+func (c *context) bodyLimit() int {
+	if c.serverOpts == nil || !c.serverOpts.streamReqBody {
+		return 0
+	}
+	return c.serverOpts.bodyLimit
 }
 
 func (c *context) QueryBytes(name string) []byte {
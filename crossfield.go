@@ -0,0 +1,221 @@
+package gofi
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/michaelolof/gofi/cont"
+	"github.com/michaelolof/gofi/utils"
+	"github.com/michaelolof/gofi/validators/rules"
+	"github.com/valyala/fastjson"
+)
+
+func isCrossFieldRule(rule string) bool {
+	_, ok := rules.CrossFieldChecks[rule]
+	return ok
+}
+
+// sibling returns the child of r named name, matching json names first, then Go field names,
+// then json names case-insensitively (header names are stored lower-cased).
+func (r *RuleDef) sibling(name string) *RuleDef {
+	if def, ok := r.properties[name]; ok {
+		return def
+	}
+	for _, def := range r.orderedProps {
+		if def.fieldName == name {
+			return def
+		}
+	}
+	for _, def := range r.orderedProps {
+		if strings.EqualFold(def.field, name) {
+			return def
+		}
+	}
+	return nil
+}
+
+// checkCrossFields reports cross-field rules on the children of r that name unknown siblings
+// or have the wrong number of arguments.
+func (r *RuleDef) checkCrossFields() error {
+	if r == nil {
+		return nil
+	}
+
+	for _, def := range r.crossFields {
+		for _, rule := range def.rules {
+			if !isCrossFieldRule(rule.rule) {
+				continue
+			}
+
+			names, err := rules.CrossFieldNames(rule.rule, rule.args)
+			if err != nil {
+				return fmt.Errorf("%w on field '%s'", err, def.field)
+			}
+
+			for _, name := range names {
+				if r.sibling(name) == nil {
+					return fmt.Errorf("validate rule '%s' on field '%s' references unknown field '%s'", rule.rule, def.field, name)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// validateCrossFields runs the cross-field rules declared on the children of parent. lookup
+// returns the value read for a child, or nil when the child is absent.
+func validateCrossFields(parent *RuleDef, typ errorType, field schemaField, keys []string, lookup func(def *RuleDef) any) error {
+	if parent == nil || len(parent.crossFields) == 0 {
+		return nil
+	}
+
+	sibling := func(name string) any {
+		if def := parent.sibling(name); def != nil {
+			return lookup(def)
+		}
+		return nil
+	}
+
+	var errs []error
+	for _, def := range parent.crossFields {
+		val := lookup(def)
+		for _, rule := range def.rules {
+			check, ok := rules.CrossFieldChecks[rule.rule]
+			if !ok {
+				continue
+			}
+
+			if err := check(val, rule.args, sibling); err != nil {
+				report := newKeysReport(typ, field, append(keys[:len(keys):len(keys)], def.field), rule.rule, err)
+				report.args, report.value = rule.args, val
+				errs = append(errs, report)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// jsonFieldLookup reads sibling values from a decoded JSON object.
+func jsonFieldLookup(node *fastjson.Value) func(def *RuleDef) any {
+	return func(def *RuleDef) any {
+		child := node.Get(def.field)
+		if (def.kind == reflect.Slice || def.kind == reflect.Array) && def.format != utils.RawJSONFormat && def.format != utils.ByteFormat {
+			if child == nil {
+				return nil
+			}
+			if arr, err := child.Array(); err == nil {
+				return arr
+			}
+			return nil
+		}
+
+		v, err := cont.GetNodeByKind(child, def.kind, def.format)
+		if err != nil {
+			return nil
+		}
+		if v == cont.EOF {
+			return def.defVal
+		}
+		if s, ok := v.(string); ok && def.format == utils.TimeObjectFormat {
+			t, err := time.Parse(def.pattern, s)
+			if err != nil {
+				return nil
+			}
+			return t
+		}
+		return v
+	}
+}
+
+// structFieldLookup reads sibling values from a bound struct.
+func structFieldLookup(strct reflect.Value) func(def *RuleDef) any {
+	return func(def *RuleDef) any {
+		fv := strct.FieldByName(def.fieldName)
+		for fv.Kind() == reflect.Pointer {
+			if fv.IsNil() {
+				return nil
+			}
+			fv = fv.Elem()
+		}
+		if !fv.IsValid() || !fv.CanInterface() {
+			return nil
+		}
+		return fv.Interface()
+	}
+}
+
+// strFieldLookup reads sibling values from string request parts such as headers and queries.
+func (c *context) strFieldLookup(get func(string) string) func(def *RuleDef) any {
+	return func(def *RuleDef) any {
+		s := def.modify(get(def.field))
+		if s == "" {
+			s = def.defaultText()
+		}
+		if s == "" {
+			return nil
+		}
+		v, err := c.parseStrValue(def, s)
+		if err != nil {
+			return nil
+		}
+		return v
+	}
+}
+
+// crossFieldDescription documents the cross-field rules of def for the OpenAPI spec, which has
+// no keyword for them.
+func crossFieldDescription(def *RuleDef) string {
+	if def == nil {
+		return ""
+	}
+
+	quote := func(names []string) string {
+		q := make([]string, 0, len(names))
+		for _, n := range names {
+			q = append(q, "`"+n+"`")
+		}
+		return strings.Join(q, " or ")
+	}
+
+	var sentences []string
+	for _, rule := range def.rules {
+		if !isCrossFieldRule(rule.rule) || len(rule.args) == 0 {
+			continue
+		}
+
+		switch rule.rule {
+		case "eqfield":
+			sentences = append(sentences, "Must equal "+quote(rule.args[:1])+".")
+		case "nefield":
+			sentences = append(sentences, "Must not equal "+quote(rule.args[:1])+".")
+		case "gtfield":
+			sentences = append(sentences, "Must be greater than "+quote(rule.args[:1])+".")
+		case "gtefield":
+			sentences = append(sentences, "Must be greater than or equal to "+quote(rule.args[:1])+".")
+		case "ltfield":
+			sentences = append(sentences, "Must be less than "+quote(rule.args[:1])+".")
+		case "ltefield":
+			sentences = append(sentences, "Must be less than or equal to "+quote(rule.args[:1])+".")
+		case "required_if", "required_unless":
+			pairs := make([]string, 0, len(rule.args)/2)
+			for i := 0; i+1 < len(rule.args); i += 2 {
+				pairs = append(pairs, "`"+rule.args[i]+"` is `"+rule.args[i+1]+"`")
+			}
+			word := "when"
+			if rule.rule == "required_unless" {
+				word = "unless"
+			}
+			sentences = append(sentences, "Required "+word+" "+strings.Join(pairs, " and ")+".")
+		case "required_with":
+			sentences = append(sentences, "Required when "+quote(rule.args)+" is present.")
+		case "required_without":
+			sentences = append(sentences, "Required when "+quote(rule.args)+" is missing.")
+		case "excluded_with":
+			sentences = append(sentences, "Must be omitted when "+quote(rule.args)+" is present.")
+		}
+	}
+	return strings.Join(sentences, " ")
+}
@@ -0,0 +1,149 @@
+package gofi
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type crossFieldSchema struct {
+	Request struct {
+		Header struct {
+			Mode  string `json:"x-mode"`
+			Token string `json:"x-token" validate:"required_if=x-mode strict"`
+		}
+		Query struct {
+			From int `json:"from"`
+			To   int `json:"to" validate:"gtfield=from"`
+		}
+		Body struct {
+			Country   string    `json:"country"`
+			VatNumber string    `json:"vat_number" validate:"required_if=country DE"`
+			StartDate time.Time `json:"start_date"`
+			EndDate   time.Time `json:"end_date" validate:"gtfield=start_date"`
+			Password  string    `json:"password"`
+			Confirm   string    `json:"confirm" validate:"eqfield=password"`
+			Email     string    `json:"email" validate:"required_without=phone"`
+			Phone     string    `json:"phone" validate:"excluded_with=email"`
+			Items     []struct {
+				Min int `json:"min"`
+				Max int `json:"max" validate:"gtefield=min"`
+			} `json:"items"`
+		}
+	}
+}
+
+func runCrossField(t *testing.T, query map[string]string, headers map[string]string, body string) ValidationErrors {
+	t.Helper()
+
+	var bindErr error
+	r := NewRouter()
+	hs := map[string]string{"Content-Type": "application/json"}
+	for k, v := range headers {
+		hs[k] = v
+	}
+	_, err := r.Inject(InjectOptions{
+		Path:    "/accounts",
+		Method:  "POST",
+		Query:   query,
+		Headers: hs,
+		Body:    strings.NewReader(body),
+		Handler: &RouteOptions{
+			Schema: &crossFieldSchema{},
+			Handler: func(c Context) error {
+				_, bindErr = ValidateAndBind[crossFieldSchema](c)
+				return nil
+			},
+		},
+	})
+	require.Nil(t, err)
+
+	var verrs ValidationErrors
+	if bindErr != nil {
+		require.True(t, errors.As(bindErr, &verrs), bindErr.Error())
+	}
+	return verrs
+}
+
+func TestCrossFieldRules_Pass(t *testing.T) {
+	verrs := runCrossField(t,
+		map[string]string{"from": "1", "to": "5"},
+		map[string]string{"X-Mode": "strict", "X-Token": "abc"},
+		`{
+			"country": "DE", "vat_number": "DE123",
+			"start_date": "2024-01-01T00:00:00Z", "end_date": "2024-02-01T00:00:00Z",
+			"password": "secret", "confirm": "secret",
+			"email": "a@example.com",
+			"items": [{"min": 1, "max": 1}]
+		}`)
+	assert.Empty(t, verrs)
+}
+
+func TestCrossFieldRules_Fail(t *testing.T) {
+	verrs := runCrossField(t,
+		map[string]string{"from": "5", "to": "1"},
+		map[string]string{"X-Mode": "strict"},
+		`{
+			"country": "DE",
+			"start_date": "2024-02-01T00:00:00Z", "end_date": "2024-01-01T00:00:00Z",
+			"password": "secret", "confirm": "other",
+			"email": "a@example.com", "phone": "123"
+		}`)
+
+	byPointer := map[string]ValidationError{}
+	for _, e := range verrs {
+		byPointer[e.Location()+e.Pointer()] = e
+	}
+
+	assert.Equal(t, "required_if", byPointer["header/x-token"].Rule())
+	assert.Equal(t, "gtfield", byPointer["query/to"].Rule())
+	assert.Equal(t, "required_if", byPointer["body/vat_number"].Rule())
+	assert.Equal(t, []string{"country", "DE"}, byPointer["body/vat_number"].Args())
+	assert.Equal(t, "gtfield", byPointer["body/end_date"].Rule())
+	assert.Equal(t, "eqfield", byPointer["body/confirm"].Rule())
+	assert.Equal(t, "excluded_with", byPointer["body/phone"].Rule())
+	assert.Len(t, verrs, 6)
+}
+
+func TestCrossFieldRules_NestedAndConditional(t *testing.T) {
+	// Nested structs resolve siblings within their own object.
+	verrs := runCrossField(t, nil, nil, `{"email": "a@example.com", "items": [{"min": 3, "max": 1}]}`)
+	require.Len(t, verrs, 1)
+	assert.Equal(t, "/items/0/max", verrs[0].Pointer())
+	assert.Equal(t, "gtefield", verrs[0].Rule())
+
+	// required_if only applies when the condition matches.
+	verrs = runCrossField(t, nil, nil, `{"country": "FR", "email": "a@example.com"}`)
+	assert.Empty(t, verrs)
+
+	// required_without fires when neither contact is given.
+	verrs = runCrossField(t, nil, nil, `{"country": "FR"}`)
+	require.Len(t, verrs, 1)
+	assert.Equal(t, "required_without", verrs[0].Rule())
+	assert.Equal(t, "/email", verrs[0].Pointer())
+}
+
+func TestCrossFieldRules_Spec(t *testing.T) {
+	r := NewRouter()
+	r.Post("/accounts", RouteOptions{Schema: &crossFieldSchema{}, Handler: func(c Context) error { return nil }})
+
+	doc := OpenAPISpec(r, DocsOptions{})
+	op := (*doc.Paths)["/accounts"]["post"]
+	body := op.RequestBody.Content["*/*"].Schema
+	assert.Equal(t, "Required when `country` is `DE`.", body.Properties["vat_number"].Description)
+	assert.Equal(t, "Must be greater than `start_date`.", body.Properties["end_date"].Description)
+	assert.Equal(t, "Must be omitted when `email` is present.", body.Properties["phone"].Description)
+}
+
+func TestCrossFieldRules_UnknownField(t *testing.T) {
+	def := &RuleDef{properties: map[string]*RuleDef{}}
+	def.attach("to", &RuleDef{field: "to", rules: []ruleOpts{{rule: "gtfield", args: []string{"from"}}}})
+	assert.ErrorContains(t, def.checkCrossFields(), "unknown field 'from'")
+
+	def.attach("from", &RuleDef{field: "from"})
+	assert.NoError(t, def.checkCrossFields())
+}
@@ -0,0 +1,357 @@
+package gofi
+
+import (
+	"crypto/rand"
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/michaelolof/gofi/utils"
+)
+
+// DefaultFunc provides a dynamic default. It is named with a "$" prefix in default tags and
+// called for every request or response that leaves the field empty:
+//
+//	Id      string    `json:"id" default:"$uuid"`
+//	Created time.Time `json:"created" default:"$now"`
+//
+// The value is converted to the type of the field: a time.Time or a string as text, a number
+// or bool as is, and anything else through its JSON encoding. A literal default that starts
+// with "$" is written with "$$".
+type DefaultFunc func() any
+
+// builtinDefaults are the dynamic defaults available in every default tag.
+var builtinDefaults = map[string]DefaultFunc{
+	"now":  func() any { return time.Now() },
+	"uuid": func() any { return newUUID() },
+}
+
+// lookupDefault finds a dynamic default by name. Built-in defaults take precedence over
+// registered ones.
+func (m *muxOptions) lookupDefault(name string) (DefaultFunc, bool) {
+	if fn, ok := builtinDefaults[name]; ok {
+		return fn, true
+	}
+	if m != nil {
+		fn, ok := m.defaults[name]
+		return fn, ok
+	}
+	return nil, false
+}
+
+// newUUID returns a random (version 4) UUID.
+func newUUID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+// setDefaulted marks whether r or any of its children has a default, so structs whose fields
+// have defaults are bound even when they are left out.
+func (r *RuleDef) setDefaulted() {
+	if r == nil {
+		return
+	}
+
+	r.defaulted = r.defStr != "" || (r.item != nil && r.item.defaulted)
+	for _, p := range r.orderedProps {
+		r.defaulted = r.defaulted || p.defaulted
+	}
+}
+
+// nodeDefault reports whether the default of r is bound from its JSON text rather than from
+// the primitive value parsed at startup.
+func (r *RuleDef) nodeDefault() bool {
+	return r.defStr != "" && (r.defFn != nil || r.defVal == nil || !utils.IsPrimitiveKind(r.kind))
+}
+
+// defaultText returns the default of r as text, calling its provider for dynamic defaults.
+func (r *RuleDef) defaultText() string {
+	if r.defFn == nil {
+		return r.defStr
+	}
+	return r.formatDefault(r.defFn())
+}
+
+// formatDefault converts the value of a dynamic default to text.
+func (r *RuleDef) formatDefault(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case time.Time:
+		if utils.KindIsNumber(r.kind) {
+			return strconv.FormatInt(v.Unix(), 10)
+		}
+		if r.format == utils.TimeObjectFormat && r.pattern != "" {
+			return v.Format(r.pattern)
+		}
+		return v.Format(time.RFC3339Nano)
+	case encoding.TextMarshaler:
+		b, err := v.MarshalText()
+		if err != nil {
+			return ""
+		}
+		return string(b)
+	}
+
+	if rv := reflect.ValueOf(v); utils.IsPrimitiveKind(rv.Kind()) {
+		return fmt.Sprint(v)
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return ""
+	}
+	return string(b)
+}
+
+// defaultJSON returns text, a default of r, as a JSON value. Strings and values read from
+// strings are quoted; composite defaults are JSON literals already.
+func (r *RuleDef) defaultJSON(text string) []byte {
+	if r.kind == reflect.String || (r.format != "" && r.format != utils.RawJSONFormat) {
+		b, _ := json.Marshal(text)
+		return b
+	}
+	return []byte(text)
+}
+
+// defaultValue returns the default of r as a value of typ, or false when it cannot be
+// converted.
+func (r *RuleDef) defaultValue(typ reflect.Type, specs CustomSpecs) (reflect.Value, bool) {
+	if !r.nodeDefault() && typ.Kind() != reflect.Pointer {
+		if dv, err := utils.SafeConvert(reflect.ValueOf(r.defVal), typ); err == nil {
+			return dv, true
+		}
+	}
+	v, err := r.parseDefault(r.defaultText(), typ, specs)
+	return v, err == nil
+}
+
+// parseDefault converts text, a default of r, to a value of typ.
+func (r *RuleDef) parseDefault(text string, typ reflect.Type, specs CustomSpecs) (reflect.Value, error) {
+	out := reflect.New(typ).Elem()
+	v := out
+	for v.Kind() == reflect.Pointer {
+		v.Set(reflect.New(v.Type().Elem()))
+		v = v.Elem()
+	}
+
+	switch {
+	case r.format != "" && specs != nil && specs[string(r.format)] != nil:
+		decoded, err := specs[string(r.format)].Decode(text)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		dv, err := utils.SafeConvert(reflect.ValueOf(decoded), v.Type())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		v.Set(dv)
+
+	case v.Type() == utils.TimeType:
+		pattern := r.pattern
+		if pattern == "" {
+			pattern = time.RFC3339Nano
+		}
+		t, err := time.Parse(pattern, text)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		v.Set(reflect.ValueOf(t))
+
+	case v.Type() == utils.CookieType:
+		v.Set(reflect.ValueOf(http.Cookie{Name: r.field, Value: text}))
+
+	case utils.IsPrimitiveKind(v.Kind()):
+		p, err := utils.PrimitiveFromStr(v.Kind(), text)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		v.Set(reflect.ValueOf(p).Convert(v.Type()))
+
+	default:
+		if err := json.Unmarshal(r.defaultJSON(text), v.Addr().Interface()); err != nil {
+			return reflect.Value{}, err
+		}
+	}
+	return out, nil
+}
+
+// compileDefault checks the static default of r against its rules and returns the value to
+// document as the OpenAPI default. A default that cannot be used is reported and dropped.
+// Dynamic defaults are checked by the rules on every use and are not documented.
+func (s *serveMux) compileDefault(r *RuleDef, name string) any {
+	if r == nil || r.defStr == "" || r.defFn != nil || r.isWrapper() {
+		return nil
+	}
+
+	v, err := r.parseDefault(r.defStr, r.typ, s.opts.customSpecs)
+	if err == nil {
+		err = checkDefault(v, r, []string{name})
+	}
+	if err != nil {
+		s.reg.fail(tagOf("default", r.defStr), err)
+		r.defStr, r.defVal = "", nil
+		return nil
+	}
+
+	if r.format == utils.TimeObjectFormat || (r.format != "" && r.format != utils.RawJSONFormat) {
+		return r.defStr
+	}
+	if utils.IsPrimitiveKind(r.kind) {
+		doc, _ := utils.PrimitiveFromStr(r.kind, r.defStr)
+		return doc
+	}
+	var doc any
+	if err := json.Unmarshal(r.defaultJSON(r.defStr), &doc); err != nil {
+		return r.defStr
+	}
+	return doc
+}
+
+// checkDefault runs the rules of def, and of the items, values and fields it has, on v.
+// Cross-field rules are left to requests since they depend on sibling values.
+func checkDefault(v reflect.Value, def *RuleDef, keys []string) error {
+	if def == nil {
+		return nil
+	}
+
+	var val any
+	if def.defFn == nil && def.defVal != nil && v.Kind() != reflect.Pointer {
+		val = def.defVal
+	} else if def.format == utils.CookieObjectFormat {
+		val = reflect.Indirect(v).FieldByName("Value").Interface() // cookie rules check the value
+	} else if v.IsValid() && v.CanInterface() {
+		val = v.Interface()
+	}
+	rules := make([]ruleOpts, 0, len(def.rules))
+	for _, rule := range def.rules {
+		if !isCrossFieldRule(rule.rule) {
+			rules = append(rules, rule)
+		}
+	}
+	if err := runValidationLazy(val, RequestErr, schemaBody, keys, rules); err != nil {
+		return err
+	}
+
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	if def.format != "" {
+		return nil
+	}
+
+	var errs []error
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			errs = append(errs, checkDefault(v.Index(i), def.item, append(keys, strconv.Itoa(i))))
+		}
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			errs = append(errs, checkDefault(iter.Value(), def.additionalProperties, append(keys, fmt.Sprint(iter.Key().Interface()))))
+		}
+	case reflect.Struct:
+		for _, child := range def.orderedProps {
+			fv := v.FieldByName(child.fieldName)
+			// A child left empty takes its own default when the value is bound.
+			if !fv.IsValid() || (child.defStr != "" && fv.IsZero()) {
+				continue
+			}
+			errs = append(errs, checkDefault(fv, child, append(keys, child.field)))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// applyFormDefaults adds the defaults of the fields of def that values leaves out, under the
+// dotted keys the form parsers bind: "key.child" for the fields of a struct, "key.0.child" for
+// the fields of slice items and one value per item for a slice of scalars.
+func applyFormDefaults(values map[string][]string, def *RuleDef, prefix string) {
+	if def == nil || !def.defaulted {
+		return
+	}
+
+	for _, child := range def.orderedProps {
+		if !child.defaulted {
+			continue
+		}
+
+		key := prefix + child.field
+		sent := hasFormKey(values, key)
+		switch {
+		case child.defStr != "" && !sent:
+			addFormDefault(values, key, child)
+		case child.kind == reflect.Slice && child.item != nil && child.item.kind == reflect.Struct:
+			for i := 0; hasFormKey(values, key+"."+strconv.Itoa(i)); i++ {
+				applyFormDefaults(values, child.item, key+"."+strconv.Itoa(i)+".")
+			}
+		case child.kind == reflect.Struct && child.format == "" && (sent || child.typ.Kind() == reflect.Struct):
+			applyFormDefaults(values, child, key+".")
+		}
+	}
+}
+
+// hasFormKey reports whether values holds key or any key nested under it.
+func hasFormKey(values map[string][]string, key string) bool {
+	if _, ok := values[key]; ok {
+		return true
+	}
+	for k := range values {
+		if strings.HasPrefix(k, key) && len(k) > len(key) && k[len(key)] == '.' {
+			return true
+		}
+	}
+	return false
+}
+
+// addFormDefault adds the default of def under key, flattening JSON literal defaults.
+func addFormDefault(values map[string][]string, key string, def *RuleDef) {
+	text := def.defaultText()
+	if !def.isStructured() {
+		values[key] = []string{text}
+		return
+	}
+
+	var v any
+	dec := json.NewDecoder(strings.NewReader(text))
+	dec.UseNumber()
+	if err := dec.Decode(&v); err != nil {
+		return
+	}
+	flattenFormDefault(values, key, v)
+}
+
+func flattenFormDefault(values map[string][]string, key string, v any) {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, e := range v {
+			flattenFormDefault(values, key+"."+k, e)
+		}
+	case []any:
+		for i, e := range v {
+			switch e.(type) {
+			case map[string]any, []any:
+				flattenFormDefault(values, key+"."+strconv.Itoa(i), e)
+			default:
+				flattenFormDefault(values, key, e)
+			}
+		}
+	case nil:
+	default:
+		values[key] = append(values[key], fmt.Sprint(v))
+	}
+}
@@ -0,0 +1,163 @@
+package gofi
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type defaultsAddress struct {
+	City    string `json:"city" default:"Lagos" validate:"required"`
+	Country string `json:"country" default:"NG" validate:"len=2"`
+}
+
+type defaultsItem struct {
+	Sku string `json:"sku" validate:"required"`
+	Qty int    `json:"qty" default:"1" validate:"min=1"`
+}
+
+type defaultsSchema struct {
+	Request struct {
+		Query struct {
+			Trace string `json:"trace" default:"$uuid"`
+		}
+		Body struct {
+			Name    string           `json:"name" validate:"required"`
+			Address defaultsAddress  `json:"address"`
+			Billing *defaultsAddress `json:"billing"`
+			Tags    []string         `json:"tags" default:"[\"new\",\"hot\"]" validate:"dive,alpha"`
+			Items   []defaultsItem   `json:"items"`
+			Created time.Time        `json:"created" default:"$now"`
+			Tenant  string           `json:"tenant" default:"$tenant"`
+			Literal string           `json:"literal" default:"$$money"`
+			Retries *int             `json:"retries" default:"3"`
+		}
+	}
+}
+
+func bindDefaults(t *testing.T, contentType string, body string) *defaultsSchema {
+	t.Helper()
+
+	var s *defaultsSchema
+	var bindErr error
+	r := NewRouter()
+	r.RegisterDefault("tenant", func() any { return "acme" })
+	_, err := r.Inject(InjectOptions{
+		Path:    "/defaults",
+		Method:  "POST",
+		Headers: map[string]string{"Content-Type": contentType},
+		Body:    strings.NewReader(body),
+		Handler: &RouteOptions{
+			Schema: &defaultsSchema{},
+			Handler: func(c Context) error {
+				s, bindErr = ValidateAndBind[defaultsSchema](c)
+				return nil
+			},
+		},
+	})
+	require.NoError(t, err)
+	require.NoError(t, bindErr)
+	return s
+}
+
+func assertDefaults(t *testing.T, s *defaultsSchema) {
+	t.Helper()
+
+	assert.Len(t, s.Request.Query.Trace, 36)
+	body := s.Request.Body
+	assert.Equal(t, "ada", body.Name)
+	assert.Nil(t, body.Billing)
+	assert.Equal(t, []string{"new", "hot"}, body.Tags)
+	assert.Equal(t, "acme", body.Tenant)
+	assert.Equal(t, "$money", body.Literal)
+	require.NotNil(t, body.Retries)
+	assert.Equal(t, 3, *body.Retries)
+}
+
+func TestDefaults_JSON(t *testing.T) {
+	s := bindDefaults(t, "application/json", `{"name": "ada", "items": [{"sku": "a"}]}`)
+	assertDefaults(t, s)
+	assert.WithinDuration(t, time.Now(), s.Request.Body.Created, time.Minute)
+	assert.Equal(t, defaultsAddress{City: "Lagos", Country: "NG"}, s.Request.Body.Address)
+	assert.Equal(t, []defaultsItem{{Sku: "a", Qty: 1}}, s.Request.Body.Items)
+
+	s = bindDefaults(t, "application/json", `{"name": "ada", "address": {"city": "Abuja"}, "billing": {}, "tags": ["old"]}`)
+	assert.Equal(t, defaultsAddress{City: "Abuja", Country: "NG"}, s.Request.Body.Address)
+	assert.Equal(t, &defaultsAddress{City: "Lagos", Country: "NG"}, s.Request.Body.Billing)
+	assert.Equal(t, []string{"old"}, s.Request.Body.Tags)
+
+	a := bindDefaults(t, "application/json", `{"name": "ada"}`)
+	b := bindDefaults(t, "application/json", `{"name": "ada"}`)
+	assert.NotEqual(t, a.Request.Query.Trace, b.Request.Query.Trace)
+}
+
+func TestDefaults_Form(t *testing.T) {
+	s := bindDefaults(t, "application/x-www-form-urlencoded", "name=ada&items.0.sku=a&address.city=Abuja")
+	assertDefaults(t, s)
+	assert.Equal(t, defaultsAddress{City: "Abuja", Country: "NG"}, s.Request.Body.Address)
+	assert.Equal(t, []defaultsItem{{Sku: "a", Qty: 1}}, s.Request.Body.Items)
+}
+
+func TestDefaults_Multipart(t *testing.T) {
+	s := bindDefaults(t, "multipart/form-data; boundary=boundary123",
+		"--boundary123\r\n"+
+			"Content-Disposition: form-data; name=\"name\"\r\n\r\n"+
+			"ada\r\n"+
+			"--boundary123--\r\n",
+	)
+	assertDefaults(t, s)
+	assert.Equal(t, defaultsAddress{City: "Lagos", Country: "NG"}, s.Request.Body.Address)
+}
+
+func TestDefaults_OpenAPI(t *testing.T) {
+	r := newRouter()
+	r.RegisterDefault("tenant", func() any { return "acme" })
+	cs := r.compileSchema(&defaultsSchema{}, Info{})
+	body := cs.specs.bodySchema
+
+	assert.Equal(t, []any{"new", "hot"}, body.Properties["tags"].Default)
+	assert.Equal(t, "Lagos", body.Properties["address"].Properties["city"].Default)
+	assert.Equal(t, 3, body.Properties["retries"].Default)
+	assert.Equal(t, "$money", body.Properties["literal"].Default)
+	assert.Nil(t, body.Properties["created"].Default)
+	assert.Nil(t, body.Properties["tenant"].Default)
+}
+
+func TestDefaults_Response(t *testing.T) {
+	type responseSchema struct {
+		Ok struct {
+			Body struct {
+				Id      string         `json:"id" default:"$uuid"`
+				Tags    []string       `json:"tags" default:"[\"a\"]"`
+				Limits  map[string]int `json:"limits" default:"{\"daily\":10}"`
+				Retries *int           `json:"retries" default:"3"`
+			}
+		}
+	}
+
+	r := NewRouter()
+	res, err := r.Inject(InjectOptions{
+		Path:   "/defaults",
+		Method: "GET",
+		Handler: &RouteOptions{
+			Schema: &responseSchema{},
+			Handler: func(c Context) error {
+				var s responseSchema
+				return c.Send(200, s.Ok)
+			},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, 200, res.StatusCode, string(res.Body))
+
+	var got map[string]any
+	require.NoError(t, json.Unmarshal(res.Body, &got))
+	assert.Len(t, got["id"], 36)
+	assert.Equal(t, []any{"a"}, got["tags"])
+	assert.Equal(t, map[string]any{"daily": float64(10)}, got["limits"])
+	assert.Equal(t, float64(3), got["retries"])
+}
@@ -0,0 +1,212 @@
+package gofi
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type diveSchema struct {
+	Request struct {
+		Body struct {
+			Tags   []string          `json:"tags" validate:"max=5,dive,alphanum,max=20"`
+			Matrix [][]int           `json:"matrix" validate:"dive,min=1,dive,gte=0"`
+			Labels map[string]string `json:"labels" validate:"dive,keys,uuid,endkeys,not_empty"`
+		}
+	}
+	Ok struct {
+		Body struct {
+			Labels map[string]int `json:"labels" validate:"dive,keys,alpha,endkeys,gt=0"`
+		}
+	}
+}
+
+const diveKey = "0b6f1a5e-6a7c-4b8e-9a51-9f1f3c3e2d10"
+
+func bindDive(t *testing.T, body string) (*diveSchema, error) {
+	t.Helper()
+
+	var s *diveSchema
+	var bindErr error
+	r := NewRouter()
+	_, err := r.Inject(InjectOptions{
+		Path:    "/dive",
+		Method:  "POST",
+		Headers: map[string]string{"Content-Type": "application/json"},
+		Body:    strings.NewReader(body),
+		Handler: &RouteOptions{
+			Schema: &diveSchema{},
+			Handler: func(c Context) error {
+				s, bindErr = ValidateAndBind[diveSchema](c)
+				return nil
+			},
+		},
+	})
+	require.Nil(t, err)
+	return s, bindErr
+}
+
+func TestDive_JSONRequest(t *testing.T) {
+	s, err := bindDive(t, `{"tags":["go","web"],"matrix":[[1,2],[0]],"labels":{"`+diveKey+`":"x"}}`)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"go", "web"}, s.Request.Body.Tags)
+	assert.Equal(t, [][]int{{1, 2}, {0}}, s.Request.Body.Matrix)
+	assert.Equal(t, "x", s.Request.Body.Labels[diveKey])
+
+	cases := []struct {
+		name    string
+		body    string
+		pointer string
+		rule    string
+	}{
+		{"item rule", `{"tags":["go","not valid"]}`, "/tags/1", "alphanum"},
+		{"nested slice rule", `{"matrix":[[1],[]]}`, "/matrix/1", "min"},
+		{"nested item rule", `{"matrix":[[1,-1]]}`, "/matrix/0/1", "gte"},
+		{"map key rule", `{"labels":{"nope":"x"}}`, "/labels/nope", "uuid"},
+		{"map value rule", `{"labels":{"` + diveKey + `":""}}`, "/labels/" + diveKey, "not_empty"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := bindDive(t, tc.body)
+			var verr ValidationError
+			require.True(t, errors.As(err, &verr), "expected a validation error")
+			assert.Equal(t, tc.pointer, verr.Pointer())
+			assert.Equal(t, tc.rule, verr.Rule())
+		})
+	}
+}
+
+func TestDive_FormRequest(t *testing.T) {
+	type formDiveSchema struct {
+		Request struct {
+			Body struct {
+				Tags []string `json:"tags" validate:"dive,alpha"`
+			}
+		}
+	}
+
+	var bindErr error
+	r := NewRouter()
+	_, err := r.Inject(InjectOptions{
+		Path:    "/dive",
+		Method:  "POST",
+		Headers: map[string]string{"Content-Type": "application/x-www-form-urlencoded"},
+		Body:    strings.NewReader("tags=go&tags=v2"),
+		Handler: &RouteOptions{
+			Schema: &formDiveSchema{},
+			Handler: func(c Context) error {
+				_, bindErr = ValidateAndBind[formDiveSchema](c)
+				return nil
+			},
+		},
+	})
+	require.Nil(t, err)
+
+	var verr ValidationError
+	require.True(t, errors.As(bindErr, &verr))
+	assert.Equal(t, "/tags/1", verr.Pointer())
+	assert.Equal(t, "alpha", verr.Rule())
+}
+
+func TestDive_Response(t *testing.T) {
+	send := func(labels map[string]int) error {
+		var sendErr error
+		r := NewRouter()
+		_, err := r.Inject(InjectOptions{
+			Path:   "/dive",
+			Method: "GET",
+			Handler: &RouteOptions{
+				Schema: &diveSchema{},
+				Handler: func(c Context) error {
+					var s diveSchema
+					s.Ok.Body.Labels = labels
+					sendErr = c.Send(200, s.Ok)
+					return nil
+				},
+			},
+		})
+		require.Nil(t, err)
+		return sendErr
+	}
+
+	assert.NoError(t, send(map[string]int{"one": 1}))
+	assert.ErrorContains(t, send(map[string]int{"one": 0}), "labels.one")
+	assert.ErrorContains(t, send(map[string]int{"n0": 1}), "labels.n0")
+}
+
+func TestDive_Spec(t *testing.T) {
+	r := NewRouter()
+	r.Post("/dive", RouteOptions{Schema: &diveSchema{}, Handler: func(c Context) error { return nil }})
+
+	doc := OpenAPISpec(r, DocsOptions{})
+	body := (*doc.Paths)["/dive"]["post"].RequestBody.Content["*/*"].Schema
+
+	tags := body.Properties["tags"]
+	require.NotNil(t, tags.Items)
+	require.NotNil(t, tags.Items.Maximum)
+	assert.Equal(t, 20.0, *tags.Items.Maximum)
+
+	matrix := body.Properties["matrix"]
+	require.NotNil(t, matrix.Items)
+	require.NotNil(t, matrix.Items.Items)
+	require.NotNil(t, matrix.Items.Items.Minimum)
+	assert.Equal(t, 0.0, *matrix.Items.Items.Minimum)
+
+	labels := body.Properties["labels"]
+	assert.Equal(t, "Keys must satisfy `uuid`.", labels.Description)
+}
+
+func TestDive_StructItems(t *testing.T) {
+	type line struct {
+		Sku string `json:"sku"`
+	}
+	type schema struct {
+		Request struct {
+			Body struct {
+				Lines []*line `json:"lines" validate:"dive,required"`
+				Rows  []line  `json:"rows" validate:"dive,required"`
+				Ship  *line   `json:"ship" validate:"required"`
+			}
+		}
+	}
+
+	bind := func(body string) (*schema, error) {
+		var s *schema
+		var bindErr error
+		_, err := NewRouter().Inject(InjectOptions{
+			Path:    "/lines",
+			Method:  "POST",
+			Headers: map[string]string{"Content-Type": "application/json"},
+			Body:    strings.NewReader(body),
+			Handler: &RouteOptions{
+				Schema: &schema{},
+				Handler: func(c Context) error {
+					s, bindErr = ValidateAndBind[schema](c)
+					return nil
+				},
+			},
+		})
+		require.NoError(t, err)
+		return s, bindErr
+	}
+
+	s, err := bind(`{"lines":[{"sku":"a"}],"rows":[{"sku":"b"}],"ship":{}}`)
+	require.NoError(t, err)
+	assert.Equal(t, []*line{{Sku: "a"}}, s.Request.Body.Lines)
+	assert.Equal(t, []line{{Sku: "b"}}, s.Request.Body.Rows)
+
+	for body, pointer := range map[string]string{
+		`{"lines":[{"sku":"a"},null],"ship":{}}`: "/lines/1",
+		`{"rows":[null],"ship":{}}`:              "/rows/0",
+		`{"lines":[]}`:                           "/ship",
+	} {
+		_, err = bind(body)
+		var verr ValidationError
+		require.True(t, errors.As(err, &verr), body)
+		assert.Equal(t, "required", verr.Rule(), body)
+		assert.Equal(t, pointer, verr.Pointer(), body)
+	}
+}
@@ -29,9 +29,13 @@ type DocsOptions struct {
 func (d *DocsOptions) getMatchingDocs(m *serveMux, match func(url string) bool) Docs {
 	mpaths := make(docsPaths)
 	for url, v := range m.paths {
-		if match(url) {
-			mpaths[url] = v
+		if !match(url) {
+			continue
+		}
+		if m.opts.problemDocs {
+			v = withProblemResponses(v)
 		}
+		mpaths[url] = v
 	}
 	return Docs{
 		OpenApi:     "3.0.3",
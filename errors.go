@@ -3,12 +3,30 @@ package gofi
 // HTTPError is a structured error that carries an HTTP status code.
 // Handlers and middlewares can return this to let the default error handler
 // preserve an explicit response status.
+//
+// The remaining fields map onto RFC 9457 problem details and are used by
+// ProblemErrorHandler. They are all optional: Title falls back to the status
+// text and Detail to Message.
 type HTTPError struct {
 	Code    int
 	Message string
+
+	// Type is a URI reference identifying the problem type. Defaults to "about:blank".
+	Type string
+	// Title is a short, human-readable summary of the problem type.
+	Title string
+	// Detail is a human-readable explanation specific to this occurrence.
+	Detail string
+	// Instance is a URI reference identifying this occurrence of the problem.
+	Instance string
+	// Extensions are additional members written alongside the standard ones.
+	Extensions map[string]any
 }
 
 func (e *HTTPError) Error() string {
+	if e.Message == "" {
+		return e.Detail
+	}
 	return e.Message
 }
 
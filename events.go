@@ -0,0 +1,296 @@
+package gofi
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/michaelolof/gofi/cont"
+)
+
+// DefaultEventKeepAlive is how often SendEvents writes a keep-alive comment while the
+// handler is idle. Override it per stream with EventWriter.SetKeepAlive.
+var DefaultEventKeepAlive = 15 * time.Second
+
+// Event is a single server-sent event. Name must match an event declared on the
+// response schema's Events field, and Data is validated against that event's payload rules.
+type Event struct {
+	ID    string
+	Name  string
+	Data  any
+	Retry time.Duration
+}
+
+// EventWriter writes typed server-sent events for a response schema that declares an Events field:
+//
+//	Ok struct {
+//		Events struct {
+//			Message ChatMessage `json:"message"`
+//			Typing  TypingEvent `json:"typing"`
+//		}
+//	}
+//
+// Each field is one event type; the json name is the event name and the field type is its payload.
+type EventWriter struct {
+	mu          sync.Mutex
+	w           *bufio.Writer
+	pc          *parserContext
+	rules       *RuleDef
+	lastEventID string
+	err         error
+
+	keepAlive *time.Ticker
+	stop      chan struct{}
+	done      chan struct{}
+	// paused is set under mu by SetKeepAlive so a tick already in flight is dropped.
+	paused bool
+}
+
+// LastEventID returns the Last-Event-ID request header sent by a reconnecting client.
+// Handlers use it to resume the stream after the last event the client received.
+func (e *EventWriter) LastEventID() string {
+	return e.lastEventID
+}
+
+// Send writes an event with the given name and payload.
+func (e *EventWriter) Send(name string, data any) error {
+	return e.Write(Event{Name: name, Data: data})
+}
+
+// Write validates and encodes ev and flushes it to the client.
+func (e *EventWriter) Write(ev Event) error {
+	prop, ok := e.rules.properties[ev.Name]
+	if !ok {
+		return newErrReport(ResponseErr, schemaEvents, ev.Name, "oneof", fmt.Errorf("event '%s' is not declared on the response schema", ev.Name))
+	}
+
+	if strings.ContainsAny(ev.ID, "\r\n\x00") {
+		return newErrReport(ResponseErr, schemaEvents, ev.Name, "typeMismatch", errors.New("event id must not contain line breaks or NUL"))
+	}
+
+	data, err := e.encodeData(ev.Data, prop)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	if ev.ID != "" {
+		buf.WriteString("id: " + ev.ID + "\n")
+	}
+	buf.WriteString("event: " + ev.Name + "\n")
+	if ev.Retry > 0 {
+		buf.WriteString("retry: " + strconv.FormatInt(ev.Retry.Milliseconds(), 10) + "\n")
+	}
+	for _, line := range strings.Split(data, "\n") {
+		buf.WriteString("data: " + strings.TrimSuffix(line, "\r") + "\n")
+	}
+	buf.WriteByte('\n')
+
+	return e.write(buf.Bytes())
+}
+
+// Retry tells the client how long to wait before reconnecting if the stream drops.
+func (e *EventWriter) Retry(d time.Duration) error {
+	return e.write([]byte("retry: " + strconv.FormatInt(d.Milliseconds(), 10) + "\n\n"))
+}
+
+// Comment writes an SSE comment line. Clients ignore comments; they are useful to keep
+// intermediaries from closing an idle connection.
+func (e *EventWriter) Comment(text string) error {
+	var buf bytes.Buffer
+	for _, line := range strings.Split(text, "\n") {
+		buf.WriteString(": " + line + "\n")
+	}
+	buf.WriteByte('\n')
+	return e.write(buf.Bytes())
+}
+
+// SetKeepAlive changes how often keep-alive comments are written. A zero or negative
+// duration pauses keep-alives; the runKeepAlive goroutine keeps running until the stream
+// closes, so a later call with a positive duration resumes them.
+func (e *EventWriter) SetKeepAlive(d time.Duration) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.paused = d <= 0
+	if e.paused {
+		e.keepAlive.Stop()
+		return
+	}
+	e.keepAlive.Reset(d)
+}
+
+func (e *EventWriter) encodeData(data any, prop *RuleDef) (string, error) {
+	val := reflect.ValueOf(data)
+	if val.IsValid() && prop.typ != nil {
+		want, got := prop.typ, val.Type()
+		for want.Kind() == reflect.Pointer {
+			want = want.Elem()
+		}
+		for got.Kind() == reflect.Pointer {
+			got = got.Elem()
+		}
+		if want.Kind() != reflect.Interface && want != got {
+			return "", newErrReport(ResponseErr, schemaEvents, prop.field, "typeMismatch", fmt.Errorf("event '%s' expects a %s payload", prop.field, want))
+		}
+	}
+
+	// Plain string payloads are sent as-is rather than as a quoted JSON string.
+	if _, isCustom := e.pc.CustomSpecs().Find(string(prop.format)); !isCustom && prop.kind == reflect.String && val.Kind() == reflect.String {
+		if err := runValidation(data, ResponseErr, schemaEvents, prop.field, prop.rules); err != nil {
+			return "", err
+		}
+		s := val.String()
+		if s == "" && prop.defStr != "" {
+			s = prop.defaultText()
+		}
+		return s, nil
+	}
+
+	var j JSONBodyParser
+	var buf bytes.Buffer
+	if err := j.encodeFieldValue(e.pc, &buf, val, prop, []string{prop.field}); err != nil {
+		var verr *errReport
+		if errors.As(err, &verr) {
+			retagErrReports(err, schemaEvents)
+			return "", err
+		}
+		return "", newErrReport(ResponseErr, schemaEvents, prop.field, "encoder", err)
+	}
+	return buf.String(), nil
+}
+
+func (e *EventWriter) write(b []byte) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.writeLocked(b)
+}
+
+func (e *EventWriter) writeLocked(b []byte) error {
+	if e.err != nil {
+		return e.err
+	}
+	if _, err := e.w.Write(b); err != nil {
+		e.err = err
+		return err
+	}
+	if err := e.w.Flush(); err != nil {
+		e.err = err
+		return err
+	}
+	return nil
+}
+
+func (e *EventWriter) runKeepAlive() {
+	defer close(e.done)
+	for {
+		select {
+		case <-e.stop:
+			return
+		case <-e.keepAlive.C:
+			e.mu.Lock()
+			var err error
+			if !e.paused {
+				err = e.writeLocked([]byte(": keep-alive\n\n"))
+			}
+			e.mu.Unlock()
+			if err != nil {
+				return
+			}
+		}
+	}
+}
+
+func (c *context) SendEvents(code int, obj any, fn func(ew *EventWriter) error) error {
+	rules, err := c.prepareStream(code, obj)
+	if err != nil {
+		return err
+	}
+
+	edef, ok := rules[string(schemaEvents)]
+	if !ok {
+		return newErrReport(ResponseErr, schemaEvents, "", "required", errors.New("no Events schema defined for status code "+strconv.Itoa(code)))
+	}
+
+	contentType := c.rules().respContent(code)
+	c.fctx.Response.Header.Set("Content-Type", string(contentType))
+	c.fctx.Response.SetStatusCode(code)
+
+	lastEventID := c.HeaderVal("Last-Event-ID")
+	pc := c.streamParserContext()
+	return c.SetBodyStreamWriter(func(w *bufio.Writer) error {
+		ew := &EventWriter{
+			w:           w,
+			pc:          pc,
+			rules:       &edef,
+			lastEventID: lastEventID,
+			stop:        make(chan struct{}),
+			done:        make(chan struct{}),
+		}
+
+		ew.keepAlive = time.NewTicker(time.Hour)
+		ew.SetKeepAlive(DefaultEventKeepAlive)
+		go ew.runKeepAlive()
+
+		err := fn(ew)
+
+		// The writer is only valid until this callback returns, so wait for the
+		// keep-alive goroutine to exit before handing it back.
+		close(ew.stop)
+		<-ew.done
+		ew.keepAlive.Stop()
+		return err
+	})
+}
+
+// retagErrReports points validation errors produced by the shared JSON encoder at the Events schema field.
+func retagErrReports(err error, field schemaField) {
+	switch e := err.(type) {
+	case *errReport:
+		e.field = field
+	case interface{ Unwrap() []error }:
+		for _, ie := range e.Unwrap() {
+			retagErrReports(ie, field)
+		}
+	}
+}
+
+// eventStreamSchema documents a text/event-stream response as a sequence of the declared events.
+// Each entry carries the event name as a single-value enum alongside its payload schema.
+func eventStreamSchema(events openapiSchema) openapiSchema {
+	names := make([]string, 0, len(events.Properties))
+	for name := range events.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	oneOf := make([]openapiSchema, 0, len(names))
+	for _, name := range names {
+		oneOf = append(oneOf, openapiSchema{
+			Title: name,
+			Type:  "object",
+			Properties: map[string]openapiSchema{
+				"event": {Type: "string", Enum: []any{name}},
+				"data":  events.Properties[name],
+				"id":    {Type: "string"},
+				"retry": {Type: "integer", Description: "Reconnection delay in milliseconds"},
+			},
+			Required: []string{"event", "data"},
+		})
+	}
+
+	return openapiSchema{
+		Type:           "array",
+		Description:    "Server-sent event stream. Each item is one event.",
+		Items:          &openapiSchema{OneOf: oneOf},
+		ParentRequired: events.ParentRequired,
+		mediaType:      string(cont.TextEventStream),
+	}
+}
@@ -0,0 +1,141 @@
+package gofi
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type chatMessage struct {
+	ID   int    `json:"id" validate:"required"`
+	Text string `json:"text" validate:"max=20"`
+}
+
+type feedSchema struct {
+	Ok struct {
+		Header struct {
+			CacheControl string `json:"cache-control" default:"no-cache"`
+		}
+		Events struct {
+			Message chatMessage `json:"message"`
+			Status  string      `json:"status" validate:"oneof=online offline"`
+		}
+	}
+}
+
+func TestSendEvents_WritesTypedEvents(t *testing.T) {
+	mux := NewRouter()
+	var lastID string
+	handler := RouteOptions{
+		Schema: &feedSchema{},
+		Handler: func(c Context) error {
+			var s feedSchema
+			return c.SendEvents(200, s.Ok, func(ew *EventWriter) error {
+				lastID = ew.LastEventID()
+				if err := ew.Retry(3 * time.Second); err != nil {
+					return err
+				}
+				if err := ew.Write(Event{ID: "8", Name: "message", Data: chatMessage{ID: 8, Text: "hi"}}); err != nil {
+					return err
+				}
+				return ew.Send("status", "online")
+			})
+		},
+	}
+
+	res, err := mux.Inject(InjectOptions{
+		Path:    "/feed",
+		Method:  "GET",
+		Headers: map[string]string{"Last-Event-ID": "7"},
+		Handler: &handler,
+	})
+	require.Nil(t, err)
+	assert.Equal(t, 200, res.StatusCode)
+	assert.Equal(t, "text/event-stream", res.Header("Content-Type"))
+	assert.Equal(t, "no-cache", res.Header("Cache-Control"))
+	assert.Equal(t, "7", lastID)
+	assert.Equal(t,
+		"retry: 3000\n\nid: 8\nevent: message\ndata: {\"id\":8,\"text\":\"hi\"}\n\nevent: status\ndata: online\n\n",
+		string(res.Body),
+	)
+}
+
+func TestSendEvents_ValidatesEvents(t *testing.T) {
+	mux := NewRouter()
+	var undeclared, invalid, mismatched error
+	handler := RouteOptions{
+		Schema: &feedSchema{},
+		Handler: func(c Context) error {
+			var s feedSchema
+			return c.SendEvents(200, s.Ok, func(ew *EventWriter) error {
+				undeclared = ew.Send("typing", "x")
+				invalid = ew.Send("message", chatMessage{ID: 1, Text: strings.Repeat("x", 30)})
+				mismatched = ew.Send("message", "not a message")
+				return nil
+			})
+		},
+	}
+
+	res, err := mux.Inject(InjectOptions{Path: "/feed", Method: "GET", Handler: &handler})
+	require.Nil(t, err)
+	assert.Empty(t, string(res.Body))
+
+	var verr ValidationError
+	require.ErrorAs(t, undeclared, &verr)
+	assert.Equal(t, "typing", verr.SchemaValue())
+
+	require.ErrorAs(t, invalid, &verr)
+	assert.Equal(t, "max", verr.Rule())
+	assert.Equal(t, schemaEvents, verr.SchemaType())
+	assert.Equal(t, "message.text", verr.SchemaValue())
+
+	require.ErrorAs(t, mismatched, &verr)
+	assert.Equal(t, "typeMismatch", verr.Rule())
+}
+
+func TestSendEvents_KeepAlive(t *testing.T) {
+	mux := NewRouter()
+	handler := RouteOptions{
+		Schema: &feedSchema{},
+		Handler: func(c Context) error {
+			var s feedSchema
+			return c.SendEvents(200, s.Ok, func(ew *EventWriter) error {
+				ew.SetKeepAlive(5 * time.Millisecond)
+				time.Sleep(30 * time.Millisecond)
+				ew.SetKeepAlive(0)
+				return ew.Send("status", "offline")
+			})
+		},
+	}
+
+	res, err := mux.Inject(InjectOptions{Path: "/feed", Method: "GET", Handler: &handler})
+	require.Nil(t, err)
+	assert.True(t, strings.HasPrefix(string(res.Body), ": keep-alive\n\n"), string(res.Body))
+	assert.True(t, strings.HasSuffix(string(res.Body), "event: status\ndata: offline\n\n"), string(res.Body))
+}
+
+func TestSendEvents_DocumentsEventStream(t *testing.T) {
+	r := newRouter()
+	cs := r.compileSchema(&feedSchema{}, Info{})
+	cs.specs.normalize("GET", "/feed")
+
+	media, ok := cs.specs.Responses["200"].Content["text/event-stream"]
+	require.True(t, ok)
+	assert.Equal(t, "array", media.Schema.Type)
+	require.NotNil(t, media.Schema.Items)
+	require.Len(t, media.Schema.Items.OneOf, 2)
+
+	message := media.Schema.Items.OneOf[0]
+	assert.Equal(t, "message", message.Title)
+	assert.Equal(t, []any{"message"}, message.Properties["event"].Enum)
+	assert.Contains(t, message.Properties["data"].Properties, "text")
+}
+
+func TestSendEvents_ContentTypeFromSchema(t *testing.T) {
+	r := newRouter()
+	cs := r.compileSchema(&feedSchema{}, Info{})
+	assert.Equal(t, "text/event-stream", string(cs.rules.respContent(200)))
+}
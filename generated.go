@@ -0,0 +1,930 @@
+package gofi
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/michaelolof/gofi/utils"
+	"github.com/valyala/fastjson"
+)
+
+// GeneratedSchema is implemented by the code that cmd/gofigen writes for a route schema. When
+// the schema of a route implements it, ValidateAndBind and Context.Send run the generated
+// code instead of walking the schema with reflection. The rules still come from the struct
+// tags, so only changes to the Go types of a schema need the code to be generated again.
+//
+// The methods are called by gofi and are not meant to be called directly.
+type GeneratedSchema interface {
+	// GofiFields lists the schema fields the generated code handles, as "Path kind" entries.
+	// The path is made of Go field names, with "[]" for slice items and "{}" for map values.
+	// The kind is the reflect.Kind of the field, or "reflect" for fields left to reflection.
+	GofiFields() []string
+	// GofiBind validates the request and binds it to the receiver.
+	GofiBind(b *GenBinder)
+	// GofiEncode validates and encodes the body of the response struct obj, which belongs
+	// to the response field key. It returns false when it has no code for the response.
+	GofiEncode(e *GenEncoder, key string, obj any) (bool, error)
+}
+
+// JSONValue is a parsed JSON value, as handed to generated code.
+type JSONValue = fastjson.Value
+
+// JSONObject is a parsed JSON object, as handed to generated code.
+type JSONObject = fastjson.Object
+
+// genSchema holds the rules of each field listed by the GofiFields of a schema.
+type genSchema struct {
+	schema GeneratedSchema
+	defs   []*RuleDef
+	parts  []schemaField
+	// reflect marks the fields the generated code leaves to the reflective path.
+	reflect []bool
+	// rest holds the parameters the generated code does not bind.
+	rest    []genParam
+	encodes map[string]bool
+}
+
+type genParam struct {
+	part schemaField
+	def  *RuleDef
+}
+
+// compileGenerated matches the fields of the generated code of a schema with its compiled rules.
+// Generated code that does not match the schema is an error, as it would bind or encode the
+// wrong fields.
+func compileGenerated(sRules *schemaRules, gs GeneratedSchema, typ reflect.Type) (*genSchema, error) {
+	outdated := func(format string, args ...any) error {
+		return errors.New("generated code of " + typ.String() + " is out of date: " + fmt.Sprintf(format, args...) + ". run go generate")
+	}
+
+	fields := gs.GofiFields()
+	gen := &genSchema{
+		schema:  gs,
+		defs:    make([]*RuleDef, len(fields)),
+		parts:   make([]schemaField, len(fields)),
+		reflect: make([]bool, len(fields)),
+		encodes: make(map[string]bool),
+	}
+
+	roots := make(map[string]*RuleDef)
+	kinds := make(map[string]string, len(fields))
+	for i, f := range fields {
+		path, kind, _ := strings.Cut(f, " ")
+		if key, _, _ := strings.Cut(path, "."); key != string(schemaReq) {
+			if _, ok := statuses[key]; !ok {
+				continue // not a response field, so never encoded
+			}
+		}
+		def, part, err := sRules.genField(roots, path)
+		if err != nil {
+			return nil, outdated("%s", err)
+		}
+		if kind != "reflect" && kind != def.kind.String() {
+			return nil, outdated("%s is a %s, not a %s", path, def.kind, kind)
+		}
+		kinds[path] = kind
+		gen.defs[i], gen.parts[i], gen.reflect[i] = def, part, kind == "reflect"
+
+		key, rest, _ := strings.Cut(path, ".")
+		if rest != string(schemaBody) || kind != reflect.Struct.String() || def.format != "" || def.isWrapper() {
+			continue
+		}
+		if key != string(schemaReq) {
+			gen.encodes[key] = true
+		}
+	}
+
+	// Every field of a generated body must be handled by the generated code.
+	var walk func(def *RuleDef, path string) error
+	walk = func(def *RuleDef, path string) error {
+		kind, ok := kinds[path]
+		if !ok {
+			return outdated("%s is missing", path)
+		}
+		if kind == "reflect" {
+			return nil
+		}
+		for _, child := range def.orderedProps {
+			if err := walk(child, path+"."+child.fieldName); err != nil {
+				return err
+			}
+		}
+		if def.item != nil && (def.kind == reflect.Slice || def.kind == reflect.Array) {
+			if err := walk(def.item, path+".[]"); err != nil {
+				return err
+			}
+		}
+		if def.additionalProperties != nil && def.kind == reflect.Map {
+			return walk(def.additionalProperties, path+".{}")
+		}
+		return nil
+	}
+	for path, def := range roots {
+		if err := walk(def, path); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, part := range []schemaField{schemaHeaders, schemaQuery, schemaPath, schemaCookies} {
+		pdef := sRules.getReqRules(part)
+		if pdef == nil {
+			continue
+		}
+		for _, def := range pdef.orderedProps {
+			if _, ok := kinds[string(schemaReq)+"."+string(part)+"."+def.fieldName]; !ok {
+				gen.rest = append(gen.rest, genParam{part: part, def: def})
+			}
+		}
+	}
+	return gen, nil
+}
+
+// genField finds the rules of a field listed by GofiFields.
+func (s *schemaRules) genField(roots map[string]*RuleDef, path string) (*RuleDef, schemaField, error) {
+	segs := strings.Split(path, ".")
+	if len(segs) < 2 {
+		return nil, "", fmt.Errorf("%s is not a schema field", path)
+	}
+
+	var part schemaField
+	var def *RuleDef
+	root := segs[0] + "." + segs[1]
+	if segs[0] == string(schemaReq) {
+		part = schemaField(segs[1])
+		if part != schemaBody {
+			pdef := s.getReqRules(part)
+			if pdef == nil || len(segs) != 3 {
+				return nil, "", fmt.Errorf("%s is not a schema field", path)
+			}
+			for _, child := range pdef.orderedProps {
+				if child.fieldName == segs[2] {
+					return child, part, nil
+				}
+			}
+			return nil, "", fmt.Errorf("%s is not a schema field", path)
+		}
+		if r, ok := s.req[root]; ok {
+			def = &r
+		}
+	} else if segs[1] == string(schemaBody) {
+		if r, ok := s.responses[segs[0]][segs[1]]; ok {
+			def = &r
+		}
+	}
+	if def == nil {
+		return nil, "", fmt.Errorf("%s is not a schema field", root)
+	}
+	if r, ok := roots[root]; ok {
+		def = r
+	} else {
+		roots[root] = def
+	}
+
+	for _, seg := range segs[2:] {
+		switch seg {
+		case "[]":
+			def = def.item
+		case "{}":
+			def = def.additionalProperties
+		default:
+			var child *RuleDef
+			for _, c := range def.orderedProps {
+				if c.fieldName == seg {
+					child = c
+					break
+				}
+			}
+			def = child
+		}
+		if def == nil {
+			return nil, "", fmt.Errorf("%s is not a schema field", path)
+		}
+	}
+	return def, part, nil
+}
+
+// GenBinder validates and binds a request for generated code. Fields are referred to by their
+// index in GofiFields.
+type GenBinder struct {
+	c      *context
+	gen    *genSchema
+	schema any
+	req    reflect.Value
+	pc     parserContext
+	json   *JSONBodyParser
+	errs   ValidationErrors
+}
+
+// request returns the Request struct of the bound schema, for the fields left to reflection.
+func (b *GenBinder) request() reflect.Value {
+	if !b.req.IsValid() {
+		b.req = reflect.ValueOf(b.schema).Elem().FieldByName(string(schemaReq))
+	}
+	return b.req
+}
+
+func (b *GenBinder) fail(part schemaField, err error) {
+	b.errs = appendValidationErrors(b.errs, err, RequestErr, part)
+}
+
+// Param validates the header, query, path or cookie parameter i. It returns the value to bind,
+// or false when there is nothing to bind. Parameters the generated code cannot bind are bound
+// here.
+func (b *GenBinder) Param(i int) (any, bool) {
+	def, part := b.gen.defs[i], b.gen.parts[i]
+	if b.gen.reflect[i] || def.isWrapper() || def.isMultiValue() || def.style != "" || def.format != "" {
+		b.fail(part, b.c.bindParamDef(part, def, true, b.request()))
+		return nil, false
+	}
+
+	var val any
+	var err error
+	switch part {
+	case schemaHeaders:
+		val, err = b.c.validateStr(part, b.c.headerGet(def.field), def)
+	case schemaQuery:
+		val, err = b.c.validateStr(part, b.c.queryGet(def.field), def)
+	case schemaPath:
+		val, err = b.c.validateStr(part, b.c.params.Get(def.field), def)
+	case schemaCookies:
+		var cv *http.Cookie
+		cv, err = b.c.requestCookie(def)
+		if err == nil && cv != nil {
+			val, err = b.c.cookieValue(def, cv)
+		}
+	}
+	if err != nil {
+		b.fail(part, err)
+		return nil, false
+	}
+	return val, val != nil
+}
+
+// Bound reports a parameter value of field i that could not be stored.
+func (b *GenBinder) Bound(i int, err error) {
+	if err != nil {
+		slog.Error(newErrReport(ResponseErr, schemaBody, b.gen.defs[i].field, "typeMismatch", err).Error())
+	}
+}
+
+// Body reads and parses the JSON request body of field i, and checks its root object. It
+// returns false when there is nothing more to bind, and binds bodies the generated code cannot
+// bind itself.
+func (b *GenBinder) Body(i int) (*JSONValue, bool, error) {
+	def := b.gen.defs[i]
+	bs, sz, err := b.c.requestBody(def)
+	if err != nil || bs == nil {
+		return nil, false, err
+	}
+
+	j, ok := sz.(*JSONBodyParser)
+	if !ok || b.gen.reflect[i] || def.kind != reflect.Struct || def.format != "" || def.isWrapper() {
+		req := b.request()
+		return nil, false, sz.ValidateAndDecodeRequest(io.NopCloser(bytes.NewReader(bs)), RequestOptions{
+			ShouldBind:  true,
+			Context:     &b.pc,
+			SchemaPtr:   b.schema,
+			Body:        &req,
+			SchemaRules: def,
+		})
+	}
+	b.json = j
+
+	limit := j.MaxRequestSize
+	if limit == 0 {
+		limit = 1048576 // defaultReqSize
+	}
+	if int64(len(bs)) > limit {
+		bs = bs[:limit]
+	}
+
+	n, err := b.pc.getParser().ParseBytes(bs)
+	if err != nil {
+		return nil, false, newErrReport(RequestErr, schemaBody, "", "parser", err)
+	}
+
+	ok, err = b.object(i, n, nil)
+	if err != nil {
+		return nil, false, err
+	} else if !ok {
+		return nil, false, newErrReport(RequestErr, schemaBody, "", "parser", errors.New("couldn't parse request body"))
+	}
+	return n, true, nil
+}
+
+// BodyDone records the outcome of binding the request body.
+func (b *GenBinder) BodyDone(err error) {
+	b.fail(schemaBody, err)
+}
+
+// Field returns the node and the key path of the struct field i in the object n.
+func (b *GenBinder) Field(i int, n *JSONValue, k []string) (*JSONValue, []string) {
+	field := b.gen.defs[i].field
+	return n.Get(field), append(k, field)
+}
+
+// delegated reports whether field i is bound or encoded by the reflective path.
+func (g *genSchema) delegated(i int) bool {
+	def := g.defs[i]
+	return g.reflect[i] || def.isWrapper() || def.format != "" || def.kind == reflect.Interface || def.nodeDefault()
+}
+
+// Reflect binds the node n to dst, a pointer to field i, with the reflective path.
+func (b *GenBinder) Reflect(i int, n *JSONValue, k []string, dst any) error {
+	body := reflect.ValueOf(dst).Elem()
+	_, err := b.json.walkStruct(n, schemaBody, RequestOptions{
+		ShouldBind:  true,
+		Context:     &b.pc,
+		SchemaPtr:   b.schema,
+		Body:        &body,
+		SchemaRules: b.gen.defs[i],
+	}, k)
+	return err
+}
+
+func (b *GenBinder) object(i int, n *JSONValue, k []string) (bool, error) {
+	def := b.gen.defs[i]
+	if err := b.json.checkDepth(schemaBody, k); err != nil {
+		return false, err
+	}
+	val, ok, err := b.json.nodeValue(n, schemaBody, def, k)
+	if err != nil || !ok {
+		return false, err
+	}
+	if val == nil && !def.defaulted {
+		return false, runValidationLazy(nil, RequestErr, schemaBody, k, def.rules)
+	}
+	if def.strict {
+		if err := checkUnknownFields(n, def, schemaBody, k); err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// Object checks the object n of the struct field i. It returns false when there are no fields
+// to bind.
+func (b *GenBinder) Object(i int, n *JSONValue, k []string, dst any) (bool, error) {
+	if b.gen.delegated(i) {
+		return false, b.Reflect(i, n, k, dst)
+	}
+	return b.object(i, n, k)
+}
+
+// ObjectEnd runs the cross field rules of the struct field i once its fields are bound.
+func (b *GenBinder) ObjectEnd(i int, n *JSONValue, k []string) error {
+	return validateCrossFields(b.gen.defs[i], RequestErr, schemaBody, k, jsonFieldLookup(n))
+}
+
+// Value reads and validates the scalar field i. It returns the value to bind, or false when
+// there is nothing to bind.
+func (b *GenBinder) Value(i int, n *JSONValue, k []string, dst any) (any, bool, error) {
+	if b.gen.delegated(i) {
+		return nil, false, b.Reflect(i, n, k, dst)
+	}
+	def := b.gen.defs[i]
+	if err := b.json.checkDepth(schemaBody, k); err != nil {
+		return nil, false, err
+	}
+	val, ok, err := b.json.nodeValue(n, schemaBody, def, k)
+	if err != nil || !ok {
+		return nil, false, err
+	}
+	if err := runValidationLazy(val, RequestErr, schemaBody, k, def.rules); err != nil {
+		return nil, false, err
+	}
+	return val, true, nil
+}
+
+// Values reads and validates the slice of scalars i. It returns the items to bind, or false
+// when there is nothing to bind.
+func (b *GenBinder) Values(i int, n *JSONValue, k []string, dst any) ([]any, bool, error) {
+	def := b.gen.defs[i]
+	if b.gen.delegated(i) || !def.hasPrimitiveItems() {
+		return nil, false, b.Reflect(i, n, k, dst)
+	}
+	if err := b.json.checkDepth(schemaBody, k); err != nil {
+		return nil, false, err
+	}
+	if _, ok, err := b.json.nodeValue(n, schemaBody, def, k); err != nil || !ok {
+		return nil, false, err
+	}
+	arr, err := b.json.primitiveItems(n, schemaBody, def, k)
+	if err != nil {
+		return nil, false, err
+	}
+	return arr, true, nil
+}
+
+// Items returns the item nodes of the slice field i, or false when there is nothing to bind.
+func (b *GenBinder) Items(i int, n *JSONValue, k []string, dst any) ([]*JSONValue, bool, error) {
+	def := b.gen.defs[i]
+	if b.gen.delegated(i) || def.hasPrimitiveItems() {
+		return nil, false, b.Reflect(i, n, k, dst)
+	}
+	if err := b.json.checkDepth(schemaBody, k); err != nil {
+		return nil, false, err
+	}
+	if _, ok, err := b.json.nodeValue(n, schemaBody, def, k); err != nil || !ok {
+		return nil, false, err
+	}
+	nodes, err := b.json.itemNodes(n, schemaBody, def, k)
+	if err != nil {
+		return nil, false, err
+	}
+	return nodes, true, nil
+}
+
+// ItemsEnd runs the rules of the slice field i once its items are bound.
+func (b *GenBinder) ItemsEnd(i int, k []string, slice any) error {
+	return runValidationLazy(slice, RequestErr, schemaBody, k, b.gen.defs[i].rules)
+}
+
+// Map returns the object of the map field i, or false when there is nothing to bind.
+func (b *GenBinder) Map(i int, n *JSONValue, k []string, dst any) (*JSONObject, bool, error) {
+	def := b.gen.defs[i]
+	if b.gen.delegated(i) {
+		return nil, false, b.Reflect(i, n, k, dst)
+	}
+	if err := b.json.checkDepth(schemaBody, k); err != nil {
+		return nil, false, err
+	}
+	if _, ok, err := b.json.nodeValue(n, schemaBody, def, k); err != nil || !ok || def.additionalProperties == nil {
+		return nil, false, err
+	}
+	obj, err := n.Object()
+	if err != nil {
+		return nil, false, newKeysReport(RequestErr, schemaBody, k, "parser", err)
+	}
+	return obj, true, nil
+}
+
+// Entries validates the keys of the map field i and calls fn with each entry of obj.
+func (b *GenBinder) Entries(i int, obj *JSONObject, k []string, fn func(key string, n *JSONValue, k []string) error) error {
+	return b.json.visitEntries(obj, schemaBody, b.gen.defs[i], k, fn)
+}
+
+// DecodeError reports a body value that could not be stored.
+func (b *GenBinder) DecodeError(k []string, err error) error {
+	return newKeysReport(RequestErr, schemaBody, k, "decode", err)
+}
+
+// GenString stores a value returned by GenBinder in dst.
+func GenString[T ~string](val any, dst *T) error {
+	if v, ok := val.(string); ok {
+		*dst = T(v)
+		return nil
+	}
+	return genConvert(val, dst)
+}
+
+// GenBool stores a value returned by GenBinder in dst.
+func GenBool[T ~bool](val any, dst *T) error {
+	if v, ok := val.(bool); ok {
+		*dst = T(v)
+		return nil
+	}
+	return genConvert(val, dst)
+}
+
+type genNumber interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 | ~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~float32 | ~float64
+}
+
+// GenNumber stores a value returned by GenBinder in dst.
+func GenNumber[T genNumber](val any, dst *T) error {
+	switch v := val.(type) {
+	case int:
+		*dst = T(v)
+	case int8:
+		*dst = T(v)
+	case int16:
+		*dst = T(v)
+	case int32:
+		*dst = T(v)
+	case int64:
+		*dst = T(v)
+	case uint:
+		*dst = T(v)
+	case uint8:
+		*dst = T(v)
+	case uint16:
+		*dst = T(v)
+	case uint32:
+		*dst = T(v)
+	case uint64:
+		*dst = T(v)
+	case float32:
+		*dst = T(v)
+	case float64:
+		*dst = T(v)
+	default:
+		return genConvert(val, dst)
+	}
+	return nil
+}
+
+func genConvert(val any, dst any) error {
+	if val == nil {
+		return nil
+	}
+	field := reflect.ValueOf(dst).Elem()
+	v, err := utils.SafeConvert(reflect.ValueOf(val), field.Type())
+	if err != nil {
+		return err
+	}
+	field.Set(v)
+	return nil
+}
+
+// GenAlloc points p at a new value.
+func GenAlloc[T any](p **T) {
+	*p = new(T)
+}
+
+// GenMakeSlice sets p to a slice of n items.
+func GenMakeSlice[S ~[]E, E any](p *S, n int) {
+	*p = make(S, n)
+}
+
+// GenMakeMap sets p to an empty map.
+func GenMakeMap[M ~map[string]V, V any](p *M) {
+	*p = make(M)
+}
+
+// GenElem returns a new value of the element type of m.
+func GenElem[M ~map[string]V, V any](m M) *V {
+	return new(V)
+}
+
+// GenResponse returns the response struct obj passed to Context.Send as a *T.
+func GenResponse[T any](obj any) (*T, bool) {
+	switch v := obj.(type) {
+	case *T:
+		return v, v != nil
+	case T:
+		return &v, true
+	}
+	return nil, false
+}
+
+// GenEncoder validates and encodes a response body for generated code. Fields are referred to
+// by their index in GofiFields.
+type GenEncoder struct {
+	c    ParserContext
+	json *JSONBodyParser
+	gen  *genSchema
+	buf  *bytes.Buffer
+}
+
+// Sep writes the comma that separates a member or an item from the previous one.
+func (e *GenEncoder) Sep() {
+	if b := e.buf.Bytes(); len(b) > 0 && b[len(b)-1] != '{' && b[len(b)-1] != '[' {
+		e.buf.WriteByte(',')
+	}
+}
+
+// End writes the byte that closes an object or an array.
+func (e *GenEncoder) End(c byte) {
+	e.buf.WriteByte(c)
+}
+
+// Key writes the key of the struct field i and returns its key path. It returns false when the
+// field is left out because it is empty and tagged omitempty.
+func (e *GenEncoder) Key(i int, kp []string, empty bool) ([]string, bool) {
+	def := e.gen.defs[i]
+	if empty && slices.Contains(def.tags["json"], "omitempty") {
+		return nil, false
+	}
+	e.Sep()
+	e.writeKey(def)
+	return append(kp, def.field), true
+}
+
+func (e *GenEncoder) writeKey(def *RuleDef) {
+	if len(def.jsonKeyBytes) > 0 {
+		e.buf.Write(def.jsonKeyBytes)
+	} else {
+		e.buf.WriteString(`"` + def.field + `":`)
+	}
+}
+
+// Member writes the struct field i, which p points to, with the reflective path.
+func (e *GenEncoder) Member(i int, p any, kp []string) error {
+	def := e.gen.defs[i]
+	val := reflect.ValueOf(p).Elem()
+	if slices.Contains(def.tags["json"], "omitempty") && isEmptyJSONValue(val) {
+		return nil
+	}
+	if def.isWrapper() && !val.Field(1).Bool() {
+		return nil // unset Optional and Nullable fields are left out
+	}
+	e.Sep()
+	e.writeKey(def)
+	return e.json.encodeFieldValue(e.c, e.buf, val, def, append(kp, def.field))
+}
+
+// Value writes the field i, which p points to, with the reflective path.
+func (e *GenEncoder) Value(i int, p any, kp []string) error {
+	return e.json.encodeFieldValue(e.c, e.buf, reflect.ValueOf(p).Elem(), e.gen.defs[i], kp)
+}
+
+// MapKey writes a key of the map field i.
+func (e *GenEncoder) MapKey(i int, key string, kp []string) error {
+	if keys := e.gen.defs[i].keys; keys != nil {
+		if keys.defStr != "" && key == "" {
+			key = keys.defaultText()
+		}
+		if err := runValidationLazy(key, ResponseErr, schemaBody, kp, keys.rules); err != nil {
+			return err
+		}
+	}
+	encodeJSONString(e.buf, key)
+	e.buf.WriteByte(':')
+	return nil
+}
+
+// validate runs the rules of field i on the value p points to.
+func genValidate[T any](e *GenEncoder, i int, p *T, kp []string) error {
+	if rules := e.gen.defs[i].rules; len(rules) > 0 {
+		return runValidationLazy(*p, ResponseErr, schemaBody, kp, rules)
+	}
+	return nil
+}
+
+// genDefault sets the value p points to to the default of field i.
+func genDefault[T any](e *GenEncoder, i int, p *T) {
+	field := reflect.ValueOf(p).Elem()
+	field.Set(reflect.ValueOf(e.gen.defs[i].defVal).Convert(field.Type()))
+}
+
+// GenEncodeString validates and writes the string field i.
+func GenEncodeString[T ~string](e *GenEncoder, i int, p *T, kp []string) error {
+	if e.gen.delegated(i) {
+		return e.Value(i, p, kp)
+	}
+	if e.gen.defs[i].defStr != "" && *p == "" {
+		genDefault(e, i, p)
+	}
+	if err := genValidate(e, i, p, kp); err != nil {
+		return err
+	}
+	encodeJSONString(e.buf, string(*p))
+	return nil
+}
+
+// GenEncodeBool validates and writes the bool field i.
+func GenEncodeBool[T ~bool](e *GenEncoder, i int, p *T, kp []string) error {
+	if e.gen.delegated(i) {
+		return e.Value(i, p, kp)
+	}
+	if e.gen.defs[i].defStr != "" && !bool(*p) {
+		genDefault(e, i, p)
+	}
+	if err := genValidate(e, i, p, kp); err != nil {
+		return err
+	}
+	e.buf.Write(strconv.AppendBool(e.buf.AvailableBuffer(), bool(*p)))
+	return nil
+}
+
+// GenEncodeInt validates and writes the signed integer field i.
+func GenEncodeInt[T ~int | ~int8 | ~int16 | ~int32 | ~int64](e *GenEncoder, i int, p *T, kp []string) error {
+	if e.gen.delegated(i) {
+		return e.Value(i, p, kp)
+	}
+	if e.gen.defs[i].defStr != "" && *p == 0 {
+		genDefault(e, i, p)
+	}
+	if err := genValidate(e, i, p, kp); err != nil {
+		return err
+	}
+	e.buf.Write(strconv.AppendInt(e.buf.AvailableBuffer(), int64(*p), 10))
+	return nil
+}
+
+// GenEncodeUint validates and writes the unsigned integer field i.
+func GenEncodeUint[T ~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64](e *GenEncoder, i int, p *T, kp []string) error {
+	if e.gen.delegated(i) {
+		return e.Value(i, p, kp)
+	}
+	if err := genValidate(e, i, p, kp); err != nil {
+		return err
+	}
+	e.buf.Write(strconv.AppendUint(e.buf.AvailableBuffer(), uint64(*p), 10))
+	return nil
+}
+
+// GenEncodeFloat validates and writes the float field i.
+func GenEncodeFloat[T ~float32 | ~float64](e *GenEncoder, i int, p *T, kp []string) error {
+	if e.gen.delegated(i) {
+		return e.Value(i, p, kp)
+	}
+	if err := genValidate(e, i, p, kp); err != nil {
+		return err
+	}
+	e.buf.Write(strconv.AppendFloat(e.buf.AvailableBuffer(), float64(*p), 'f', -1, 64))
+	return nil
+}
+
+// GenEncodePointer validates the pointer field i and writes null when it is nil. It returns
+// true when the field is written; otherwise the generated code writes the value p points to.
+func GenEncodePointer[T any](e *GenEncoder, i int, p **T, kp []string) (bool, error) {
+	if e.gen.delegated(i) || e.gen.defs[i].defStr != "" {
+		return true, e.Value(i, p, kp)
+	}
+	if err := genValidate(e, i, p, kp); err != nil {
+		return true, err
+	}
+	if *p != nil {
+		return false, nil
+	}
+	if err := runValidationLazy(nil, ResponseErr, schemaBody, kp, e.gen.defs[i].rules); err != nil {
+		return true, err
+	}
+	e.buf.WriteString("null")
+	return true, nil
+}
+
+// GenEncodeStruct validates the struct field i and opens it. It returns true when the field is
+// written; otherwise the generated code writes its members.
+func GenEncodeStruct[T any](e *GenEncoder, i int, p *T, kp []string) (bool, error) {
+	if e.gen.delegated(i) {
+		return true, e.Value(i, p, kp)
+	}
+	if err := genValidate(e, i, p, kp); err != nil {
+		return true, err
+	}
+	e.buf.WriteByte('{')
+	return false, nil
+}
+
+// GenEncodeSlice validates the slice field i and opens it. It returns true when the field is
+// written; otherwise the generated code writes its items.
+func GenEncodeSlice[S ~[]E, E any](e *GenEncoder, i int, p *S, kp []string) (bool, error) {
+	if e.gen.delegated(i) || e.gen.defs[i].defStr != "" {
+		return true, e.Value(i, p, kp)
+	}
+	if err := genValidate(e, i, p, kp); err != nil {
+		return true, err
+	}
+	e.buf.WriteByte('[')
+	return false, nil
+}
+
+// GenEncodeMap validates the map field i and opens it. It returns true when the field is
+// written; otherwise the generated code writes its entries.
+func GenEncodeMap[M ~map[string]V, V any](e *GenEncoder, i int, p *M, kp []string) (bool, error) {
+	if e.gen.delegated(i) || e.gen.defs[i].defStr != "" || e.gen.defs[i].additionalProperties == nil {
+		return true, e.Value(i, p, kp)
+	}
+	if err := genValidate(e, i, p, kp); err != nil {
+		return true, err
+	}
+	e.buf.WriteByte('{')
+	return false, nil
+}
+
+// bindGenerated validates and binds the request with the generated code of the route schema.
+func bindGenerated[T any](c *context, gen *genSchema) (*T, error) {
+	schemaPtr := c.rules().schemaPool.Get().(*T)
+	gs, ok := any(schemaPtr).(GeneratedSchema)
+	if !ok {
+		return validateAndOrBindRequest[T](c, true, partAll)
+	}
+
+	b := &GenBinder{c: c, gen: gen, schema: schemaPtr, pc: parserContext{c: c}}
+	if c.rules().strictQuery {
+		b.fail(schemaQuery, checkUnknownQuery(c.fctx.QueryArgs(), c.rules().getReqRules(schemaQuery)))
+	}
+	gs.GofiBind(b)
+	for _, p := range gen.rest {
+		b.fail(p.part, c.bindParamDef(p.part, p.def, true, b.request()))
+	}
+	if pdef := c.rules().getReqRules(schemaHeaders); pdef != nil {
+		b.fail(schemaHeaders, validateCrossFields(pdef, RequestErr, schemaHeaders, nil, c.strFieldLookup(c.headerGet)))
+	}
+	if pdef := c.rules().getReqRules(schemaQuery); pdef != nil {
+		b.fail(schemaQuery, validateCrossFields(pdef, RequestErr, schemaQuery, nil, c.strFieldLookup(c.queryGet)))
+	}
+
+	errs := b.errs
+	if len(errs) == 0 {
+		errs = c.runAsyncValidation(b.request(), partAll)
+	}
+	if len(errs) == 0 {
+		errs = c.runRequestHooks(b.request(), partAll)
+	}
+
+	if len(errs) > 0 {
+		c.localizeErrors(errs)
+		return nil, errs
+	}
+	return schemaPtr, nil
+}
+
+// verifyBind binds the request with the generated code and with reflection, and fails when the
+// two disagree.
+func verifyBind[T any](c *context, gen *genSchema) (*T, error) {
+	got, gerr := bindGenerated[T](c, gen)
+	want, werr := validateAndOrBindRequest[T](c, true, partAll)
+
+	if msg := compareErrors(gerr, werr); msg != "" {
+		return nil, fmt.Errorf("generated binder of %s disagrees with reflection: %s", c.rules().schemaType, msg)
+	}
+	if gerr == nil && !reflect.DeepEqual(got, want) {
+		return nil, fmt.Errorf("generated binder of %s disagrees with reflection: bound %+v, want %+v", c.rules().schemaType, *got, *want)
+	}
+	return got, gerr
+}
+
+// encodeGenerated validates and encodes the body of the response struct obj with the generated
+// code of the route schema. It returns false when there is no code for the response.
+func (c *context) encodeGenerated(sz BodyParser, key string, obj any, pc ParserContext) ([]byte, bool, error) {
+	gen := c.rules().gen
+	j, ok := sz.(*JSONBodyParser)
+	if gen == nil || !ok || !gen.encodes[key] {
+		return nil, false, nil
+	}
+
+	buf := jsonBodyPool.Get().(*bytes.Buffer)
+	buf.Reset()
+	defer jsonBodyPool.Put(buf)
+
+	e := &GenEncoder{c: pc, json: j, gen: gen, buf: buf}
+	ok, err := gen.schema.GofiEncode(e, key, obj)
+	if !ok {
+		return nil, false, nil
+	} else if err != nil {
+		return nil, true, newErrReport(ResponseErr, schemaBody, "", "encoder", err)
+	}
+	return bytes.Clone(buf.Bytes()), true, nil
+}
+
+// encodeResponse validates and encodes the body of the response struct obj, with the generated
+// code of the route schema when it has some.
+func (c *context) encodeResponse(sz BodyParser, key string, obj any, opts ResponseOptions) ([]byte, error) {
+	bs, ok, err := c.encodeGenerated(sz, key, obj, opts.Context)
+	if !ok {
+		return sz.ValidateAndEncodeResponse(obj, opts)
+	}
+	if !c.serverOpts.verifyGenerated {
+		return bs, err
+	}
+
+	want, werr := sz.ValidateAndEncodeResponse(obj, opts)
+	if msg := compareErrors(err, werr); msg != "" {
+		return nil, fmt.Errorf("generated encoder of %s disagrees with reflection: %s", c.rules().schemaType, msg)
+	}
+	if err == nil && !sameJSON(bs, want) {
+		return nil, fmt.Errorf("generated encoder of %s disagrees with reflection: wrote %s, want %s", c.rules().schemaType, bs, want)
+	}
+	return bs, err
+}
+
+// compareErrors describes how the errors of the generated and the reflective path differ.
+func compareErrors(got, want error) string {
+	describe := func(err error) []string {
+		var out []string
+		for _, e := range appendValidationErrors(nil, err, RequestErr, schemaReq) {
+			out = append(out, e.Location()+" "+e.Pointer()+" "+e.Rule()+": "+e.Error())
+		}
+		slices.Sort(out)
+		return out
+	}
+
+	g, w := describe(got), describe(want)
+	if !slices.Equal(g, w) {
+		return fmt.Sprintf("errors %q, want %q", g, w)
+	}
+	return ""
+}
+
+// sameJSON reports whether two encodings hold the same JSON value. Map entries may be written
+// in any order.
+func sameJSON(a, b []byte) bool {
+	if bytes.Equal(a, b) {
+		return true
+	}
+	var av, bv any
+	if json.Unmarshal(a, &av) != nil || json.Unmarshal(b, &bv) != nil {
+		return false
+	}
+	return reflect.DeepEqual(av, bv)
+}
+
+func (s *schemaRules) genSchema() *genSchema {
+	if s == nil {
+		return nil
+	}
+	return s.gen
+}
@@ -0,0 +1,170 @@
+package gofi
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// genStubSchema has handwritten generated code that shouts the names it binds and encodes,
+// so tests can tell it apart from reflection.
+type genStubSchema struct {
+	Request struct {
+		Query struct {
+			Name  string `json:"name" validate:"required"`
+			Limit int    `json:"limit" validate:"max=10"`
+		}
+		Body struct {
+			Title string   `json:"title" validate:"required"`
+			Tags  []string `json:"tags"`
+		}
+	}
+	Ok struct {
+		Body struct {
+			Title string `json:"title" validate:"required"`
+		}
+	}
+}
+
+func (*genStubSchema) GofiFields() []string {
+	return []string{
+		"Request.Query.Name string",
+		"Request.Body struct",
+		"Request.Body.Title string",
+		"Request.Body.Tags reflect",
+		"Ok.Body struct",
+		"Ok.Body.Title string",
+	}
+}
+
+func (v *genStubSchema) GofiBind(b *GenBinder) {
+	if val, ok := b.Param(0); ok {
+		b.Bound(0, GenString(val, &v.Request.Query.Name))
+		v.Request.Query.Name = strings.ToUpper(v.Request.Query.Name)
+	}
+	b.BodyDone(func() error {
+		n0, ok, err := b.Body(1)
+		if err != nil || !ok {
+			return err
+		}
+		n1, k1 := b.Field(2, n0, nil)
+		if val, ok, err := b.Value(2, n1, k1, &v.Request.Body.Title); err != nil {
+			return err
+		} else if ok {
+			if err := GenString(val, &v.Request.Body.Title); err != nil {
+				return b.DecodeError(k1, err)
+			}
+		}
+		n2, k2 := b.Field(3, n0, nil)
+		if err := b.Reflect(3, n2, k2, &v.Request.Body.Tags); err != nil {
+			return err
+		}
+		return b.ObjectEnd(1, n0, nil)
+	}())
+}
+
+func (*genStubSchema) GofiEncode(e *GenEncoder, key string, obj any) (bool, error) {
+	r, ok := GenResponse[struct {
+		Body struct {
+			Title string `json:"title" validate:"required"`
+		}
+	}](obj)
+	if key != "Ok" || !ok {
+		return false, nil
+	}
+	if done, err := GenEncodeStruct(e, 4, &r.Body, nil); err != nil || done {
+		return true, err
+	}
+	if kp, ok := e.Key(5, nil, r.Body.Title == ""); ok {
+		title := strings.ToUpper(r.Body.Title)
+		if err := GenEncodeString(e, 5, &title, kp); err != nil {
+			return true, err
+		}
+	}
+	e.End('}')
+	return true, nil
+}
+
+func genStubRouter(verify bool, fn func(c Context, s *genStubSchema) error) Router {
+	r := NewRouter()
+	r.Configure(Config{VerifyGenerated: verify})
+	r.Post("/stub", RouteOptions{
+		Schema: &genStubSchema{},
+		Handler: func(c Context) error {
+			s, err := ValidateAndBind[genStubSchema](c)
+			if err != nil {
+				return err
+			}
+			return fn(c, s)
+		},
+	})
+	return r
+}
+
+func sendGenStub(t *testing.T, r Router, query string, body string) *InjectResponse {
+	t.Helper()
+
+	res, err := r.Test(TestOptions{
+		Method:  "POST",
+		Path:    "/stub" + query,
+		Headers: map[string]string{"Content-Type": "application/json"},
+		Body:    strings.NewReader(body),
+	})
+	require.NoError(t, err)
+	return res
+}
+
+func TestGenerated_Compile(t *testing.T) {
+	compiled := newRouter().compileSchema(&genStubSchema{}, Info{})
+	gen := compiled.rules.genSchema()
+	require.NotNil(t, gen)
+
+	assert.Equal(t, "name", gen.defs[0].field)
+	assert.Equal(t, schemaQuery, gen.parts[0])
+	assert.Equal(t, "title", gen.defs[2].field)
+	assert.True(t, gen.reflect[3])
+	assert.True(t, gen.encodes["Ok"])
+	require.Len(t, gen.rest, 1)
+	assert.Equal(t, "limit", gen.rest[0].def.field)
+}
+
+func TestGenerated_Preferred(t *testing.T) {
+	var bound *genStubSchema
+	r := genStubRouter(false, func(c Context, s *genStubSchema) error {
+		bound = s
+		s.Ok.Body.Title = s.Request.Body.Title
+		return c.Send(200, s.Ok)
+	})
+
+	res := sendGenStub(t, r, "?name=ada&limit=3", `{"title": "hello", "tags": ["a"]}`)
+	require.Equal(t, 200, res.StatusCode, string(res.Body))
+	assert.Equal(t, "ADA", bound.Request.Query.Name)
+	assert.Equal(t, 3, bound.Request.Query.Limit)
+	assert.Equal(t, []string{"a"}, bound.Request.Body.Tags)
+	assert.JSONEq(t, `{"title":"HELLO"}`, string(res.Body))
+
+	res = sendGenStub(t, r, "?name=ada&limit=30", `{"tags": [1]}`)
+	assert.Equal(t, 500, res.StatusCode)
+	assert.Contains(t, string(res.Body), "limit")
+}
+
+func TestGenerated_Verify(t *testing.T) {
+	r := genStubRouter(true, func(c Context, s *genStubSchema) error {
+		s.Ok.Body.Title = "hello"
+		return c.Send(200, s.Ok)
+	})
+
+	res := sendGenStub(t, r, "?name=ada", `{"title": "hello"}`)
+	assert.Equal(t, 500, res.StatusCode)
+	assert.Contains(t, string(res.Body), "generated binder of gofi.genStubSchema disagrees with reflection")
+
+	res = sendGenStub(t, r, "?name=ADA", `{"title": "hello"}`)
+	assert.Equal(t, 500, res.StatusCode)
+	assert.Contains(t, string(res.Body), "generated encoder of gofi.genStubSchema disagrees with reflection")
+
+	res = sendGenStub(t, r, "?name=ADA", `{"tags": []}`)
+	assert.Equal(t, 500, res.StatusCode)
+	assert.NotContains(t, string(res.Body), "disagrees")
+}
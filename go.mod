@@ -7,6 +7,7 @@ require (
 	github.com/stretchr/testify v1.9.0
 	github.com/valyala/fasthttp v1.69.0
 	github.com/valyala/fastjson v1.6.4
+	golang.org/x/text v0.32.0
 )
 
 require (
@@ -23,6 +23,8 @@ type RouteOptions struct {
 	Meta any
 	// Define the handler for your route
 	Handler func(c Context) error
+	// Strict overrides Config.Strict for this route
+	Strict *Strict
 }
 
 func DefineHandler(opts RouteOptions) RouteOptions {
@@ -0,0 +1,246 @@
+package gofi
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+)
+
+// SchemaValidator is implemented by schema structs that check invariants the validate tags
+// cannot express. gofi calls Validate on every struct of a request schema after the tag rules
+// pass and the request is bound (nested structs first, then the request part, then Request),
+// and on response structs before Send encodes them. gofi.Validate binds the request into a scratch
+// schema so the hooks run there too.
+type SchemaValidator interface {
+	Validate() error
+}
+
+// ContextSchemaValidator is like SchemaValidator but receives the request context.
+type ContextSchemaValidator interface {
+	Validate(c Context) error
+}
+
+type hookKind uint8
+
+const (
+	noHook hookKind = iota
+	plainHook
+	contextHook
+)
+
+var (
+	schemaValidatorType        = reflect.TypeFor[SchemaValidator]()
+	contextSchemaValidatorType = reflect.TypeFor[ContextSchemaValidator]()
+)
+
+// structHookOf reports which Validate method typ (or *typ) implements.
+func structHookOf(typ reflect.Type) hookKind {
+	if typ == nil {
+		return noHook
+	}
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+	if typ.Kind() != reflect.Struct {
+		return noHook
+	}
+
+	ptr := reflect.PointerTo(typ)
+	switch {
+	case ptr.Implements(contextSchemaValidatorType):
+		return contextHook
+	case ptr.Implements(schemaValidatorType):
+		return plainHook
+	}
+	return noHook
+}
+
+// setHook records the Validate method of typ on r and marks whether r or any of its children
+// has one, so values without hooks are never walked.
+func (r *RuleDef) setHook(typ reflect.Type) {
+	if r == nil {
+		return
+	}
+
+	r.hook = structHookOf(typ)
+	r.hooked = r.hook != noHook ||
+		(r.item != nil && r.item.hooked) ||
+		(r.additionalProperties != nil && r.additionalProperties.hooked)
+	for _, p := range r.orderedProps {
+		r.hooked = r.hooked || p.hooked
+	}
+}
+
+// callStructHook calls the Validate method of the struct held by v.
+func callStructHook(c Context, v reflect.Value, kind hookKind) error {
+	if kind == noHook {
+		return nil
+	}
+
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	if !v.IsValid() {
+		return nil
+	}
+
+	var ptr reflect.Value
+	if v.CanAddr() {
+		ptr = v.Addr()
+	} else {
+		ptr = reflect.New(v.Type())
+		ptr.Elem().Set(v)
+	}
+
+	switch kind {
+	case contextHook:
+		return ptr.Interface().(ContextSchemaValidator).Validate(c)
+	default:
+		return ptr.Interface().(SchemaValidator).Validate()
+	}
+}
+
+// runStructHooks walks v along def and calls the Validate hooks it finds, nested values first.
+func runStructHooks(c Context, v reflect.Value, def *RuleDef, typ errorType, field schemaField, keys []string) error {
+	if def == nil || !def.hooked || !v.IsValid() {
+		return nil
+	}
+
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	if def.isWrapper() {
+		var ok bool
+		if v, ok = wrappedValue(v); !ok {
+			return nil
+		}
+		return runStructHooks(c, v, def.item, typ, field, keys)
+	}
+
+	var errs []error
+	switch v.Kind() {
+	case reflect.Struct:
+		for _, child := range def.orderedProps {
+			if !child.hooked {
+				continue
+			}
+			fv, err := v.FieldByIndexErr(child.accessor.index)
+			if err != nil {
+				continue
+			}
+			if err := runStructHooks(c, fv, child, typ, field, append(keys, child.field)); err != nil {
+				errs = append(errs, err)
+			}
+		}
+
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if err := runStructHooks(c, v.Index(i), def.item, typ, field, append(keys, strconv.Itoa(i))); err != nil {
+				errs = append(errs, err)
+			}
+		}
+
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			key, ok := iter.Key().Interface().(string)
+			if !ok {
+				continue
+			}
+			if err := runStructHooks(c, iter.Value(), def.additionalProperties, typ, field, append(keys, key)); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	if len(errs) == 0 {
+		if err := callStructHook(c, v, def.hook); err != nil {
+			errs = append(errs, hookError(err, typ, field, keys))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// hookError folds an error returned by a Validate hook into the validation error format.
+// Validation errors are kept as they are; any other error is reported against the struct
+// with the rule "validate".
+func hookError(err error, typ errorType, field schemaField, keys []string) error {
+	var verr ValidationError
+	if errors.As(err, &verr) {
+		return err
+	}
+	return newKeysReport(typ, field, keys, "validate", err)
+}
+
+// reqHooked reports whether the request parts selected by mask, or the Request struct when
+// every part is selected, have Validate hooks.
+func (s *schemaRules) reqHooked(mask requestPartMask) bool {
+	if s.schemaType == nil {
+		return false
+	}
+	if mask == partAll && s.reqHook != noHook {
+		return true
+	}
+	for _, p := range requestParts {
+		if def := s.getReqRules(p.field); mask&p.mask != 0 && def != nil && def.hooked {
+			return true
+		}
+	}
+	return false
+}
+
+// runRequestHooks calls the Validate hooks of the bound request parts selected by mask, then
+// of the Request struct itself when every part was bound.
+func (c *context) runRequestHooks(reqStruct reflect.Value, mask requestPartMask) ValidationErrors {
+	var errs ValidationErrors
+	for _, p := range requestParts {
+		if mask&p.mask == 0 {
+			continue
+		}
+		pdef := c.rules().getReqRules(p.field)
+		if pdef == nil {
+			continue
+		}
+		if err := runStructHooks(c, reqStruct.FieldByName(string(p.field)), pdef, RequestErr, p.field, nil); err != nil {
+			errs = appendValidationErrors(errs, err, RequestErr, p.field)
+		}
+	}
+
+	if len(errs) == 0 && mask == partAll {
+		if err := callStructHook(c, reqStruct, c.rules().reqHook); err != nil {
+			errs = appendValidationErrors(errs, hookError(err, RequestErr, schemaReq, nil), RequestErr, schemaReq)
+		}
+	}
+	return errs
+}
+
+// runResponseHooks calls the Validate hooks of a response struct before it is encoded.
+func (c *context) runResponseHooks(key string, rules ruleDefMap, resp reflect.Value) error {
+	var errs ValidationErrors
+	for _, field := range []schemaField{schemaHeaders, schemaCookies, schemaBody} {
+		def, ok := rules[string(field)]
+		if !ok {
+			continue
+		}
+		if err := runStructHooks(c, resp.FieldByName(string(field)), &def, ResponseErr, field, nil); err != nil {
+			errs = appendValidationErrors(errs, err, ResponseErr, field)
+		}
+	}
+
+	if len(errs) == 0 {
+		if err := callStructHook(c, resp, c.rules().respHooks[key]); err != nil {
+			errs = appendValidationErrors(errs, hookError(err, ResponseErr, schemaField(key), nil), ResponseErr, schemaField(key))
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
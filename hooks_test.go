@@ -0,0 +1,171 @@
+package gofi
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type hookItem struct {
+	Min int `json:"min"`
+	Max int `json:"max"`
+}
+
+func (h *hookItem) Validate() error {
+	if h.Max < h.Min {
+		return errors.New("max must not be less than min")
+	}
+	return nil
+}
+
+type hookBody struct {
+	Name  string     `json:"name" validate:"required"`
+	Items []hookItem `json:"items"`
+}
+
+type hookRequest struct {
+	Header struct {
+		Tenant string `json:"x-tenant"`
+	}
+	Body hookBody
+}
+
+func (r hookRequest) Validate(c Context) error {
+	if r.Header.Tenant != "" && r.Header.Tenant != c.Param("tenant") {
+		return errors.New("tenant header does not match path")
+	}
+	return nil
+}
+
+type hookSchema struct {
+	Request hookRequest
+}
+
+func bindHooks(t *testing.T, headers map[string]string, body string) (*hookSchema, error) {
+	t.Helper()
+
+	var s *hookSchema
+	var bindErr error
+	hs := map[string]string{"Content-Type": "application/json"}
+	for k, v := range headers {
+		hs[k] = v
+	}
+	r := NewRouter()
+	_, err := r.Inject(InjectOptions{
+		Path:    "/tenants/:tenant/orders",
+		Method:  "POST",
+		Headers: hs,
+		Body:    strings.NewReader(body),
+		Handler: &RouteOptions{
+			Schema: &hookSchema{},
+			Handler: func(c Context) error {
+				s, bindErr = ValidateAndBind[hookSchema](c)
+				return nil
+			},
+		},
+		Paths: map[string]string{"tenant": "acme"},
+	})
+	require.Nil(t, err)
+	return s, bindErr
+}
+
+func TestStructHooks_Request(t *testing.T) {
+	s, err := bindHooks(t, map[string]string{"X-Tenant": "acme"}, `{"name":"a","items":[{"min":1,"max":2}]}`)
+	require.NoError(t, err)
+	assert.Equal(t, "a", s.Request.Body.Name)
+
+	_, err = bindHooks(t, nil, `{"name":"a","items":[{"min":1,"max":2},{"min":3,"max":1}]}`)
+	var verrs ValidationErrors
+	require.True(t, errors.As(err, &verrs))
+	require.Len(t, verrs, 1)
+	assert.Equal(t, "/items/1", verrs[0].Pointer())
+	assert.Equal(t, "validate", verrs[0].Rule())
+	assert.Equal(t, "max must not be less than min", verrs[0].Message())
+
+	_, err = bindHooks(t, map[string]string{"X-Tenant": "other"}, `{"name":"a"}`)
+	require.True(t, errors.As(err, &verrs))
+	require.Len(t, verrs, 1)
+	assert.Equal(t, "validate", verrs[0].Rule())
+	assert.ErrorContains(t, err, "tenant header does not match path")
+}
+
+func TestStructHooks_SkippedWhenTagRulesFail(t *testing.T) {
+	_, err := bindHooks(t, map[string]string{"X-Tenant": "other"}, `{"items":[{"min":3,"max":1}]}`)
+	var verrs ValidationErrors
+	require.True(t, errors.As(err, &verrs))
+	require.Len(t, verrs, 1)
+	assert.Equal(t, "required", verrs[0].Rule())
+	assert.Equal(t, "/name", verrs[0].Pointer())
+}
+
+type hookResponseBody struct {
+	Total int `json:"total"`
+	Count int `json:"count"`
+}
+
+func (b hookResponseBody) Validate() error {
+	if b.Count > b.Total {
+		return errors.New("count exceeds total")
+	}
+	return nil
+}
+
+func TestStructHooks_Response(t *testing.T) {
+	type schema struct {
+		Ok struct {
+			Body hookResponseBody
+		}
+	}
+
+	send := func(body hookResponseBody) error {
+		var sendErr error
+		r := NewRouter()
+		_, err := r.Inject(InjectOptions{
+			Path:   "/stats",
+			Method: "GET",
+			Handler: &RouteOptions{
+				Schema: &schema{},
+				Handler: func(c Context) error {
+					var s schema
+					s.Ok.Body = body
+					sendErr = c.Send(200, s.Ok)
+					return nil
+				},
+			},
+		})
+		require.Nil(t, err)
+		return sendErr
+	}
+
+	assert.NoError(t, send(hookResponseBody{Total: 2, Count: 1}))
+	assert.ErrorContains(t, send(hookResponseBody{Total: 1, Count: 2}), "count exceeds total")
+}
+
+func TestStructHooks_ValidateOnly(t *testing.T) {
+	var validateErr error
+	r := NewRouter()
+	_, err := r.Inject(InjectOptions{
+		Path:    "/tenants/:tenant/orders",
+		Method:  "POST",
+		Headers: map[string]string{"Content-Type": "application/json", "X-Tenant": "acme"},
+		Body:    strings.NewReader(`{"name":"a","items":[{"min":3,"max":1}]}`),
+		Handler: &RouteOptions{
+			Schema: &hookSchema{},
+			Handler: func(c Context) error {
+				validateErr = Validate(c)
+				return nil
+			},
+		},
+		Paths: map[string]string{"tenant": "acme"},
+	})
+	require.NoError(t, err)
+
+	var verrs ValidationErrors
+	require.True(t, errors.As(validateErr, &verrs))
+	require.Len(t, verrs, 1)
+	assert.Equal(t, "/items/0", verrs[0].Pointer())
+	assert.Equal(t, "validate", verrs[0].Rule())
+}
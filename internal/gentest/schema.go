@@ -0,0 +1,81 @@
+// Package gentest holds route schemas with code generated by cmd/gofigen, to check that the
+// generated code behaves like the reflective path of gofi.
+package gentest
+
+import (
+	"time"
+
+	"github.com/michaelolof/gofi"
+)
+
+//go:generate go run github.com/michaelolof/gofi/cmd/gofigen -type OrderSchema -output schema_gofi.go
+
+type Status string
+
+type Address struct {
+	Street string `json:"street" validate:"required"`
+	City   string `json:"city" validate:"required,min=2"`
+	Zip    *int   `json:"zip,omitempty"`
+}
+
+type Item struct {
+	Sku      string            `json:"sku" validate:"required"`
+	Quantity uint16            `json:"quantity" validate:"required,min=1,max=99"`
+	Price    float64           `json:"price" validate:"min=0"`
+	Tags     []string          `json:"tags,omitempty"`
+	Attrs    map[string]string `json:"attrs,omitempty"`
+}
+
+type Order struct {
+	Id        int                `json:"id" validate:"required"`
+	Customer  string             `json:"customer" validate:"required"`
+	Status    Status             `json:"status" default:"pending" validate:"oneof=pending paid shipped"`
+	Paid      bool               `json:"paid"`
+	Note      *string            `json:"note"`
+	Items     []Item             `json:"items" validate:"required"`
+	Shipping  *Address           `json:"shipping,omitempty"`
+	Discounts map[string]float32 `json:"discounts,omitempty"`
+	Placed    time.Time          `json:"placed"`
+}
+
+type OrderSchema struct {
+	Request struct {
+		Header struct {
+			Tenant string `json:"x-tenant" validate:"required"`
+		}
+		Path struct {
+			Id int `json:"id" validate:"required,min=1"`
+		}
+		Query struct {
+			Dry    *bool    `json:"dry"`
+			Fields []string `json:"fields"`
+		}
+		Body struct {
+			Customer string                `json:"customer" validate:"required,min=3"`
+			Status   Status                `json:"status" default:"pending" validate:"oneof=pending paid shipped"`
+			Items    []Item                `json:"items" validate:"required"`
+			Shipping *Address              `json:"shipping"`
+			Billing  Address               `json:"billing"`
+			Codes    []int                 `json:"codes"`
+			Notes    map[string]Address    `json:"notes"`
+			Meta     map[string]any        `json:"meta"`
+			Gift     gofi.Optional[string] `json:"gift"`
+			Deliver  *time.Time            `json:"deliver"`
+		}
+	}
+
+	Ok struct {
+		Body Order
+	}
+	Created struct {
+		Header struct {
+			Location string `json:"Location" validate:"required"`
+		}
+		Body Order
+	}
+	Err struct {
+		Body struct {
+			Message string `json:"message" validate:"required"`
+		}
+	}
+}
@@ -0,0 +1,834 @@
+// Code generated by gofigen. DO NOT EDIT.
+
+package gentest
+
+import (
+	"strconv"
+
+	"github.com/michaelolof/gofi"
+)
+
+var gofiFieldsOrderSchema = []string{
+	"Request.Header.Tenant string",
+	"Request.Query.Dry bool",
+	"Request.Path.Id int",
+	"Request.Body struct",
+	"Request.Body.Customer string",
+	"Request.Body.Status string",
+	"Request.Body.Items slice",
+	"Request.Body.Items.[] struct",
+	"Request.Body.Items.[].Sku string",
+	"Request.Body.Items.[].Quantity uint16",
+	"Request.Body.Items.[].Price float64",
+	"Request.Body.Items.[].Tags slice",
+	"Request.Body.Items.[].Tags.[] string",
+	"Request.Body.Items.[].Attrs map",
+	"Request.Body.Items.[].Attrs.{} string",
+	"Request.Body.Shipping struct",
+	"Request.Body.Shipping.Street string",
+	"Request.Body.Shipping.City string",
+	"Request.Body.Shipping.Zip int",
+	"Request.Body.Billing struct",
+	"Request.Body.Billing.Street string",
+	"Request.Body.Billing.City string",
+	"Request.Body.Billing.Zip int",
+	"Request.Body.Codes slice",
+	"Request.Body.Codes.[] int",
+	"Request.Body.Notes map",
+	"Request.Body.Notes.{} struct",
+	"Request.Body.Notes.{}.Street string",
+	"Request.Body.Notes.{}.City string",
+	"Request.Body.Notes.{}.Zip int",
+	"Request.Body.Meta map",
+	"Request.Body.Meta.{} reflect",
+	"Request.Body.Gift reflect",
+	"Request.Body.Deliver reflect",
+	"Ok.Body struct",
+	"Ok.Body.Id int",
+	"Ok.Body.Customer string",
+	"Ok.Body.Status string",
+	"Ok.Body.Paid bool",
+	"Ok.Body.Note string",
+	"Ok.Body.Items slice",
+	"Ok.Body.Items.[] struct",
+	"Ok.Body.Items.[].Sku string",
+	"Ok.Body.Items.[].Quantity uint16",
+	"Ok.Body.Items.[].Price float64",
+	"Ok.Body.Items.[].Tags slice",
+	"Ok.Body.Items.[].Tags.[] string",
+	"Ok.Body.Items.[].Attrs map",
+	"Ok.Body.Items.[].Attrs.{} string",
+	"Ok.Body.Shipping struct",
+	"Ok.Body.Shipping.Street string",
+	"Ok.Body.Shipping.City string",
+	"Ok.Body.Shipping.Zip int",
+	"Ok.Body.Discounts map",
+	"Ok.Body.Discounts.{} float32",
+	"Ok.Body.Placed reflect",
+	"Created.Body struct",
+	"Created.Body.Id int",
+	"Created.Body.Customer string",
+	"Created.Body.Status string",
+	"Created.Body.Paid bool",
+	"Created.Body.Note string",
+	"Created.Body.Items slice",
+	"Created.Body.Items.[] struct",
+	"Created.Body.Items.[].Sku string",
+	"Created.Body.Items.[].Quantity uint16",
+	"Created.Body.Items.[].Price float64",
+	"Created.Body.Items.[].Tags slice",
+	"Created.Body.Items.[].Tags.[] string",
+	"Created.Body.Items.[].Attrs map",
+	"Created.Body.Items.[].Attrs.{} string",
+	"Created.Body.Shipping struct",
+	"Created.Body.Shipping.Street string",
+	"Created.Body.Shipping.City string",
+	"Created.Body.Shipping.Zip int",
+	"Created.Body.Discounts map",
+	"Created.Body.Discounts.{} float32",
+	"Created.Body.Placed reflect",
+	"Err.Body struct",
+	"Err.Body.Message string",
+}
+
+func (*OrderSchema) GofiFields() []string {
+	return gofiFieldsOrderSchema
+}
+
+func (v *OrderSchema) GofiBind(b *gofi.GenBinder) {
+	if val, ok := b.Param(0); ok {
+		b.Bound(0, gofi.GenString(val, &v.Request.Header.Tenant))
+	}
+	if val, ok := b.Param(1); ok {
+		gofi.GenAlloc(&v.Request.Query.Dry)
+		b.Bound(1, gofi.GenBool(val, v.Request.Query.Dry))
+	}
+	if val, ok := b.Param(2); ok {
+		b.Bound(2, gofi.GenNumber(val, &v.Request.Path.Id))
+	}
+	b.BodyDone(gofiBindOrderSchemaBody(b, v))
+}
+
+func gofiBindOrderSchemaBody(b *gofi.GenBinder, v *OrderSchema) error {
+	n0, ok, err := b.Body(3)
+	if err != nil || !ok {
+		return err
+	}
+	var k0 []string
+	p0 := &v.Request.Body
+	{
+		n1, k1 := b.Field(4, n0, k0)
+		p1 := &p0.Customer
+		if val, ok, err := b.Value(4, n1, k1, p1); err != nil {
+			return err
+		} else if ok {
+			if err := gofi.GenString(val, p1); err != nil {
+				return b.DecodeError(k1, err)
+			}
+		}
+	}
+	{
+		n2, k2 := b.Field(5, n0, k0)
+		p2 := &p0.Status
+		if val, ok, err := b.Value(5, n2, k2, p2); err != nil {
+			return err
+		} else if ok {
+			if err := gofi.GenString(val, p2); err != nil {
+				return b.DecodeError(k2, err)
+			}
+		}
+	}
+	{
+		n3, k3 := b.Field(6, n0, k0)
+		p3 := &p0.Items
+		if items, ok, err := b.Items(6, n3, k3, p3); err != nil {
+			return err
+		} else if ok {
+			gofi.GenMakeSlice(p3, len(items))
+			for j4, n4 := range items {
+				k4 := append(k3, strconv.Itoa(j4))
+				p4 := &(*p3)[j4]
+				if ok, err := b.Object(7, n4, k4, p4); err != nil {
+					return err
+				} else if ok {
+					{
+						n5, k5 := b.Field(8, n4, k4)
+						p5 := &p4.Sku
+						if val, ok, err := b.Value(8, n5, k5, p5); err != nil {
+							return err
+						} else if ok {
+							if err := gofi.GenString(val, p5); err != nil {
+								return b.DecodeError(k5, err)
+							}
+						}
+					}
+					{
+						n6, k6 := b.Field(9, n4, k4)
+						p6 := &p4.Quantity
+						if val, ok, err := b.Value(9, n6, k6, p6); err != nil {
+							return err
+						} else if ok {
+							if err := gofi.GenNumber(val, p6); err != nil {
+								return b.DecodeError(k6, err)
+							}
+						}
+					}
+					{
+						n7, k7 := b.Field(10, n4, k4)
+						p7 := &p4.Price
+						if val, ok, err := b.Value(10, n7, k7, p7); err != nil {
+							return err
+						} else if ok {
+							if err := gofi.GenNumber(val, p7); err != nil {
+								return b.DecodeError(k7, err)
+							}
+						}
+					}
+					{
+						n8, k8 := b.Field(11, n4, k4)
+						p8 := &p4.Tags
+						if arr, ok, err := b.Values(11, n8, k8, p8); err != nil {
+							return err
+						} else if ok {
+							gofi.GenMakeSlice(p8, len(arr))
+							for j9, val := range arr {
+								if err := gofi.GenString(val, &(*p8)[j9]); err != nil {
+									return b.DecodeError(k8, err)
+								}
+							}
+						}
+					}
+					{
+						n10, k10 := b.Field(13, n4, k4)
+						p10 := &p4.Attrs
+						if obj, ok, err := b.Map(13, n10, k10, p10); err != nil {
+							return err
+						} else if ok {
+							gofi.GenMakeMap(p10)
+							m11 := *p10
+							if err := b.Entries(13, obj, k10, func(key string, n11 *gofi.JSONValue, k11 []string) error {
+								p11 := gofi.GenElem(m11)
+								if val, ok, err := b.Value(14, n11, k11, p11); err != nil {
+									return err
+								} else if ok {
+									if err := gofi.GenString(val, p11); err != nil {
+										return b.DecodeError(k11, err)
+									}
+								}
+								m11[key] = *p11
+								return nil
+							}); err != nil {
+								return err
+							}
+						}
+					}
+					if err := b.ObjectEnd(7, n4, k4); err != nil {
+						return err
+					}
+				}
+			}
+			if err := b.ItemsEnd(6, k3, *p3); err != nil {
+				return err
+			}
+		}
+	}
+	{
+		n12, k12 := b.Field(15, n0, k0)
+		p12 := &p0.Shipping
+		if ok, err := b.Object(15, n12, k12, p12); err != nil {
+			return err
+		} else if ok {
+			gofi.GenAlloc(p12)
+			{
+				n13, k13 := b.Field(16, n12, k12)
+				p13 := &(*p12).Street
+				if val, ok, err := b.Value(16, n13, k13, p13); err != nil {
+					return err
+				} else if ok {
+					if err := gofi.GenString(val, p13); err != nil {
+						return b.DecodeError(k13, err)
+					}
+				}
+			}
+			{
+				n14, k14 := b.Field(17, n12, k12)
+				p14 := &(*p12).City
+				if val, ok, err := b.Value(17, n14, k14, p14); err != nil {
+					return err
+				} else if ok {
+					if err := gofi.GenString(val, p14); err != nil {
+						return b.DecodeError(k14, err)
+					}
+				}
+			}
+			{
+				n15, k15 := b.Field(18, n12, k12)
+				p15 := &(*p12).Zip
+				if val, ok, err := b.Value(18, n15, k15, p15); err != nil {
+					return err
+				} else if ok {
+					gofi.GenAlloc(p15)
+					if err := gofi.GenNumber(val, *p15); err != nil {
+						return b.DecodeError(k15, err)
+					}
+				}
+			}
+			if err := b.ObjectEnd(15, n12, k12); err != nil {
+				return err
+			}
+		}
+	}
+	{
+		n16, k16 := b.Field(19, n0, k0)
+		p16 := &p0.Billing
+		if ok, err := b.Object(19, n16, k16, p16); err != nil {
+			return err
+		} else if ok {
+			{
+				n17, k17 := b.Field(20, n16, k16)
+				p17 := &p16.Street
+				if val, ok, err := b.Value(20, n17, k17, p17); err != nil {
+					return err
+				} else if ok {
+					if err := gofi.GenString(val, p17); err != nil {
+						return b.DecodeError(k17, err)
+					}
+				}
+			}
+			{
+				n18, k18 := b.Field(21, n16, k16)
+				p18 := &p16.City
+				if val, ok, err := b.Value(21, n18, k18, p18); err != nil {
+					return err
+				} else if ok {
+					if err := gofi.GenString(val, p18); err != nil {
+						return b.DecodeError(k18, err)
+					}
+				}
+			}
+			{
+				n19, k19 := b.Field(22, n16, k16)
+				p19 := &p16.Zip
+				if val, ok, err := b.Value(22, n19, k19, p19); err != nil {
+					return err
+				} else if ok {
+					gofi.GenAlloc(p19)
+					if err := gofi.GenNumber(val, *p19); err != nil {
+						return b.DecodeError(k19, err)
+					}
+				}
+			}
+			if err := b.ObjectEnd(19, n16, k16); err != nil {
+				return err
+			}
+		}
+	}
+	{
+		n20, k20 := b.Field(23, n0, k0)
+		p20 := &p0.Codes
+		if arr, ok, err := b.Values(23, n20, k20, p20); err != nil {
+			return err
+		} else if ok {
+			gofi.GenMakeSlice(p20, len(arr))
+			for j21, val := range arr {
+				if err := gofi.GenNumber(val, &(*p20)[j21]); err != nil {
+					return b.DecodeError(k20, err)
+				}
+			}
+		}
+	}
+	{
+		n22, k22 := b.Field(25, n0, k0)
+		p22 := &p0.Notes
+		if obj, ok, err := b.Map(25, n22, k22, p22); err != nil {
+			return err
+		} else if ok {
+			gofi.GenMakeMap(p22)
+			m23 := *p22
+			if err := b.Entries(25, obj, k22, func(key string, n23 *gofi.JSONValue, k23 []string) error {
+				p23 := gofi.GenElem(m23)
+				if ok, err := b.Object(26, n23, k23, p23); err != nil {
+					return err
+				} else if ok {
+					{
+						n24, k24 := b.Field(27, n23, k23)
+						p24 := &p23.Street
+						if val, ok, err := b.Value(27, n24, k24, p24); err != nil {
+							return err
+						} else if ok {
+							if err := gofi.GenString(val, p24); err != nil {
+								return b.DecodeError(k24, err)
+							}
+						}
+					}
+					{
+						n25, k25 := b.Field(28, n23, k23)
+						p25 := &p23.City
+						if val, ok, err := b.Value(28, n25, k25, p25); err != nil {
+							return err
+						} else if ok {
+							if err := gofi.GenString(val, p25); err != nil {
+								return b.DecodeError(k25, err)
+							}
+						}
+					}
+					{
+						n26, k26 := b.Field(29, n23, k23)
+						p26 := &p23.Zip
+						if val, ok, err := b.Value(29, n26, k26, p26); err != nil {
+							return err
+						} else if ok {
+							gofi.GenAlloc(p26)
+							if err := gofi.GenNumber(val, *p26); err != nil {
+								return b.DecodeError(k26, err)
+							}
+						}
+					}
+					if err := b.ObjectEnd(26, n23, k23); err != nil {
+						return err
+					}
+				}
+				m23[key] = *p23
+				return nil
+			}); err != nil {
+				return err
+			}
+		}
+	}
+	{
+		n27, k27 := b.Field(30, n0, k0)
+		p27 := &p0.Meta
+		if obj, ok, err := b.Map(30, n27, k27, p27); err != nil {
+			return err
+		} else if ok {
+			gofi.GenMakeMap(p27)
+			m28 := *p27
+			if err := b.Entries(30, obj, k27, func(key string, n28 *gofi.JSONValue, k28 []string) error {
+				p28 := gofi.GenElem(m28)
+				if err := b.Reflect(31, n28, k28, p28); err != nil {
+					return err
+				}
+				m28[key] = *p28
+				return nil
+			}); err != nil {
+				return err
+			}
+		}
+	}
+	{
+		n29, k29 := b.Field(32, n0, k0)
+		p29 := &p0.Gift
+		if err := b.Reflect(32, n29, k29, p29); err != nil {
+			return err
+		}
+	}
+	{
+		n30, k30 := b.Field(33, n0, k0)
+		p30 := &p0.Deliver
+		if err := b.Reflect(33, n30, k30, p30); err != nil {
+			return err
+		}
+	}
+	return b.ObjectEnd(3, n0, k0)
+}
+
+func (*OrderSchema) GofiEncode(e *gofi.GenEncoder, key string, obj any) (bool, error) {
+	switch key {
+	case "Ok":
+		if r, ok := gofi.GenResponse[struct {
+			Body Order
+		}](obj); ok {
+			return true, gofiEncodeOrderSchemaOk(e, r)
+		}
+	case "Created":
+		if r, ok := gofi.GenResponse[struct {
+			Header struct {
+				Location string `json:"Location" validate:"required"`
+			}
+			Body Order
+		}](obj); ok {
+			return true, gofiEncodeOrderSchemaCreated(e, r)
+		}
+	case "Err":
+		if r, ok := gofi.GenResponse[struct {
+			Body struct {
+				Message string `json:"message" validate:"required"`
+			}
+		}](obj); ok {
+			return true, gofiEncodeOrderSchemaErr(e, r)
+		}
+	}
+	return false, nil
+}
+
+func gofiEncodeOrderSchemaOk(e *gofi.GenEncoder, r *struct {
+	Body Order
+}) error {
+	p0 := &r.Body
+	var kp0 []string
+	if done, err := gofi.GenEncodeStruct(e, 34, p0, kp0); err != nil {
+		return err
+	} else if !done {
+		if kp31, ok := e.Key(35, kp0, p0.Id == 0); ok {
+			p31 := &p0.Id
+			if err := gofi.GenEncodeInt(e, 35, p31, kp31); err != nil {
+				return err
+			}
+		}
+		if kp32, ok := e.Key(36, kp0, p0.Customer == ""); ok {
+			p32 := &p0.Customer
+			if err := gofi.GenEncodeString(e, 36, p32, kp32); err != nil {
+				return err
+			}
+		}
+		if kp33, ok := e.Key(37, kp0, p0.Status == ""); ok {
+			p33 := &p0.Status
+			if err := gofi.GenEncodeString(e, 37, p33, kp33); err != nil {
+				return err
+			}
+		}
+		if kp34, ok := e.Key(38, kp0, !p0.Paid); ok {
+			p34 := &p0.Paid
+			if err := gofi.GenEncodeBool(e, 38, p34, kp34); err != nil {
+				return err
+			}
+		}
+		if kp35, ok := e.Key(39, kp0, p0.Note == nil); ok {
+			p35 := &p0.Note
+			if done, err := gofi.GenEncodePointer(e, 39, p35, kp35); err != nil {
+				return err
+			} else if !done {
+				if err := gofi.GenEncodeString(e, 39, *p35, kp35); err != nil {
+					return err
+				}
+			}
+		}
+		if kp36, ok := e.Key(40, kp0, len(p0.Items) == 0); ok {
+			p36 := &p0.Items
+			if done, err := gofi.GenEncodeSlice(e, 40, p36, kp36); err != nil {
+				return err
+			} else if !done {
+				for j37 := range *p36 {
+					kp37 := append(kp36, strconv.Itoa(j37))
+					e.Sep()
+					p37 := &(*p36)[j37]
+					if done, err := gofi.GenEncodeStruct(e, 41, p37, kp37); err != nil {
+						return err
+					} else if !done {
+						if kp38, ok := e.Key(42, kp37, p37.Sku == ""); ok {
+							p38 := &p37.Sku
+							if err := gofi.GenEncodeString(e, 42, p38, kp38); err != nil {
+								return err
+							}
+						}
+						if kp39, ok := e.Key(43, kp37, false); ok {
+							p39 := &p37.Quantity
+							if err := gofi.GenEncodeUint(e, 43, p39, kp39); err != nil {
+								return err
+							}
+						}
+						if kp40, ok := e.Key(44, kp37, false); ok {
+							p40 := &p37.Price
+							if err := gofi.GenEncodeFloat(e, 44, p40, kp40); err != nil {
+								return err
+							}
+						}
+						if kp41, ok := e.Key(45, kp37, len(p37.Tags) == 0); ok {
+							p41 := &p37.Tags
+							if done, err := gofi.GenEncodeSlice(e, 45, p41, kp41); err != nil {
+								return err
+							} else if !done {
+								for j42 := range *p41 {
+									kp42 := append(kp41, strconv.Itoa(j42))
+									e.Sep()
+									p42 := &(*p41)[j42]
+									if err := gofi.GenEncodeString(e, 46, p42, kp42); err != nil {
+										return err
+									}
+								}
+								e.End(']')
+							}
+						}
+						if kp43, ok := e.Key(47, kp37, len(p37.Attrs) == 0); ok {
+							p43 := &p37.Attrs
+							if done, err := gofi.GenEncodeMap(e, 47, p43, kp43); err != nil {
+								return err
+							} else if !done {
+								for key44, val44 := range *p43 {
+									kp44 := append(kp43, key44)
+									e.Sep()
+									if err := e.MapKey(47, key44, kp44); err != nil {
+										return err
+									}
+									p44 := &val44
+									if err := gofi.GenEncodeString(e, 48, p44, kp44); err != nil {
+										return err
+									}
+								}
+								e.End('}')
+							}
+						}
+						e.End('}')
+					}
+				}
+				e.End(']')
+			}
+		}
+		if kp45, ok := e.Key(49, kp0, p0.Shipping == nil); ok {
+			p45 := &p0.Shipping
+			if done, err := gofi.GenEncodePointer(e, 49, p45, kp45); err != nil {
+				return err
+			} else if !done {
+				if done, err := gofi.GenEncodeStruct(e, 49, *p45, kp45); err != nil {
+					return err
+				} else if !done {
+					if kp46, ok := e.Key(50, kp45, (*p45).Street == ""); ok {
+						p46 := &(*p45).Street
+						if err := gofi.GenEncodeString(e, 50, p46, kp46); err != nil {
+							return err
+						}
+					}
+					if kp47, ok := e.Key(51, kp45, (*p45).City == ""); ok {
+						p47 := &(*p45).City
+						if err := gofi.GenEncodeString(e, 51, p47, kp47); err != nil {
+							return err
+						}
+					}
+					if kp48, ok := e.Key(52, kp45, (*p45).Zip == nil); ok {
+						p48 := &(*p45).Zip
+						if done, err := gofi.GenEncodePointer(e, 52, p48, kp48); err != nil {
+							return err
+						} else if !done {
+							if err := gofi.GenEncodeInt(e, 52, *p48, kp48); err != nil {
+								return err
+							}
+						}
+					}
+					e.End('}')
+				}
+			}
+		}
+		if kp49, ok := e.Key(53, kp0, len(p0.Discounts) == 0); ok {
+			p49 := &p0.Discounts
+			if done, err := gofi.GenEncodeMap(e, 53, p49, kp49); err != nil {
+				return err
+			} else if !done {
+				for key50, val50 := range *p49 {
+					kp50 := append(kp49, key50)
+					e.Sep()
+					if err := e.MapKey(53, key50, kp50); err != nil {
+						return err
+					}
+					p50 := &val50
+					if err := gofi.GenEncodeFloat(e, 54, p50, kp50); err != nil {
+						return err
+					}
+				}
+				e.End('}')
+			}
+		}
+		if err := e.Member(55, &p0.Placed, kp0); err != nil {
+			return err
+		}
+		e.End('}')
+	}
+	return nil
+}
+
+func gofiEncodeOrderSchemaCreated(e *gofi.GenEncoder, r *struct {
+	Header struct {
+		Location string `json:"Location" validate:"required"`
+	}
+	Body Order
+}) error {
+	p0 := &r.Body
+	var kp0 []string
+	if done, err := gofi.GenEncodeStruct(e, 56, p0, kp0); err != nil {
+		return err
+	} else if !done {
+		if kp52, ok := e.Key(57, kp0, p0.Id == 0); ok {
+			p52 := &p0.Id
+			if err := gofi.GenEncodeInt(e, 57, p52, kp52); err != nil {
+				return err
+			}
+		}
+		if kp53, ok := e.Key(58, kp0, p0.Customer == ""); ok {
+			p53 := &p0.Customer
+			if err := gofi.GenEncodeString(e, 58, p53, kp53); err != nil {
+				return err
+			}
+		}
+		if kp54, ok := e.Key(59, kp0, p0.Status == ""); ok {
+			p54 := &p0.Status
+			if err := gofi.GenEncodeString(e, 59, p54, kp54); err != nil {
+				return err
+			}
+		}
+		if kp55, ok := e.Key(60, kp0, !p0.Paid); ok {
+			p55 := &p0.Paid
+			if err := gofi.GenEncodeBool(e, 60, p55, kp55); err != nil {
+				return err
+			}
+		}
+		if kp56, ok := e.Key(61, kp0, p0.Note == nil); ok {
+			p56 := &p0.Note
+			if done, err := gofi.GenEncodePointer(e, 61, p56, kp56); err != nil {
+				return err
+			} else if !done {
+				if err := gofi.GenEncodeString(e, 61, *p56, kp56); err != nil {
+					return err
+				}
+			}
+		}
+		if kp57, ok := e.Key(62, kp0, len(p0.Items) == 0); ok {
+			p57 := &p0.Items
+			if done, err := gofi.GenEncodeSlice(e, 62, p57, kp57); err != nil {
+				return err
+			} else if !done {
+				for j58 := range *p57 {
+					kp58 := append(kp57, strconv.Itoa(j58))
+					e.Sep()
+					p58 := &(*p57)[j58]
+					if done, err := gofi.GenEncodeStruct(e, 63, p58, kp58); err != nil {
+						return err
+					} else if !done {
+						if kp59, ok := e.Key(64, kp58, p58.Sku == ""); ok {
+							p59 := &p58.Sku
+							if err := gofi.GenEncodeString(e, 64, p59, kp59); err != nil {
+								return err
+							}
+						}
+						if kp60, ok := e.Key(65, kp58, false); ok {
+							p60 := &p58.Quantity
+							if err := gofi.GenEncodeUint(e, 65, p60, kp60); err != nil {
+								return err
+							}
+						}
+						if kp61, ok := e.Key(66, kp58, false); ok {
+							p61 := &p58.Price
+							if err := gofi.GenEncodeFloat(e, 66, p61, kp61); err != nil {
+								return err
+							}
+						}
+						if kp62, ok := e.Key(67, kp58, len(p58.Tags) == 0); ok {
+							p62 := &p58.Tags
+							if done, err := gofi.GenEncodeSlice(e, 67, p62, kp62); err != nil {
+								return err
+							} else if !done {
+								for j63 := range *p62 {
+									kp63 := append(kp62, strconv.Itoa(j63))
+									e.Sep()
+									p63 := &(*p62)[j63]
+									if err := gofi.GenEncodeString(e, 68, p63, kp63); err != nil {
+										return err
+									}
+								}
+								e.End(']')
+							}
+						}
+						if kp64, ok := e.Key(69, kp58, len(p58.Attrs) == 0); ok {
+							p64 := &p58.Attrs
+							if done, err := gofi.GenEncodeMap(e, 69, p64, kp64); err != nil {
+								return err
+							} else if !done {
+								for key65, val65 := range *p64 {
+									kp65 := append(kp64, key65)
+									e.Sep()
+									if err := e.MapKey(69, key65, kp65); err != nil {
+										return err
+									}
+									p65 := &val65
+									if err := gofi.GenEncodeString(e, 70, p65, kp65); err != nil {
+										return err
+									}
+								}
+								e.End('}')
+							}
+						}
+						e.End('}')
+					}
+				}
+				e.End(']')
+			}
+		}
+		if kp66, ok := e.Key(71, kp0, p0.Shipping == nil); ok {
+			p66 := &p0.Shipping
+			if done, err := gofi.GenEncodePointer(e, 71, p66, kp66); err != nil {
+				return err
+			} else if !done {
+				if done, err := gofi.GenEncodeStruct(e, 71, *p66, kp66); err != nil {
+					return err
+				} else if !done {
+					if kp67, ok := e.Key(72, kp66, (*p66).Street == ""); ok {
+						p67 := &(*p66).Street
+						if err := gofi.GenEncodeString(e, 72, p67, kp67); err != nil {
+							return err
+						}
+					}
+					if kp68, ok := e.Key(73, kp66, (*p66).City == ""); ok {
+						p68 := &(*p66).City
+						if err := gofi.GenEncodeString(e, 73, p68, kp68); err != nil {
+							return err
+						}
+					}
+					if kp69, ok := e.Key(74, kp66, (*p66).Zip == nil); ok {
+						p69 := &(*p66).Zip
+						if done, err := gofi.GenEncodePointer(e, 74, p69, kp69); err != nil {
+							return err
+						} else if !done {
+							if err := gofi.GenEncodeInt(e, 74, *p69, kp69); err != nil {
+								return err
+							}
+						}
+					}
+					e.End('}')
+				}
+			}
+		}
+		if kp70, ok := e.Key(75, kp0, len(p0.Discounts) == 0); ok {
+			p70 := &p0.Discounts
+			if done, err := gofi.GenEncodeMap(e, 75, p70, kp70); err != nil {
+				return err
+			} else if !done {
+				for key71, val71 := range *p70 {
+					kp71 := append(kp70, key71)
+					e.Sep()
+					if err := e.MapKey(75, key71, kp71); err != nil {
+						return err
+					}
+					p71 := &val71
+					if err := gofi.GenEncodeFloat(e, 76, p71, kp71); err != nil {
+						return err
+					}
+				}
+				e.End('}')
+			}
+		}
+		if err := e.Member(77, &p0.Placed, kp0); err != nil {
+			return err
+		}
+		e.End('}')
+	}
+	return nil
+}
+
+func gofiEncodeOrderSchemaErr(e *gofi.GenEncoder, r *struct {
+	Body struct {
+		Message string `json:"message" validate:"required"`
+	}
+}) error {
+	p0 := &r.Body
+	var kp0 []string
+	if done, err := gofi.GenEncodeStruct(e, 78, p0, kp0); err != nil {
+		return err
+	} else if !done {
+		if kp73, ok := e.Key(79, kp0, p0.Message == ""); ok {
+			p73 := &p0.Message
+			if err := gofi.GenEncodeString(e, 79, p73, kp73); err != nil {
+				return err
+			}
+		}
+		e.End('}')
+	}
+	return nil
+}
@@ -0,0 +1,230 @@
+package gentest
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/michaelolof/gofi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// orderRouter serves the order of a request with the generated code of OrderSchema checked
+// against reflection. reply picks the response sent for the bound request.
+func orderRouter(t *testing.T, reply func(c gofi.Context, s *OrderSchema) error) gofi.Router {
+	t.Helper()
+
+	r := gofi.NewRouter()
+	r.Configure(gofi.Config{VerifyGenerated: true})
+	r.Put("/orders/:id", gofi.RouteOptions{
+		Schema: &OrderSchema{},
+		Handler: func(c gofi.Context) error {
+			s, err := gofi.ValidateAndBind[OrderSchema](c)
+			if err != nil {
+				assert.NotContains(t, err.Error(), "disagrees")
+				return err
+			}
+			return reply(c, s)
+		},
+	})
+	return r
+}
+
+func putOrder(t *testing.T, r gofi.Router, tenant string, path string, body string) *gofi.InjectResponse {
+	t.Helper()
+
+	headers := map[string]string{"Content-Type": "application/json"}
+	if tenant != "" {
+		headers["X-Tenant"] = tenant
+	}
+	res, err := r.Test(gofi.TestOptions{
+		Method:  "PUT",
+		Path:    path,
+		Headers: headers,
+		Body:    strings.NewReader(body),
+	})
+	require.NoError(t, err)
+	return res
+}
+
+const validOrder = `{
+	"customer": "ada",
+	"items": [
+		{"sku": "a-1", "quantity": 2, "price": 9.5, "tags": ["new", "red"], "attrs": {"size": "m"}},
+		{"sku": "b-2", "quantity": 1}
+	],
+	"shipping": {"street": "1 Main St", "city": "Oslo", "zip": 150},
+	"billing": {"street": "2 Side St", "city": "Rome"},
+	"codes": [1, 2, 3],
+	"notes": {"gate": {"street": "3 Back St", "city": "Lima"}},
+	"meta": {"source": "web", "retries": 2},
+	"gift": "a card",
+	"deliver": "2024-05-01T10:00:00Z"
+}`
+
+func TestGenerated_Bind(t *testing.T) {
+	var bound *OrderSchema
+	r := orderRouter(t, func(c gofi.Context, s *OrderSchema) error {
+		bound = s
+		return nil
+	})
+
+	res := putOrder(t, r, "acme", "/orders/7?dry=true&fields=id&fields=items", validOrder)
+	require.Equal(t, 200, res.StatusCode, string(res.Body))
+
+	req := bound.Request
+	assert.Equal(t, "acme", req.Header.Tenant)
+	assert.Equal(t, 7, req.Path.Id)
+	require.NotNil(t, req.Query.Dry)
+	assert.True(t, *req.Query.Dry)
+	assert.Equal(t, []string{"id", "items"}, req.Query.Fields)
+
+	body := req.Body
+	assert.Equal(t, "ada", body.Customer)
+	assert.Equal(t, Status("pending"), body.Status)
+	require.Len(t, body.Items, 2)
+	assert.Equal(t, Item{Sku: "a-1", Quantity: 2, Price: 9.5, Tags: []string{"new", "red"}, Attrs: map[string]string{"size": "m"}}, body.Items[0])
+	require.NotNil(t, body.Shipping)
+	require.NotNil(t, body.Shipping.Zip)
+	assert.Equal(t, 150, *body.Shipping.Zip)
+	assert.Equal(t, "Rome", body.Billing.City)
+	assert.Equal(t, []int{1, 2, 3}, body.Codes)
+	assert.Equal(t, "Lima", body.Notes["gate"].City)
+	assert.Equal(t, "web", body.Meta["source"])
+	assert.Equal(t, "a card", body.Gift.Value)
+	require.NotNil(t, body.Deliver)
+	assert.True(t, body.Deliver.Equal(time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)))
+}
+
+func TestGenerated_BindErrors(t *testing.T) {
+	r := orderRouter(t, func(c gofi.Context, s *OrderSchema) error {
+		return nil
+	})
+
+	tests := []struct {
+		name   string
+		tenant string
+		path   string
+		body   string
+	}{
+		{"missing header", "", "/orders/7", validOrder},
+		{"invalid path", "acme", "/orders/0", validOrder},
+		{"invalid query", "acme", "/orders/7?dry=maybe", validOrder},
+		{"all params", "", "/orders/0?dry=maybe", validOrder},
+		{"malformed body", "acme", "/orders/7", `{"customer": `},
+		{"not an object", "acme", "/orders/7", `[1, 2]`},
+		{"short customer", "acme", "/orders/7", `{"customer": "al", "items": [{"sku": "a", "quantity": 1}]}`},
+		{"wrong type", "acme", "/orders/7", `{"customer": 12, "items": [{"sku": "a", "quantity": 1}]}`},
+		{"missing items", "acme", "/orders/7", `{"customer": "ada"}`},
+		{"bad status", "acme", "/orders/7", `{"customer": "ada", "status": "lost", "items": [{"sku": "a", "quantity": 1}]}`},
+		{"bad item", "acme", "/orders/7", `{"customer": "ada", "items": [{"sku": "a", "quantity": 100}]}`},
+		{"negative quantity", "acme", "/orders/7", `{"customer": "ada", "items": [{"sku": "a", "quantity": -1}]}`},
+		{"bad shipping", "acme", "/orders/7", `{"customer": "ada", "items": [{"sku": "a", "quantity": 1}], "shipping": {"street": "x", "city": "y"}}`},
+		{"bad code", "acme", "/orders/7", `{"customer": "ada", "items": [{"sku": "a", "quantity": 1}], "codes": [1, "two"]}`},
+		{"bad note", "acme", "/orders/7", `{"customer": "ada", "items": [{"sku": "a", "quantity": 1}], "notes": {"a": {"city": "Lima"}}}`},
+		{"bad deliver", "acme", "/orders/7", `{"customer": "ada", "items": [{"sku": "a", "quantity": 1}], "deliver": "soon"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := putOrder(t, r, tt.tenant, tt.path, tt.body)
+			assert.NotEqual(t, 200, res.StatusCode)
+			assert.NotContains(t, string(res.Body), "disagrees")
+		})
+	}
+}
+
+func TestGenerated_Encode(t *testing.T) {
+	zip := 150
+	note := "leave at door"
+	order := Order{
+		Id:       7,
+		Customer: "ada",
+		Note:     &note,
+		Items: []Item{
+			{Sku: "a-1", Quantity: 2, Price: 9.5, Tags: []string{"new"}, Attrs: map[string]string{"size": "m"}},
+			{Sku: "b-2", Quantity: 1},
+		},
+		Shipping:  &Address{Street: "1 Main St", City: "Oslo", Zip: &zip},
+		Discounts: map[string]float32{"spring": 0.5},
+		Placed:    time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC),
+	}
+
+	tests := []struct {
+		name  string
+		reply func(c gofi.Context, s *OrderSchema) error
+		code  int
+		body  string
+	}{
+		{
+			name: "ok",
+			reply: func(c gofi.Context, s *OrderSchema) error {
+				s.Ok.Body = order
+				return c.Send(200, s.Ok)
+			},
+			code: 200,
+			body: `{"id":7,"customer":"ada","status":"pending","paid":false,"note":"leave at door","items":[{"sku":"a-1","quantity":2,"price":9.5,"tags":["new"],"attrs":{"size":"m"}},{"sku":"b-2","quantity":1,"price":0}],"shipping":{"street":"1 Main St","city":"Oslo","zip":150},"discounts":{"spring":0.5},"placed":"2024-05-01T10:00:00Z"}`,
+		},
+		{
+			name: "created pointer",
+			reply: func(c gofi.Context, s *OrderSchema) error {
+				s.Created.Header.Location = "/orders/7"
+				s.Created.Body = Order{Id: 7, Customer: "ada", Status: "paid", Paid: true, Items: []Item{{Sku: "a-1", Quantity: 1}}}
+				return c.Send(201, &s.Created)
+			},
+			code: 201,
+			body: `{"id":7,"customer":"ada","status":"paid","paid":true,"note":null,"items":[{"sku":"a-1","quantity":1,"price":0}],"placed":"0001-01-01T00:00:00Z"}`,
+		},
+		{
+			name: "missing items",
+			reply: func(c gofi.Context, s *OrderSchema) error {
+				s.Ok.Body = Order{Id: 7, Customer: "ada"}
+				return c.Send(200, s.Ok)
+			},
+			code: 500,
+		},
+		{
+			name: "invalid item",
+			reply: func(c gofi.Context, s *OrderSchema) error {
+				s.Ok.Body = Order{Id: 7, Customer: "ada", Items: []Item{{Sku: "a", Quantity: 0}}}
+				return c.Send(200, s.Ok)
+			},
+			code: 500,
+		},
+		{
+			name: "invalid status",
+			reply: func(c gofi.Context, s *OrderSchema) error {
+				s.Ok.Body = Order{Id: 7, Customer: "ada", Status: "lost", Items: []Item{{Sku: "a-1", Quantity: 1}}}
+				return c.Send(200, s.Ok)
+			},
+			code: 500,
+		},
+		{
+			name: "error response",
+			reply: func(c gofi.Context, s *OrderSchema) error {
+				s.Err.Body.Message = "out of stock"
+				return c.Send(500, s.Err)
+			},
+			code: 500,
+			body: `{"message":"out of stock"}`,
+		},
+		{
+			name: "invalid error response",
+			reply: func(c gofi.Context, s *OrderSchema) error {
+				return c.Send(500, s.Err)
+			},
+			code: 500,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := orderRouter(t, tt.reply)
+			res := putOrder(t, r, "acme", "/orders/7", validOrder)
+			assert.Equal(t, tt.code, res.StatusCode, string(res.Body))
+			assert.NotContains(t, string(res.Body), "disagrees")
+			if tt.body != "" {
+				assert.JSONEq(t, tt.body, string(res.Body))
+			}
+		})
+	}
+}
@@ -0,0 +1,290 @@
+package gofi
+
+import (
+	"maps"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// defaultMessages is the built-in English catalog that request validation messages are rendered
+// from. Templates may use {field} (the field name or
+// key path), {value} (the rendered value, see ValidationError.Value), {args} (all rule
+// arguments, comma separated) and {0}, {1}... for individual arguments.
+var defaultMessages = map[string]string{
+	"required":           "{field} is required",
+	"present":            "{field} must be present",
+	"not_empty":          "{field} must not be empty",
+	"allow_zero":         "{field} must be set",
+	"isdefault":          "{field} must be empty",
+	"min":                "{field} must be at least {0}",
+	"max":                "{field} must be at most {0}",
+	"len":                "{field} must have a length of {0}",
+	"eq":                 "{field} must be equal to {0}",
+	"ne":                 "{field} must not be equal to {0}",
+	"gt":                 "{field} must be greater than {0}",
+	"gte":                "{field} must be greater than or equal to {0}",
+	"lt":                 "{field} must be less than {0}",
+	"lte":                "{field} must be less than or equal to {0}",
+	"oneof":              "{field} must be one of: {args}",
+	"email":              "{field} must be a valid email address",
+	"url":                "{field} must be a valid URL",
+	"http_url":           "{field} must be a valid HTTP or HTTPS URL",
+	"fileUrl":            "{field} must be a valid file URL",
+	"uri":                "{field} must be a valid URI",
+	"urn_rfc2141":        "{field} must be a valid URN",
+	"url_encoded":        "{field} must be URL encoded",
+	"datauri":            "{field} must be a valid data URI",
+	"uuid":               "{field} must be a valid UUID",
+	"uuid3":              "{field} must be a valid version 3 UUID",
+	"uuid4":              "{field} must be a valid version 4 UUID",
+	"uuid5":              "{field} must be a valid version 5 UUID",
+	"uuid_rfc4122":       "{field} must be a valid RFC 4122 UUID",
+	"uuid3_rfc4122":      "{field} must be a valid RFC 4122 version 3 UUID",
+	"uuid4_rfc4122":      "{field} must be a valid RFC 4122 version 4 UUID",
+	"uuid5_rfc4122":      "{field} must be a valid RFC 4122 version 5 UUID",
+	"ulid":               "{field} must be a valid ULID",
+	"ip":                 "{field} must be a valid IP address",
+	"ipv4":               "{field} must be a valid IPv4 address",
+	"ipv6":               "{field} must be a valid IPv6 address",
+	"ip_addr":            "{field} must be a resolvable IP address",
+	"ip4_addr":           "{field} must be a resolvable IPv4 address",
+	"ip6_addr":           "{field} must be a resolvable IPv6 address",
+	"tcp_addr":           "{field} must be a resolvable TCP address",
+	"tcp4_addr":          "{field} must be a resolvable TCPv4 address",
+	"tcp6_addr":          "{field} must be a resolvable TCPv6 address",
+	"udp_addr":           "{field} must be a resolvable UDP address",
+	"udp4_addr":          "{field} must be a resolvable UDPv4 address",
+	"udp6_addr":          "{field} must be a resolvable UDPv6 address",
+	"unix_addr":          "{field} must be a resolvable Unix address",
+	"cidr":               "{field} must be a valid CIDR notation",
+	"cidrv4":             "{field} must be a valid IPv4 CIDR notation",
+	"cidrv6":             "{field} must be a valid IPv6 CIDR notation",
+	"mac":                "{field} must be a valid MAC address",
+	"fqdn":               "{field} must be a fully qualified domain name",
+	"hostname":           "{field} must be a valid hostname",
+	"hostname_rfc1123":   "{field} must be a valid RFC 1123 hostname",
+	"hostname_port":      "{field} must be a valid host and port",
+	"alpha":              "{field} must contain only letters",
+	"alphanum":           "{field} must contain only letters and digits",
+	"alphaunicode":       "{field} must contain only unicode letters",
+	"alphaunicodenum":    "{field} must contain only unicode letters and digits",
+	"ascii":              "{field} must contain only ASCII characters",
+	"printascii":         "{field} must contain only printable ASCII characters",
+	"multibyte":          "{field} must contain multibyte characters",
+	"lowercase":          "{field} must be lowercase",
+	"uppercase":          "{field} must be uppercase",
+	"numeric":            "{field} must be numeric",
+	"number":             "{field} must be a number",
+	"boolean":            "{field} must be a boolean",
+	"hexadecimal":        "{field} must be hexadecimal",
+	"hexcolor":           "{field} must be a valid hex color",
+	"rgb":                "{field} must be a valid RGB color",
+	"rgba":               "{field} must be a valid RGBA color",
+	"hsl":                "{field} must be a valid HSL color",
+	"hsla":               "{field} must be a valid HSLA color",
+	"e164":               "{field} must be a valid E.164 phone number",
+	"isbn10":             "{field} must be a valid ISBN-10",
+	"isbn13":             "{field} must be a valid ISBN-13",
+	"issn":               "{field} must be a valid ISSN",
+	"ssn":                "{field} must be a valid SSN",
+	"ein":                "{field} must be a valid EIN",
+	"bic":                "{field} must be a valid BIC",
+	"credit_card":        "{field} must be a valid credit card number",
+	"luhn_checksum":      "{field} must have a valid Luhn checksum",
+	"semver":             "{field} must be a valid semantic version",
+	"cve":                "{field} must be a valid CVE identifier",
+	"base32":             "{field} must be valid base32",
+	"base64":             "{field} must be valid base64",
+	"base64url":          "{field} must be valid URL-safe base64",
+	"base64rawurl":       "{field} must be valid unpadded URL-safe base64",
+	"md4":                "{field} must be an MD4 hash",
+	"md5":                "{field} must be an MD5 hash",
+	"sha256":             "{field} must be a SHA-256 hash",
+	"sha384":             "{field} must be a SHA-384 hash",
+	"sha512":             "{field} must be a SHA-512 hash",
+	"ripemd128":          "{field} must be a RIPEMD-128 hash",
+	"ripemd160":          "{field} must be a RIPEMD-160 hash",
+	"tiger128":           "{field} must be a Tiger128 hash",
+	"tiger160":           "{field} must be a Tiger160 hash",
+	"tiger192":           "{field} must be a Tiger192 hash",
+	"html":               "{field} must be HTML",
+	"html_encoded":       "{field} must be HTML encoded",
+	"json":               "{field} must be valid JSON",
+	"jwt":                "{field} must be a valid JWT",
+	"cron":               "{field} must be a valid cron expression",
+	"latitude":           "{field} must be a valid latitude",
+	"longitude":          "{field} must be a valid longitude",
+	"btc_addr":           "{field} must be a valid Bitcoin address",
+	"btc_addr_bech32":    "{field} must be a valid Bech32 Bitcoin address",
+	"eth_addr":           "{field} must be a valid Ethereum address",
+	"mongodb":            "{field} must be a valid MongoDB ObjectId",
+	"spicedb_id":         "{field} must be a valid SpiceDB ID",
+	"spicedb_permission": "{field} must be a valid SpiceDB permission",
+	"spicedb_type":       "{field} must be a valid SpiceDB type",
+	"contains":           "{field} must contain '{0}'",
+	"containsany":        "{field} must contain any of '{0}'",
+	"containsrune":       "{field} must contain '{0}'",
+	"excludes":           "{field} must not contain '{0}'",
+	"excludesall":        "{field} must not contain any of '{0}'",
+	"excludesrune":       "{field} must not contain '{0}'",
+	"startswith":         "{field} must start with '{0}'",
+	"endswith":           "{field} must end with '{0}'",
+	"datetime":           "{field} must be a date matching {0}",
+	"timezone":           "{field} must be a valid time zone",
+	"file":               "{field} must be an existing file",
+	"dir":                "{field} must be an existing directory",
+	"filepath":           "{field} must be a valid file path",
+	"eqfield":            "{field} must be equal to {0}",
+	"nefield":            "{field} must not be equal to {0}",
+	"gtfield":            "{field} must be greater than {0}",
+	"gtefield":           "{field} must be greater than or equal to {0}",
+	"ltfield":            "{field} must be less than {0}",
+	"ltefield":           "{field} must be less than or equal to {0}",
+	"required_if":        "{field} is required when {0} is {1}",
+	"required_unless":    "{field} is required unless {0} is {1}",
+	"required_with":      "{field} is required when {args} is present",
+	"required_without":   "{field} is required when {args} is missing",
+	"excluded_with":      "{field} must be omitted when {args} is present",
+	"typeMismatch":       "{field} has the wrong type",
+	"parser":             "{field} could not be parsed",
+	"depth":              "{field} is nested too deeply",
+	"patchPath":          "{field} does not point to a value",
+	"patchTest":          "{field} does not match the current value",
+	"unknownField":       "{field} is not allowed",
+}
+
+// DefaultMessages returns a copy of the built-in English message catalog, keyed by rule name.
+// Request validation messages are rendered from it unless a registered catalog overrides them.
+// Use it as a starting point for a translation, or pass a few entries back to
+// Router.RegisterMessages("en", ...) to override individual messages.
+func DefaultMessages() map[string]string {
+	return maps.Clone(defaultMessages)
+}
+
+// messageCatalogs holds the catalogs registered with Router.RegisterMessages, keyed by
+// lower-cased language tag.
+type messageCatalogs map[string]map[string]string
+
+func (m messageCatalogs) register(lang string, messages map[string]string) {
+	lang = strings.ToLower(strings.TrimSpace(lang))
+	catalog, ok := m[lang]
+	if !ok {
+		catalog = make(map[string]string, len(messages))
+		m[lang] = catalog
+	}
+	maps.Copy(catalog, messages)
+}
+
+// lookup returns the catalog for lang, falling back from a regional tag to its base language
+// (pt-BR to pt).
+func (m messageCatalogs) lookup(lang string) (map[string]string, bool) {
+	lang = strings.ToLower(lang)
+	if c, ok := m[lang]; ok {
+		return c, true
+	}
+	if i := strings.IndexByte(lang, '-'); i > 0 {
+		c, ok := m[lang[:i]]
+		return c, ok
+	}
+	return nil, false
+}
+
+// negotiate picks the registered language that best matches an Accept-Language header.
+func (m messageCatalogs) negotiate(header string) string {
+	type candidate struct {
+		tag string
+		q   float64
+	}
+
+	var candidates []candidate
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if tag == "" || tag == "*" {
+			continue
+		}
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if f, err := strconv.ParseFloat(v, 64); err == nil {
+				q = f
+			}
+		}
+		if q > 0 {
+			candidates = append(candidates, candidate{tag, q})
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].q > candidates[j].q })
+
+	for _, c := range candidates {
+		if _, ok := m.lookup(c.tag); ok {
+			return c.tag
+		}
+	}
+	return ""
+}
+
+// renderMessage fills a catalog template for e.
+func renderMessage(tmpl string, e *errReport) string {
+	field := e.schemaValue
+	if field == "" {
+		field = strings.ToLower(string(e.field))
+	}
+
+	pairs := []string{"{field}", field, "{value}", e.Value(), "{args}", strings.Join(e.args, ", ")}
+	for i, arg := range e.args {
+		pairs = append(pairs, "{"+strconv.Itoa(i)+"}", arg)
+	}
+	return strings.NewReplacer(pairs...).Replace(tmpl)
+}
+
+// language returns the language used for this request's validation messages. Requests that
+// match no registered language fall back to "en", so English overrides apply to them.
+func (c *context) language() string {
+	var lang string
+	if c.serverOpts.langResolver != nil {
+		lang = c.serverOpts.langResolver(c)
+	} else {
+		lang = c.serverOpts.messages.negotiate(c.HeaderVal("Accept-Language"))
+	}
+	if lang == "" {
+		return "en"
+	}
+	return lang
+}
+
+// localizeErrors renders validation messages from the catalog for the request language. Rules
+// that catalog does not cover use the English catalog, i.e. the built-in templates with any
+// "en" overrides. Only rules missing from every catalog, such as custom validators, keep the
+// message of their error.
+func (c *context) localizeErrors(errs ValidationErrors) {
+	var catalog, english map[string]string
+	if c.serverOpts != nil && len(c.serverOpts.messages) > 0 {
+		catalog, _ = c.serverOpts.messages.lookup(c.language())
+		english = c.serverOpts.messages["en"]
+	}
+	localizeReports(errs, catalog, english, defaultMessages)
+}
+
+// localizeReports renders the message of every failure in errs from the first catalog with a
+// template for its rule.
+func localizeReports(errs ValidationErrors, catalogs ...map[string]string) {
+	for _, e := range errs {
+		report, ok := e.(*errReport)
+		if !ok {
+			continue
+		}
+		if tmpl, ok := lookupMessage(report.rule, catalogs...); ok {
+			report.localized = renderMessage(tmpl, report)
+		}
+	}
+}
+
+// lookupMessage returns the template for rule from the first catalog that has one.
+func lookupMessage(rule string, catalogs ...map[string]string) (string, bool) {
+	for _, catalog := range catalogs {
+		if tmpl, ok := catalog[rule]; ok {
+			return tmpl, true
+		}
+	}
+	return "", false
+}
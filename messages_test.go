@@ -0,0 +1,107 @@
+package gofi
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/michaelolof/gofi/validators"
+	"github.com/michaelolof/gofi/validators/rules"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type messagesSchema struct {
+	Request struct {
+		Query struct {
+			Page int `json:"page" validate:"min=1"`
+		}
+		Body struct {
+			Name string `json:"name" validate:"required"`
+		}
+	}
+}
+
+func bindWithLanguage(t *testing.T, r Router, acceptLanguage string) ValidationErrors {
+	t.Helper()
+
+	var bindErr error
+	_, err := r.Inject(InjectOptions{
+		Path:    "/users",
+		Method:  "POST",
+		Query:   map[string]string{"page": "0"},
+		Headers: map[string]string{"Content-Type": "application/json", "Accept-Language": acceptLanguage},
+		Body:    strings.NewReader(`{}`),
+		Handler: &RouteOptions{
+			Schema: &messagesSchema{},
+			Handler: func(c Context) error {
+				_, bindErr = ValidateAndBind[messagesSchema](c)
+				return nil
+			},
+		},
+	})
+	require.Nil(t, err)
+
+	var verrs ValidationErrors
+	require.ErrorAs(t, bindErr, &verrs)
+	return verrs
+}
+
+func messagesByRule(verrs ValidationErrors) map[string]string {
+	out := make(map[string]string, len(verrs))
+	for _, e := range verrs {
+		out[e.Rule()] = e.Message()
+	}
+	return out
+}
+
+func TestRegisterMessages_AcceptLanguage(t *testing.T) {
+	r := NewRouter()
+	r.RegisterMessages("fr", map[string]string{
+		"required": "{field} est obligatoire",
+		"min":      "{field} doit être au moins {0} (reçu {value})",
+	})
+	r.RegisterMessages("pt", map[string]string{
+		"required": "{field} é obrigatório",
+	})
+
+	fr := messagesByRule(bindWithLanguage(t, r, "fr-CA,fr;q=0.9,en;q=0.5"))
+	assert.Equal(t, "name est obligatoire", fr["required"])
+	assert.Equal(t, "page doit être au moins 1 (reçu 0)", fr["min"])
+
+	pt := messagesByRule(bindWithLanguage(t, r, "de;q=0.9, pt-BR;q=0.8"))
+	assert.Equal(t, "name é obrigatório", pt["required"])
+	// Rules the catalog does not cover use the English catalog.
+	assert.Equal(t, "page must be at least 1", pt["min"])
+
+	unknown := messagesByRule(bindWithLanguage(t, r, "de"))
+	assert.Equal(t, "name is required", unknown["required"])
+}
+
+func TestRegisterMessages_EnglishOverridesAndResolver(t *testing.T) {
+	r := NewRouter()
+	en := DefaultMessages()
+	assert.Equal(t, "{field} is required", en["required"])
+
+	r.RegisterMessages("en", map[string]string{"required": "please fill in {field}"})
+	msgs := messagesByRule(bindWithLanguage(t, r, ""))
+	assert.Equal(t, "please fill in name", msgs["required"])
+
+	r.RegisterMessages("fr", map[string]string{"required": "{field} est obligatoire"})
+	r.UseLanguageResolver(func(c Context) string { return "fr" })
+	msgs = messagesByRule(bindWithLanguage(t, r, "en"))
+	assert.Equal(t, "name est obligatoire", msgs["required"])
+}
+
+func TestDefaultMessages_RenderBuiltInRules(t *testing.T) {
+	en := DefaultMessages()
+	for name := range validators.Validators {
+		assert.Contains(t, en, name)
+	}
+	for name := range rules.CrossFieldChecks {
+		assert.Contains(t, en, name)
+	}
+
+	msgs := messagesByRule(bindWithLanguage(t, NewRouter(), ""))
+	assert.Equal(t, "name is required", msgs["required"])
+	assert.Equal(t, "page must be at least 1", msgs["min"])
+}
@@ -0,0 +1,78 @@
+package gofi
+
+import (
+	"reflect"
+	"regexp"
+	"strings"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Modifier normalizes request input before it is validated and bound. Modifiers are listed in
+// the mod tag and applied in order:
+//
+//	Email string `json:"email" mod:"trim,lower" validate:"required,email"`
+//
+// Header, query, path, cookie and form values are modified as raw strings before they are
+// converted, JSON bodies on string fields (and the items of string slices and maps).
+type Modifier interface {
+	Name() string
+	Modify(val string, args []string) string
+}
+
+type modifierFn func(val string, args []string) string
+
+var htmlTagRegex = regexp.MustCompile(`<[^>]*>`)
+
+// builtinModifiers are the modifiers available in every mod tag.
+var builtinModifiers = map[string]modifierFn{
+	"trim":  func(s string, _ []string) string { return strings.TrimSpace(s) },
+	"lower": func(s string, _ []string) string { return strings.ToLower(s) },
+	"upper": func(s string, _ []string) string { return strings.ToUpper(s) },
+	// cases.Caser is stateful, so a new one is used for every value.
+	"title":      func(s string, _ []string) string { return cases.Title(language.Und).String(s) },
+	"squish":     func(s string, _ []string) string { return strings.Join(strings.Fields(s), " ") },
+	"strip_html": func(s string, _ []string) string { return htmlTagRegex.ReplaceAllString(s, "") },
+	"nfc":        func(s string, _ []string) string { return norm.NFC.String(s) },
+	"default_if_empty": func(s string, args []string) string {
+		if s == "" {
+			return strings.Join(args, " ")
+		}
+		return s
+	},
+}
+
+type modifierOpts struct {
+	name string
+	args []string
+	fn   modifierFn
+}
+
+// modify applies the mod tag of r to s.
+func (r *RuleDef) modify(s string) string {
+	for _, m := range r.mods {
+		s = m.fn(s, m.args)
+	}
+	return s
+}
+
+// inheritMods applies the mod tag of a slice or map field to its string items.
+func (r *RuleDef) inheritMods(parent *RuleDef) {
+	if len(r.mods) == 0 && r.kind == reflect.String {
+		r.mods = parent.mods
+	}
+}
+
+// lookupModifier finds a modifier by name. Built-in modifiers take precedence over registered ones.
+func (m *muxOptions) lookupModifier(name string) (modifierFn, bool) {
+	if fn, ok := builtinModifiers[name]; ok {
+		return fn, true
+	}
+	if m != nil {
+		fn, ok := m.modifiers[name]
+		return fn, ok
+	}
+	return nil, false
+}
@@ -0,0 +1,134 @@
+package gofi
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type modSchema struct {
+	Request struct {
+		Header struct {
+			Tenant string `json:"x-tenant" mod:"trim,lower"`
+		}
+		Query struct {
+			Page int    `json:"page" mod:"trim"`
+			Sort string `json:"sort" mod:"trim,default_if_empty=created_at"`
+		}
+		Body struct {
+			Email   string            `json:"email" mod:"trim,lower" validate:"required,email"`
+			Name    string            `json:"name" mod:"squish,title"`
+			Code    string            `json:"code" mod:"upper"`
+			Bio     string            `json:"bio" mod:"strip_html,trim"`
+			Accent  string            `json:"accent" mod:"nfc"`
+			Tags    []string          `json:"tags" mod:"trim,lower" validate:"dive,alpha"`
+			Labels  map[string]string `json:"labels" mod:"trim"`
+			Handle  string            `json:"handle" mod:"slug"`
+			Nothing string            `json:"nothing" mod:"trim" validate:"required"`
+		}
+	}
+}
+
+type slugModifier struct{}
+
+func (slugModifier) Name() string { return "slug" }
+
+func (slugModifier) Modify(val string, _ []string) string {
+	return strings.ReplaceAll(strings.ToLower(strings.TrimSpace(val)), " ", "-")
+}
+
+func bindMods(t *testing.T, query map[string]string, body string) (*modSchema, error) {
+	t.Helper()
+
+	var s *modSchema
+	var bindErr error
+	r := NewRouter()
+	r.RegisterModifier(slugModifier{})
+	_, err := r.Inject(InjectOptions{
+		Path:    "/mods",
+		Method:  "POST",
+		Query:   query,
+		Headers: map[string]string{"Content-Type": "application/json", "X-Tenant": "  ACME "},
+		Body:    strings.NewReader(body),
+		Handler: &RouteOptions{
+			Schema: &modSchema{},
+			Handler: func(c Context) error {
+				s, bindErr = ValidateAndBind[modSchema](c)
+				return nil
+			},
+		},
+	})
+	require.Nil(t, err)
+	return s, bindErr
+}
+
+func TestModifiers(t *testing.T) {
+	s, err := bindMods(t, map[string]string{"page": "+2+", "sort": "++"}, `{
+		"email": "  John@Example.COM ",
+		"name": "  jane   van  doe ",
+		"code": "ng-la",
+		"bio": " <b>Hello</b> <script>x</script>world ",
+		"accent": "e\u0301",
+		"tags": [" Go ", "WEB"],
+		"labels": {"a": " x "},
+		"handle": " My Handle ",
+		"nothing": "ok"
+	}`)
+	require.NoError(t, err)
+
+	assert.Equal(t, "acme", s.Request.Header.Tenant)
+	assert.Equal(t, 2, s.Request.Query.Page)
+	assert.Equal(t, "created_at", s.Request.Query.Sort)
+
+	b := s.Request.Body
+	assert.Equal(t, "john@example.com", b.Email)
+	assert.Equal(t, "Jane Van Doe", b.Name)
+	assert.Equal(t, "NG-LA", b.Code)
+	assert.Equal(t, "Hello xworld", b.Bio)
+	assert.Equal(t, "\u00e9", b.Accent)
+	assert.Equal(t, []string{"go", "web"}, b.Tags)
+	assert.Equal(t, map[string]string{"a": "x"}, b.Labels)
+	assert.Equal(t, "my-handle", b.Handle)
+}
+
+func TestModifiers_RunBeforeRules(t *testing.T) {
+	// Whitespace is trimmed before required sees the value.
+	_, err := bindMods(t, nil, `{"email": "a@example.com", "nothing": "   "}`)
+	var verr ValidationError
+	require.True(t, errors.As(err, &verr))
+	assert.Equal(t, "/nothing", verr.Pointer())
+	assert.Equal(t, "required", verr.Rule())
+}
+
+func TestModifiers_Form(t *testing.T) {
+	type formSchema struct {
+		Request struct {
+			Body struct {
+				Email string `json:"email" mod:"trim,lower" validate:"email"`
+			}
+		}
+	}
+
+	var s *formSchema
+	var bindErr error
+	r := NewRouter()
+	_, err := r.Inject(InjectOptions{
+		Path:    "/mods",
+		Method:  "POST",
+		Headers: map[string]string{"Content-Type": "application/x-www-form-urlencoded"},
+		Body:    strings.NewReader("email=+A%40Example.com+"),
+		Handler: &RouteOptions{
+			Schema: &formSchema{},
+			Handler: func(c Context) error {
+				s, bindErr = ValidateAndBind[formSchema](c)
+				return nil
+			},
+		},
+	})
+	require.Nil(t, err)
+	require.NoError(t, bindErr)
+	assert.Equal(t, "a@example.com", s.Request.Body.Email)
+}
@@ -0,0 +1,143 @@
+package gofi
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/michaelolof/gofi/utils"
+)
+
+// isMultiValue reports whether a header or cookie binds every value sent under its name: a
+// slice without a spec of its own.
+func (r *RuleDef) isMultiValue() bool {
+	return r.format == "" && r.kind == reflect.Slice
+}
+
+// repeatedElem returns the item type of a slice cookie field, which binds the cookies sent
+// under the same name. Other types are returned unchanged.
+func repeatedElem(typ reflect.Type) reflect.Type {
+	if typ.Kind() == reflect.Slice && !utils.IsByteSlice(typ) {
+		return typ.Elem()
+	}
+	return typ
+}
+
+// splitHeaderList splits a list header such as Accept or X-Forwarded-For into its elements.
+// Elements are trimmed and empty ones dropped. Commas inside quoted strings do not split.
+func splitHeaderList(dst []string, v string) []string {
+	var quoted, escaped bool
+	start := 0
+	for i := 0; i < len(v); i++ {
+		switch {
+		case escaped:
+			escaped = false
+		case quoted && v[i] == '\\':
+			escaped = true
+		case v[i] == '"':
+			quoted = !quoted
+		case v[i] == ',' && !quoted:
+			dst = appendListElem(dst, v[start:i])
+			start = i + 1
+		}
+	}
+	return appendListElem(dst, v[start:])
+}
+
+func appendListElem(dst []string, s string) []string {
+	if s = strings.Trim(s, " \t"); s != "" {
+		dst = append(dst, s)
+	}
+	return dst
+}
+
+// headerValues returns every value of a slice header. Repeated headers are merged and each
+// line is split on commas, except for times: an HTTP-date has a comma of its own.
+func (c *context) headerValues(def *RuleDef) *paramTree {
+	lines := c.fctx.Request.Header.PeekAll(def.field)
+	if len(lines) == 0 {
+		return nil
+	}
+
+	split := def.item == nil || def.item.format != utils.TimeObjectFormat
+	n := &paramTree{}
+	for _, l := range lines {
+		if split {
+			n.vals = splitHeaderList(n.vals, string(l))
+		} else {
+			n.vals = appendListElem(n.vals, string(l))
+		}
+	}
+	return n
+}
+
+// cookieValues returns the values of every cookie sent under the name of a slice cookie.
+func (c *context) cookieValues(def *RuleDef) *paramTree {
+	var n *paramTree
+	c.fctx.Request.Header.VisitAllCookie(func(k, v []byte) {
+		if string(k) != def.field {
+			return
+		}
+		if n == nil {
+			n = &paramTree{}
+		}
+		n.vals = append(n.vals, string(v))
+	})
+	return n
+}
+
+// encodeHeaderValues validates the elements of a slice response header and returns them
+// as header values.
+func (c *context) encodeHeaderValues(def *RuleDef, key string, hf reflect.Value) ([]string, error) {
+	for hf.Kind() == reflect.Pointer {
+		if hf.IsNil() {
+			return nil, nil
+		}
+		hf = hf.Elem()
+	}
+
+	vals := make([]string, 0, hf.Len())
+	for i := 0; i < hf.Len(); i++ {
+		s, err := c.encodeHeaderValue(def.item, fmt.Sprintf("%s.%d", key, i), hf.Index(i).Interface())
+		if err != nil {
+			return nil, err
+		}
+		vals = append(vals, s)
+	}
+	return vals, runValidation(hf.Interface(), ResponseErr, schemaHeaders, key, def.rules)
+}
+
+func (c *context) encodeHeaderValue(def *RuleDef, key string, v any) (string, error) {
+	if spec, ok := c.serverOpts.customSpecs.Find(string(def.format)); ok {
+		s, err := spec.Encode(v)
+		if err != nil {
+			return "", newErrReport(ResponseErr, schemaHeaders, key, "typeMismatch", err)
+		}
+		return s, runValidation(s, ResponseErr, schemaHeaders, key, def.rules)
+	}
+
+	if err := runValidation(v, ResponseErr, schemaHeaders, key, def.rules); err != nil {
+		return "", err
+	}
+	switch tv := v.(type) {
+	case time.Time:
+		return tv.Format(def.pattern), nil
+	case *time.Time:
+		if tv == nil {
+			return "", nil
+		}
+		return tv.Format(def.pattern), nil
+	}
+	if utils.IsPrimitiveKind(def.kind) {
+		rv := reflect.ValueOf(v)
+		if rv.Kind() == reflect.Pointer {
+			if rv.IsNil() {
+				return "", nil
+			}
+			rv = rv.Elem()
+		}
+		return fmt.Sprintf("%v", rv.Interface()), nil
+	}
+	return "", newErrReport(ResponseErr, schemaHeaders, key, "parser", fmt.Errorf("unable to encode %T as a header", v))
+}
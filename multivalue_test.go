@@ -0,0 +1,192 @@
+package gofi
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type multiValueSchema struct {
+	Request struct {
+		Header struct {
+			Accept    []string    `json:"Accept"`
+			Forwarded []string    `json:"X-Forwarded-For" validate:"dive,ip"`
+			Ids       []int       `json:"X-Ids" validate:"max=3"`
+			Codes     *[]string   `json:"X-Codes"`
+			Since     []time.Time `json:"X-Since" pattern:"Mon, 02 Jan 2006 15:04:05 MST"`
+			Trace     string      `json:"X-Trace"`
+		}
+		Cookie struct {
+			Pref  []string `json:"pref" validate:"dive,oneof=dark compact"`
+			Token string   `json:"token"`
+		}
+	}
+
+	Ok struct {
+		Header struct {
+			Vary  []string `json:"Vary" validate:"required,dive,alpha"`
+			Links []int    `json:"X-Links"`
+		}
+	}
+}
+
+func injectMultiValue(t *testing.T, headers map[string]string, cookies []http.Cookie, handler func(c Context) error) *InjectResponse {
+	t.Helper()
+
+	res, err := NewRouter().Inject(InjectOptions{
+		Path:    "/multi",
+		Method:  "GET",
+		Headers: headers,
+		Cookies: cookies,
+		Handler: &RouteOptions{Schema: &multiValueSchema{}, Handler: handler},
+	})
+	require.Nil(t, err)
+	return res
+}
+
+func TestMultiValue_Bind(t *testing.T) {
+	var s *multiValueSchema
+	var bindErr error
+	injectMultiValue(t, map[string]string{
+		"Accept":          `text/html, application/json;q=0.9, text/plain;note="a, b"`,
+		"X-Forwarded-For": "10.0.0.1, ::1,,",
+		"X-Ids":           "1,2",
+		"X-Codes":         "a,b",
+		"X-Since":         "Mon, 02 Jan 2006 15:04:05 GMT",
+		"X-Trace":         "a,b",
+	}, []http.Cookie{
+		{Name: "pref", Value: "dark"},
+		{Name: "token", Value: "t"},
+		{Name: "pref", Value: "compact"},
+	}, func(c Context) error {
+		s, bindErr = ValidateAndBind[multiValueSchema](c)
+		return nil
+	})
+	require.NoError(t, bindErr)
+
+	h := s.Request.Header
+	assert.Equal(t, []string{"text/html", "application/json;q=0.9", `text/plain;note="a, b"`}, h.Accept)
+	assert.Equal(t, []string{"10.0.0.1", "::1"}, h.Forwarded)
+	assert.Equal(t, []int{1, 2}, h.Ids)
+	require.NotNil(t, h.Codes)
+	assert.Equal(t, []string{"a", "b"}, *h.Codes)
+	require.Len(t, h.Since, 1)
+	assert.Equal(t, 2006, h.Since[0].Year())
+	assert.Equal(t, "a,b", h.Trace)
+	assert.Equal(t, []string{"dark", "compact"}, s.Request.Cookie.Pref)
+	assert.Equal(t, "t", s.Request.Cookie.Token)
+}
+
+func TestMultiValue_Absent(t *testing.T) {
+	var s *multiValueSchema
+	var bindErr error
+	injectMultiValue(t, nil, nil, func(c Context) error {
+		s, bindErr = ValidateAndBind[multiValueSchema](c)
+		return nil
+	})
+	require.NoError(t, bindErr)
+	assert.Nil(t, s.Request.Header.Accept)
+	assert.Nil(t, s.Request.Header.Codes)
+	assert.Nil(t, s.Request.Cookie.Pref)
+}
+
+func TestMultiValue_Errors(t *testing.T) {
+	var bindErr error
+	injectMultiValue(t, map[string]string{
+		"X-Forwarded-For": "10.0.0.1, nope",
+		"X-Ids":           "1,2,x",
+	}, []http.Cookie{
+		{Name: "pref", Value: "dark"},
+		{Name: "pref", Value: "loud"},
+	}, func(c Context) error {
+		_, bindErr = ValidateAndBind[multiValueSchema](c)
+		return nil
+	})
+
+	var verrs ValidationErrors
+	require.True(t, errors.As(bindErr, &verrs))
+	got := map[string]string{}
+	for _, e := range verrs {
+		got[e.Pointer()] = e.Rule()
+	}
+	assert.Equal(t, map[string]string{
+		"/X-Forwarded-For/1": "ip",
+		"/X-Ids/2":           "typeCast",
+		"/pref/1":            "oneof",
+	}, got)
+
+	injectMultiValue(t, map[string]string{"X-Ids": "1,2,3,4"}, nil, func(c Context) error {
+		_, bindErr = ValidateAndBind[multiValueSchema](c)
+		return nil
+	})
+	var verr ValidationError
+	require.True(t, errors.As(bindErr, &verr))
+	assert.Equal(t, "/X-Ids", verr.Pointer())
+	assert.Equal(t, "max", verr.Rule())
+}
+
+func TestMultiValue_ResponseHeaders(t *testing.T) {
+	res := injectMultiValue(t, nil, nil, func(c Context) error {
+		var s multiValueSchema
+		s.Ok.Header.Vary = []string{"Accept", "Origin"}
+		s.Ok.Header.Links = []int{1, 2}
+		return c.Send(200, s.Ok)
+	})
+	require.Equal(t, 200, res.StatusCode)
+	assert.Equal(t, []string{"Accept", "Origin"}, res.HeaderMap.Values("Vary"))
+	assert.Equal(t, []string{"1", "2"}, res.HeaderMap.Values("X-Links"))
+
+	res = injectMultiValue(t, nil, nil, func(c Context) error {
+		var s multiValueSchema
+		s.Ok.Header.Vary = []string{"Accept", "X-1"}
+		return c.Send(200, s.Ok)
+	})
+	assert.Equal(t, 500, res.StatusCode)
+
+	res = injectMultiValue(t, nil, nil, func(c Context) error {
+		return c.Send(200, multiValueSchema{}.Ok)
+	})
+	assert.Equal(t, 500, res.StatusCode)
+
+	res = injectMultiValue(t, nil, nil, func(c Context) error {
+		c.Writer().Header().Set("Vary", "Cookie")
+		return c.Send(200, multiValueSchema{}.Ok)
+	})
+	assert.Equal(t, 200, res.StatusCode)
+	assert.Equal(t, []string{"Cookie"}, res.HeaderMap.Values("Vary"))
+}
+
+func TestMultiValue_Docs(t *testing.T) {
+	r := NewRouter()
+	r.Get("/multi", RouteOptions{Schema: &multiValueSchema{}, Handler: func(c Context) error { return nil }})
+
+	doc := OpenAPISpec(r, DocsOptions{})
+	params := map[string]openapiParameter{}
+	for _, p := range (*doc.Paths)["/multi"]["get"].Parameters {
+		params[p.In+":"+p.Name] = p
+	}
+	assert.Equal(t, "array", params["header:accept"].Schema.Type)
+	require.NotNil(t, params["header:x-forwarded-for"].Schema.Items)
+	assert.Equal(t, "string", params["header:x-forwarded-for"].Schema.Items.Type)
+	assert.Equal(t, "array", params["cookie:pref"].Schema.Type)
+	assert.Equal(t, "string", params["cookie:pref"].Schema.Items.Type)
+}
+
+func TestSplitHeaderList(t *testing.T) {
+	cases := map[string][]string{
+		"":                     nil,
+		" , ,":                 nil,
+		"a":                    {"a"},
+		"a, b,c ,\td":          {"a", "b", "c", "d"},
+		`W/"x,y", "z"`:         {`W/"x,y"`, `"z"`},
+		`a;q="1,\"2", b`:       {`a;q="1,\"2"`, "b"},
+		`text/html;level=1,  `: {"text/html;level=1"},
+	}
+	for in, want := range cases {
+		assert.Equal(t, want, splitHeaderList(nil, in), in)
+	}
+}
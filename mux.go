@@ -55,8 +55,18 @@ type serveMux struct {
 	ctxPool           *sync.Pool
 	maxParams         uint8
 
-	activeServer *FasthttpServer
-	serverMu     sync.Mutex
+	// server is shared by pointer so routers derived with With can be copied by value
+	// and still observe the server started by Listen.
+	server *muxServer
+	// reg collects the errors of the routes registered on this router and the routers
+	// derived from it.
+	reg *registry
+}
+
+// muxServer tracks the server started by Listen so Shutdown can stop it.
+type muxServer struct {
+	mu     sync.Mutex
+	active *FasthttpServer
 }
 
 func NewRouter() Router {
@@ -332,12 +342,29 @@ func (s *serveMux) Meta() RouterMeta {
 func (s *serveMux) UseErrorHandler(handler func(err error, c Context)) {
 	if handler != nil {
 		s.opts.errHandler = handler
+		s.opts.problemDocs = false
 	}
 }
 
+func (s *serveMux) UseProblemDetails() {
+	s.opts.errHandler = ProblemErrorHandler
+	s.opts.problemDocs = true
+}
+
+func (s *serveMux) RegisterMessages(lang string, messages map[string]string) {
+	s.opts.messages.register(lang, messages)
+}
+
+func (s *serveMux) UseLanguageResolver(fn func(c Context) string) {
+	s.opts.langResolver = fn
+}
+
 func (s *serveMux) RegisterSpec(list ...CustomSpec) {
 	for _, v := range list {
 		s.opts.customSpecs[v.SpecID()] = v
+		if t, ok := v.(typedCustomSpec); ok {
+			s.opts.typeSpecs[t.specType()] = v.SpecID()
+		}
 	}
 }
 
@@ -376,6 +403,10 @@ func (s *serveMux) Static(prefix, root string) {
 
 // Listen starts the server on the given address using fasthttp.
 func (s *serveMux) Listen(addr string) error {
+	if err := s.Build(); err != nil {
+		return err
+	}
+
 	srv := &FasthttpServer{
 		mux: s,
 		server: &fasthttp.Server{
@@ -384,18 +415,23 @@ func (s *serveMux) Listen(addr string) error {
 			DisableKeepalive:   false,
 			ReduceMemoryUsage:  false,
 			MaxRequestBodySize: s.opts.bodyLimit,
+			StreamRequestBody:  s.opts.streamReqBody,
 		},
 	}
 
-	s.serverMu.Lock()
-	s.activeServer = srv
-	s.serverMu.Unlock()
+	s.server.mu.Lock()
+	s.server.active = srv
+	s.server.mu.Unlock()
 
 	return srv.Listen(listenAddr(addr))
 }
 
 // ListenTLS starts an HTTPS server on the given address.
 func (s *serveMux) ListenTLS(addr, certFile, keyFile string) error {
+	if err := s.Build(); err != nil {
+		return err
+	}
+
 	srv := &FasthttpServer{
 		mux: s,
 		server: &fasthttp.Server{
@@ -404,18 +440,23 @@ func (s *serveMux) ListenTLS(addr, certFile, keyFile string) error {
 			DisableKeepalive:   false,
 			ReduceMemoryUsage:  false,
 			MaxRequestBodySize: s.opts.bodyLimit,
+			StreamRequestBody:  s.opts.streamReqBody,
 		},
 	}
 
-	s.serverMu.Lock()
-	s.activeServer = srv
-	s.serverMu.Unlock()
+	s.server.mu.Lock()
+	s.server.active = srv
+	s.server.mu.Unlock()
 
 	return srv.ListenTLS(addr, certFile, keyFile)
 }
 
 // ListenTLSMutual starts an HTTPS server providing mutual TLS (mTLS) authentication.
 func (s *serveMux) ListenTLSMutual(addr, certFile, keyFile, clientCertFile string) error {
+	if err := s.Build(); err != nil {
+		return err
+	}
+
 	srv := &FasthttpServer{
 		mux: s,
 		server: &fasthttp.Server{
@@ -424,32 +465,33 @@ func (s *serveMux) ListenTLSMutual(addr, certFile, keyFile, clientCertFile strin
 			DisableKeepalive:   false,
 			ReduceMemoryUsage:  false,
 			MaxRequestBodySize: s.opts.bodyLimit,
+			StreamRequestBody:  s.opts.streamReqBody,
 		},
 	}
 
-	s.serverMu.Lock()
-	s.activeServer = srv
-	s.serverMu.Unlock()
+	s.server.mu.Lock()
+	s.server.active = srv
+	s.server.mu.Unlock()
 
 	return srv.ListenTLSMutual(addr, certFile, keyFile, clientCertFile)
 }
 
 // Shutdown gracefully shuts down the server.
 func (s *serveMux) Shutdown() error {
-	s.serverMu.Lock()
-	defer s.serverMu.Unlock()
-	if s.activeServer != nil {
-		return s.activeServer.Shutdown()
+	s.server.mu.Lock()
+	defer s.server.mu.Unlock()
+	if s.server.active != nil {
+		return s.server.active.Shutdown()
 	}
 	return nil
 }
 
 // ShutdownWithContext gracefully shuts down the server with a timeout context.
 func (s *serveMux) ShutdownWithContext(ctx stdcontext.Context) error {
-	s.serverMu.Lock()
-	defer s.serverMu.Unlock()
-	if s.activeServer != nil {
-		return s.activeServer.ShutdownWithContext(ctx)
+	s.server.mu.Lock()
+	defer s.server.mu.Unlock()
+	if s.server.active != nil {
+		return s.server.active.ShutdownWithContext(ctx)
 	}
 	return nil
 }
@@ -467,6 +509,30 @@ func (s *serveMux) RegisterValidator(list ...Validator) {
 	}
 }
 
+func (s *serveMux) RegisterAsyncValidator(list ...AsyncValidator) {
+	for _, v := range list {
+		s.opts.asyncValidators[v.Name()] = v.Rule
+		// Rules are resolved when routes are compiled, so routes added earlier never run it.
+		s.reg.registeredLate(v.Name(), fmt.Errorf("async validator '%s' was registered after this route", v.Name()))
+	}
+}
+
+func (s *serveMux) RegisterModifier(list ...Modifier) {
+	for _, m := range list {
+		s.opts.modifiers[m.Name()] = m.Modify
+	}
+}
+
+func (s *serveMux) RegisterDefault(name string, fn DefaultFunc) {
+	s.opts.defaults[name] = fn
+}
+
+func (s *serveMux) RegisterAlias(name string, rules string) {
+	if err := s.opts.aliases.Add(name, rules); err != nil {
+		s.reg.failSetting(err)
+	}
+}
+
 type TestOptions struct {
 	Path    string
 	Method  string
@@ -648,7 +714,11 @@ func (s *serveMux) Inject(opts InjectOptions) (resp *InjectResponse, err error)
 
 	// Compile schema if present
 	if def.Schema != nil {
-		rules := s.compileSchema(def.Schema, def.Info)
+		n := s.reg.begin(opts.Method, opts.Path)
+		rules := s.compileStrictSchema(def.Schema, def.Info, def.Strict)
+		if err := s.reg.since(n); err != nil {
+			return nil, err
+		}
 		rules.specs.normalize(opts.Method, opts.Path)
 		s.opts.schemaRules.SetRules(opts.Path, opts.Method, &rules.rules)
 	}
@@ -718,8 +788,9 @@ func (s *serveMux) method(method string, path string, opts RouteOptions) {
 		path = joinPath(s.prefix, path)
 	}
 
+	s.reg.begin(method, path)
 	if opts.Schema != nil {
-		comps := s.compileSchema(opts.Schema, opts.Info)
+		comps := s.compileStrictSchema(opts.Schema, opts.Info, opts.Strict)
 		comps.specs.normalize(method, path)
 
 		if len(s.paths[path]) == 0 {
@@ -772,7 +843,9 @@ func (s *serveMux) method(method string, path string, opts RouteOptions) {
 		data.rules = rules
 	}
 
-	root.addRoute(path, data)
+	if err := addRoute(root, path, data); err != nil {
+		s.reg.fail("", err)
+	}
 
 	// Update maxParams
 	if root.maxParams > s.maxParams {
@@ -799,6 +872,18 @@ func (s *serveMux) Configure(config Config) {
 	if config.MethodNotAllowed != nil {
 		s.opts.methodNotAllowed = *config.MethodNotAllowed
 	}
+	if config.StreamRequestBody {
+		s.opts.streamReqBody = true
+	}
+	if config.AsyncValidationWorkers > 0 {
+		s.opts.asyncWorkers = config.AsyncValidationWorkers
+	}
+	if config.Strict != (Strict{}) {
+		s.opts.strict = config.Strict
+	}
+	if config.VerifyGenerated {
+		s.opts.verifyGenerated = true
+	}
 }
 
 func serveRouterBuilder(trees map[string]*node, paths docsPaths, rm metaMap, globalStore GofiStore, m Middlewares, opts *muxOptions) *serveMux {
@@ -811,6 +896,8 @@ func serveRouterBuilder(trees map[string]*node, paths docsPaths, rm metaMap, glo
 		inlineMiddlewares: make(Middlewares, 0),
 		opts:              opts,
 		rOpts:             nil,
+		server:            &muxServer{},
+		reg:               &registry{},
 		ctxPool: &sync.Pool{
 			New: func() interface{} {
 				return &context{
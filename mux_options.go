@@ -2,32 +2,53 @@ package gofi
 
 import (
 	"fmt"
+	"reflect"
 	"strings"
 
 	"github.com/michaelolof/gofi/cont"
+	"github.com/michaelolof/gofi/validators"
 	"github.com/michaelolof/gofi/validators/rules"
 )
 
 type muxOptions struct {
 	errHandler       func(err error, c Context)
 	customValidators rules.ContextValidators
+	asyncValidators  map[string]func(c ValidatorContext) AsyncValidatorFn
+	asyncWorkers     int // AsyncValidationWorkers
+	modifiers        map[string]modifierFn
+	defaults         map[string]DefaultFunc
+	aliases          validators.Aliases
 	customSpecs      CustomSpecs
+	typeSpecs        map[reflect.Type]string // spec IDs of DefineSpec specs by Go type
 	bodyParsers      []BodyParser
 	schemaRules      SchemaRulesMap
 	bodyLimit        int  // MaxRequestBodySize
 	methodNotAllowed bool // respond 405 instead of 404 on method mismatch
+	streamReqBody    bool // StreamRequestBody
+	problemDocs      bool // document problem details on error responses
+	messages         messageCatalogs
+	langResolver     func(c Context) string
+	strict           Strict // Strict
+	verifyGenerated  bool   // VerifyGenerated
 }
 
 func defaultMuxOptions() *muxOptions {
 	bp := make([]BodyParser, 0, 20)
-	bp = append(bp, &JSONBodyParser{}, &FormBodyParser{}, &MultipartBodyParser{})
+	bp = append(bp, &JSONBodyParser{}, &FormBodyParser{}, &MultipartBodyParser{}, &CSVBodyParser{})
 
 	return &muxOptions{
 		errHandler:       defaultErrorHandler,
 		customValidators: make(rules.ContextValidators),
+		asyncValidators:  make(map[string]func(c ValidatorContext) AsyncValidatorFn),
+		asyncWorkers:     defaultAsyncWorkers,
+		modifiers:        make(map[string]modifierFn),
+		defaults:         make(map[string]DefaultFunc),
+		aliases:          make(validators.Aliases),
 		customSpecs:      make(CustomSpecs),
+		typeSpecs:        make(map[reflect.Type]string),
 		bodyParsers:      bp,
 		schemaRules:      make(SchemaRulesMap),
+		messages:         make(messageCatalogs),
 		bodyLimit:        4 * 1024 * 1024, // 4 MB default
 		methodNotAllowed: true,            // enabled by default
 	}
@@ -152,3 +173,100 @@ func (s *specDefinition) Decode(val any) (any, error) {
 	}
 	return s.decode(val)
 }
+
+// TypedSpecDefinition describes a CustomSpec for values of type T. Decode receives the raw text of
+// a header, query, path, cookie, form or JSON value.
+type TypedSpecDefinition[T any] struct {
+	// SpecID defaults to the name of T.
+	SpecID string
+	Type   string
+	Format string
+	Encode func(val T) (string, error)
+	Decode func(val string) (T, error)
+}
+
+// DefineSpec creates a type-safe CustomSpec for T. Once registered with RegisterSpec, every field
+// of type T (or *T) uses it without a spec tag:
+//
+//	var orderID = gofi.DefineSpec(gofi.TypedSpecDefinition[OrderID]{
+//		Format: "order-id",
+//		Encode: func(id OrderID) (string, error) { return id.String(), nil },
+//		Decode: ParseOrderID,
+//	})
+func DefineSpec[T any](spec TypedSpecDefinition[T]) CustomSpec {
+	typ := reflect.TypeFor[T]()
+	if spec.SpecID == "" {
+		spec.SpecID = typ.String()
+	}
+	return &typedSpec[T]{def: spec, typ: typ}
+}
+
+// typedCustomSpec is a CustomSpec bound to a Go type, which fields of that type use by default.
+type typedCustomSpec interface {
+	CustomSpec
+	specType() reflect.Type
+}
+
+type typedSpec[T any] struct {
+	def TypedSpecDefinition[T]
+	typ reflect.Type
+}
+
+func (s *typedSpec[T]) SpecID() string {
+	return s.def.SpecID
+}
+
+func (s *typedSpec[T]) Type() string {
+	if s.def.Type == "" {
+		return "string"
+	}
+	return s.def.Type
+}
+
+func (s *typedSpec[T]) Format() string {
+	return s.def.Format
+}
+
+func (s *typedSpec[T]) specType() reflect.Type {
+	return s.typ
+}
+
+func (s *typedSpec[T]) Encode(val any) (string, error) {
+	if s.def.Encode == nil {
+		return "", fmt.Errorf("encode function not defined for spec '%s'", s.def.SpecID)
+	}
+	switch v := val.(type) {
+	case T:
+		return s.def.Encode(v)
+	case *T:
+		if v == nil {
+			return "", nil
+		}
+		return s.def.Encode(*v)
+	}
+	return "", fmt.Errorf("spec '%s' cannot encode a value of type %T", s.def.SpecID, val)
+}
+
+func (s *typedSpec[T]) Decode(val any) (any, error) {
+	if s.def.Decode == nil {
+		return nil, fmt.Errorf("decode function not defined for spec '%s'", s.def.SpecID)
+	}
+
+	var str string
+	switch v := val.(type) {
+	case T:
+		return v, nil
+	case string:
+		str = v
+	case []byte:
+		str = string(v)
+	default:
+		str = fmt.Sprint(v)
+	}
+
+	decoded, err := s.def.Decode(str)
+	if err != nil {
+		return nil, err
+	}
+	return decoded, nil
+}
@@ -0,0 +1,297 @@
+package gofi
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/michaelolof/gofi/utils"
+)
+
+// Optional is a field that can be left out. Set reports whether it was sent. Rules in its
+// validate tag apply to Value, and only when the field is set. A JSON null leaves an Optional
+// unset; use Nullable to tell null apart.
+type Optional[T any] struct {
+	Value T
+	Set   bool
+}
+
+// Some returns an Optional set to v.
+func Some[T any](v T) Optional[T] {
+	return Optional[T]{Value: v, Set: true}
+}
+
+// Get returns the value and whether it is set.
+func (o Optional[T]) Get() (T, bool) {
+	return o.Value, o.Set
+}
+
+// Or returns the value when it is set, and def otherwise.
+func (o Optional[T]) Or(def T) T {
+	if o.Set {
+		return o.Value
+	}
+	return def
+}
+
+// IsZero reports whether the field is unset, so `json:",omitzero"` leaves it out.
+func (o Optional[T]) IsZero() bool {
+	return !o.Set
+}
+
+func (o Optional[T]) WrappedType() reflect.Type {
+	return reflect.TypeFor[T]()
+}
+
+func (o Optional[T]) Unwrap() (any, bool) {
+	return o.Value, o.Set
+}
+
+func (o Optional[T]) MarshalJSON() ([]byte, error) {
+	if !o.Set {
+		return []byte("null"), nil
+	}
+	return json.Marshal(o.Value)
+}
+
+func (o *Optional[T]) UnmarshalJSON(b []byte) error {
+	*o = Optional[T]{}
+	if bytes.Equal(b, []byte("null")) {
+		return nil
+	}
+	o.Set = true
+	return json.Unmarshal(b, &o.Value)
+}
+
+func (Optional[T]) wrapperFormat() utils.ObjectFormats {
+	return utils.OptionalFormat
+}
+
+// Nullable is a field that can be left out, sent as null or sent with a value. Set reports
+// whether it was sent and Null whether it was null. Rules in its validate tag apply to Value,
+// and only when a value was sent. It is documented as nullable.
+type Nullable[T any] struct {
+	Value T
+	Set   bool
+	Null  bool
+}
+
+// NullableOf returns a Nullable set to v.
+func NullableOf[T any](v T) Nullable[T] {
+	return Nullable[T]{Value: v, Set: true}
+}
+
+// Null returns a Nullable set to null.
+func Null[T any]() Nullable[T] {
+	return Nullable[T]{Set: true, Null: true}
+}
+
+// Get returns the value and whether one was sent. It is false for null.
+func (n Nullable[T]) Get() (T, bool) {
+	return n.Value, n.Set && !n.Null
+}
+
+// IsNull reports whether the field was sent as null.
+func (n Nullable[T]) IsNull() bool {
+	return n.Set && n.Null
+}
+
+// IsZero reports whether the field is unset, so `json:",omitzero"` leaves it out.
+func (n Nullable[T]) IsZero() bool {
+	return !n.Set
+}
+
+func (n Nullable[T]) WrappedType() reflect.Type {
+	return reflect.TypeFor[T]()
+}
+
+func (n Nullable[T]) Unwrap() (any, bool) {
+	return n.Value, n.Set && !n.Null
+}
+
+func (n Nullable[T]) MarshalJSON() ([]byte, error) {
+	if !n.Set || n.Null {
+		return []byte("null"), nil
+	}
+	return json.Marshal(n.Value)
+}
+
+func (n *Nullable[T]) UnmarshalJSON(b []byte) error {
+	*n = Nullable[T]{Set: true}
+	if bytes.Equal(b, []byte("null")) {
+		n.Null = true
+		return nil
+	}
+	return json.Unmarshal(b, &n.Value)
+}
+
+func (Nullable[T]) wrapperFormat() utils.ObjectFormats {
+	return utils.NullableFormat
+}
+
+// wrapperField is implemented by Optional and Nullable. The fields of both start with Value
+// and Set, and Nullable adds Null.
+type wrapperField interface {
+	WrappedType() reflect.Type
+	wrapperFormat() utils.ObjectFormats
+}
+
+var wrapperFieldType = reflect.TypeOf((*wrapperField)(nil)).Elem()
+
+// wrapperOf returns the format and the wrapped type of an Optional or Nullable type.
+func wrapperOf(typ reflect.Type) (utils.ObjectFormats, reflect.Type, bool) {
+	if typ.Kind() != reflect.Struct || !typ.Implements(wrapperFieldType) {
+		return "", nil, false
+	}
+	w := reflect.Zero(typ).Interface().(wrapperField)
+	return w.wrapperFormat(), w.WrappedType(), true
+}
+
+// isWrapper reports whether the field is an Optional or a Nullable. Its item holds the rules of
+// the wrapped value.
+func (r *RuleDef) isWrapper() bool {
+	return r.format == utils.OptionalFormat || r.format == utils.NullableFormat
+}
+
+// wrappedValue returns the value held by an Optional or Nullable, and false when it is unset
+// or null.
+func wrappedValue(w reflect.Value) (reflect.Value, bool) {
+	if !w.Field(1).Bool() || (w.NumField() > 2 && w.Field(2).Bool()) {
+		return reflect.Value{}, false
+	}
+	return w.Field(0), true
+}
+
+// setWrapped marks an Optional or Nullable as sent and returns its Value field. When null is
+// set a Nullable is marked null and an Optional is left unset.
+func setWrapped(w reflect.Value, null bool) reflect.Value {
+	if null {
+		if w.NumField() > 2 {
+			w.Field(1).SetBool(true)
+			w.Field(2).SetBool(true)
+		}
+		return reflect.Value{}
+	}
+	w.Field(1).SetBool(true)
+	return w.Field(0)
+}
+
+// bindWrappedStr binds a query, path or header value to an Optional or Nullable field. An
+// empty value leaves the field unset.
+func (c *context) bindWrappedStr(field schemaField, def *RuleDef, s string, shouldBind bool, reqStruct reflect.Value) error {
+	if s == "" {
+		return nil
+	}
+
+	v, err := c.paramLeaf(field, def.item, s, def.field)
+	if err != nil || !shouldBind || !v.IsValid() {
+		return err
+	}
+	setWrapped(reqStruct.FieldByName(string(field)).FieldByName(def.fieldName), false).Set(v)
+	return nil
+}
+
+// ApplyPatch copies the fields of patch that were sent onto dst, which must point to a
+// struct. Fields are matched by Go name. Optional and Nullable fields are copied when set, and
+// a null Nullable sets the field of dst to its zero value. Pointer fields are copied when not
+// nil, nested structs field by field, and other fields always.
+func ApplyPatch(dst any, patch any) error {
+	dv := reflect.ValueOf(dst)
+	if dv.Kind() != reflect.Pointer || dv.IsNil() || dv.Elem().Kind() != reflect.Struct {
+		return errors.New("patch destination must be a pointer to a struct")
+	}
+	pv := reflect.Indirect(reflect.ValueOf(patch))
+	if pv.Kind() != reflect.Struct {
+		return errors.New("patch must be a struct")
+	}
+	return applyPatch(dv.Elem(), pv, "")
+}
+
+func applyPatch(dst reflect.Value, patch reflect.Value, path string) error {
+	for _, sf := range reflect.VisibleFields(patch.Type()) {
+		if !sf.IsExported() || isPromotedEmbed(sf) {
+			continue
+		}
+		pf, err := patch.FieldByIndexErr(sf.Index)
+		if err != nil {
+			continue // promoted through a nil embedded pointer
+		}
+
+		name := path + sf.Name
+		f, ok := dst.Type().FieldByName(sf.Name)
+		if !ok || !f.IsExported() {
+			return fmt.Errorf("patch field '%s' has no match in %s", name, dst.Type())
+		}
+		df, err := dst.FieldByIndexErr(f.Index)
+		if err != nil {
+			return fmt.Errorf("patch field '%s' is promoted through a nil pointer in %s", name, dst.Type())
+		}
+
+		if _, _, ok := wrapperOf(pf.Type()); ok {
+			if !pf.Field(1).Bool() {
+				continue
+			}
+			v, ok := wrappedValue(pf)
+			if !ok {
+				df.SetZero()
+				continue
+			}
+			pf = v
+		} else if pf.Kind() == reflect.Pointer && pf.IsNil() {
+			continue
+		}
+
+		if err := assignPatch(df, pf, name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func assignPatch(dst reflect.Value, v reflect.Value, name string) error {
+	if v.Type().AssignableTo(dst.Type()) {
+		dst.Set(v)
+		return nil
+	}
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			dst.SetZero()
+			return nil
+		}
+		v = v.Elem()
+	}
+	if dst.Kind() == reflect.Pointer && (v.Type().AssignableTo(dst.Type().Elem()) || v.Kind() == reflect.Struct) {
+		if dst.IsNil() {
+			dst.Set(reflect.New(dst.Type().Elem()))
+		}
+		dst = dst.Elem()
+	}
+
+	switch {
+	case v.Type().AssignableTo(dst.Type()):
+		dst.Set(v)
+	case v.Kind() == reflect.Struct && dst.Kind() == reflect.Struct:
+		return applyPatch(dst, v, name+".")
+	case kindClass(v.Kind()) == kindClass(dst.Kind()) && v.Type().ConvertibleTo(dst.Type()):
+		dst.Set(v.Convert(dst.Type()))
+	default:
+		return fmt.Errorf("cannot apply patch field '%s' of type %s to %s", name, v.Type(), dst.Type())
+	}
+	return nil
+}
+
+// kindClass groups the sized kinds of numbers, so an int patch applies to an int64 field but
+// never to a string.
+func kindClass(k reflect.Kind) reflect.Kind {
+	switch k {
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return reflect.Int
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return reflect.Uint
+	case reflect.Float32:
+		return reflect.Float64
+	}
+	return k
+}
@@ -0,0 +1,287 @@
+package gofi
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type patchAddress struct {
+	City Optional[string] `json:"city" validate:"required"`
+	Zip  Nullable[string] `json:"zip" validate:"len=5"`
+}
+
+type patchUserSchema struct {
+	Request struct {
+		Query struct {
+			Limit Optional[int] `json:"limit" validate:"max=50"`
+		}
+		Body struct {
+			Name     Optional[string]    `json:"name" validate:"required,min=2"`
+			Nickname Nullable[string]    `json:"nickname" validate:"max=5"`
+			Age      Optional[int]       `json:"age" validate:"min=18"`
+			Born     Nullable[time.Time] `json:"born"`
+			Tags     Optional[[]string]  `json:"tags" validate:"dive,alpha"`
+			Address  Optional[patchAddress]
+			Active   bool `json:"active"`
+		}
+	}
+
+	Ok struct {
+		Body struct {
+			Name     Optional[string] `json:"name" validate:"min=2"`
+			Nickname Nullable[string] `json:"nickname"`
+			Age      Optional[int]    `json:"age"`
+		}
+	}
+}
+
+func bindPatch(t *testing.T, query map[string]string, body string) (*patchUserSchema, error) {
+	t.Helper()
+
+	var s *patchUserSchema
+	var bindErr error
+	_, err := NewRouter().Inject(InjectOptions{
+		Path:    "/users",
+		Method:  "PATCH",
+		Query:   query,
+		Headers: map[string]string{"Content-Type": "application/json"},
+		Body:    strings.NewReader(body),
+		Handler: &RouteOptions{
+			Schema: &patchUserSchema{},
+			Handler: func(c Context) error {
+				s, bindErr = ValidateAndBind[patchUserSchema](c)
+				return nil
+			},
+		},
+	})
+	require.Nil(t, err)
+	return s, bindErr
+}
+
+func TestOptional_Bind(t *testing.T) {
+	s, err := bindPatch(t, map[string]string{"limit": "10"}, `{
+		"name": "Ada",
+		"nickname": null,
+		"born": "2000-01-02T00:00:00Z",
+		"tags": ["a", "b"],
+		"Address": {"zip": "12345"}
+	}`)
+	require.NoError(t, err)
+
+	b := s.Request.Body
+	assert.Equal(t, Some("Ada"), b.Name)
+	assert.Equal(t, Null[string](), b.Nickname)
+	assert.True(t, b.Nickname.IsNull())
+	assert.False(t, b.Age.Set)
+	born, ok := b.Born.Get()
+	assert.True(t, ok)
+	assert.Equal(t, 2000, born.Year())
+	assert.Equal(t, Some([]string{"a", "b"}), b.Tags)
+	require.True(t, b.Address.Set)
+	assert.False(t, b.Address.Value.City.Set)
+	assert.Equal(t, NullableOf("12345"), b.Address.Value.Zip)
+	assert.Equal(t, Some(10), s.Request.Query.Limit)
+}
+
+func TestOptional_Absent(t *testing.T) {
+	s, err := bindPatch(t, nil, `{"active": true}`)
+	require.NoError(t, err)
+
+	b := s.Request.Body
+	assert.False(t, b.Name.Set)
+	assert.False(t, b.Nickname.Set)
+	assert.False(t, b.Tags.Set)
+	assert.False(t, b.Address.Set)
+	assert.False(t, s.Request.Query.Limit.Set)
+	assert.Equal(t, 7, b.Age.Or(7))
+}
+
+func TestOptional_RulesApplyWhenSet(t *testing.T) {
+	_, err := bindPatch(t, map[string]string{"limit": "60"}, `{
+		"name": "",
+		"nickname": "toolong",
+		"age": 12,
+		"tags": ["ok", "n0"],
+		"Address": {"city": "", "zip": null}
+	}`)
+
+	var verrs ValidationErrors
+	require.True(t, errors.As(err, &verrs))
+	got := map[string][]string{}
+	for _, e := range verrs {
+		got[e.Pointer()] = append(got[e.Pointer()], e.Rule())
+	}
+	// Errors from the body stop at the first failing field, like other JSON bodies.
+	assert.Equal(t, map[string][]string{"/limit": {"max"}, "/name": {"required", "min"}}, got)
+
+	_, err = bindPatch(t, nil, `{"nickname": "toolong"}`)
+	var verr ValidationError
+	require.True(t, errors.As(err, &verr))
+	assert.Equal(t, "/nickname", verr.Pointer())
+	assert.Equal(t, "max", verr.Rule())
+
+	_, err = bindPatch(t, nil, `{"Address": {"city": ""}}`)
+	require.True(t, errors.As(err, &verr))
+	assert.Equal(t, "/Address/city", verr.Pointer())
+	assert.Equal(t, "required", verr.Rule())
+
+	_, err = bindPatch(t, nil, `{"name": null, "Address": {"zip": null}}`)
+	assert.NoError(t, err)
+}
+
+func TestOptional_Encode(t *testing.T) {
+	send := func(fill func(s *patchUserSchema)) *InjectResponse {
+		res, err := NewRouter().Inject(InjectOptions{
+			Path:   "/users",
+			Method: "GET",
+			Handler: &RouteOptions{
+				Schema: &patchUserSchema{},
+				Handler: func(c Context) error {
+					var s patchUserSchema
+					fill(&s)
+					return c.Send(200, s.Ok)
+				},
+			},
+		})
+		require.Nil(t, err)
+		return res
+	}
+
+	res := send(func(s *patchUserSchema) {
+		s.Ok.Body.Name = Some("Ada")
+		s.Ok.Body.Nickname = Null[string]()
+	})
+	require.Equal(t, 200, res.StatusCode)
+	assert.JSONEq(t, `{"name":"Ada","nickname":null}`, string(res.Body))
+
+	res = send(func(s *patchUserSchema) {
+		s.Ok.Body.Nickname = NullableOf("ada")
+		s.Ok.Body.Age = Some(0)
+	})
+	assert.JSONEq(t, `{"nickname":"ada","age":0}`, string(res.Body))
+
+	res = send(func(s *patchUserSchema) {
+		s.Ok.Body.Name = Some("A")
+	})
+	assert.Equal(t, 500, res.StatusCode)
+}
+
+func TestOptional_Docs(t *testing.T) {
+	r := NewRouter()
+	r.Patch("/users", RouteOptions{Schema: &patchUserSchema{}, Handler: func(c Context) error { return nil }})
+
+	doc := OpenAPISpec(r, DocsOptions{})
+	op := (*doc.Paths)["/users"]["patch"]
+	require.Len(t, op.Parameters, 1)
+	assert.Equal(t, "integer", op.Parameters[0].Schema.Type)
+	assert.Nil(t, op.Parameters[0].Required)
+
+	body := op.RequestBody.Content["*/*"].Schema
+	assert.Empty(t, body.Required)
+	assert.Equal(t, "string", body.Properties["name"].Type)
+	assert.False(t, body.Properties["name"].Nullable)
+	assert.True(t, body.Properties["nickname"].Nullable)
+	assert.Equal(t, "date-time", body.Properties["born"].Format)
+	assert.Equal(t, "array", body.Properties["tags"].Type)
+	assert.Equal(t, "object", body.Properties["Address"].Type)
+	assert.True(t, body.Properties["Address"].Properties["zip"].Nullable)
+}
+
+func TestOptional_JSON(t *testing.T) {
+	var v struct {
+		A Optional[int]    `json:"a"`
+		B Nullable[int]    `json:"b"`
+		C Nullable[int]    `json:"c"`
+		D Optional[string] `json:"d,omitzero"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(`{"a": 1, "b": null}`), &v))
+	assert.Equal(t, Some(1), v.A)
+	assert.Equal(t, Null[int](), v.B)
+	assert.False(t, v.C.Set)
+
+	out, err := json.Marshal(v)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"a":1,"b":null,"c":null}`, string(out))
+}
+
+func TestOptional_ValidateStruct(t *testing.T) {
+	type form struct {
+		Name Optional[string] `json:"name" validate:"required,min=2"`
+		Nick Nullable[string] `json:"nick" validate:"min=2"`
+	}
+
+	assert.NoError(t, ValidateStruct(form{}))
+	assert.NoError(t, ValidateStruct(form{Nick: Null[string]()}))
+
+	err := ValidateStruct(form{Name: Some("A"), Nick: NullableOf("B")})
+	var verrs ValidationErrors
+	require.True(t, errors.As(err, &verrs))
+	var rules []string
+	for _, e := range verrs {
+		rules = append(rules, e.Pointer()+":"+e.Rule())
+	}
+	assert.Equal(t, []string{"/name:min", "/nick:min"}, rules)
+}
+
+func TestApplyPatch(t *testing.T) {
+	type address struct {
+		City string
+		Zip  *string
+	}
+	type user struct {
+		Name     string
+		Nickname *string
+		Age      int64
+		Tags     []string
+		Address  *address
+		Active   bool
+	}
+	type addressPatch struct {
+		City Optional[string]
+		Zip  Nullable[string]
+	}
+	type userPatch struct {
+		Name     Optional[string]
+		Nickname Nullable[string]
+		Age      Optional[int]
+		Tags     *[]string
+		Address  Optional[addressPatch]
+	}
+
+	nick, zip := "ada", "12345"
+	u := user{Name: "Ada", Nickname: &nick, Age: 30, Tags: []string{"a"}, Active: true}
+
+	require.NoError(t, ApplyPatch(&u, userPatch{
+		Nickname: Null[string](),
+		Age:      Some(31),
+		Address:  Some(addressPatch{City: Some("Lagos"), Zip: NullableOf(zip)}),
+	}))
+	assert.Equal(t, "Ada", u.Name)
+	assert.Nil(t, u.Nickname)
+	assert.Equal(t, int64(31), u.Age)
+	assert.Equal(t, []string{"a"}, u.Tags)
+	require.NotNil(t, u.Address)
+	assert.Equal(t, "Lagos", u.Address.City)
+	require.NotNil(t, u.Address.Zip)
+	assert.Equal(t, zip, *u.Address.Zip)
+	assert.True(t, u.Active)
+
+	tags := []string{"b"}
+	require.NoError(t, ApplyPatch(&u, &userPatch{Name: Some("Grace"), Tags: &tags, Address: Some(addressPatch{Zip: Null[string]()})}))
+	assert.Equal(t, "Grace", u.Name)
+	assert.Equal(t, []string{"b"}, u.Tags)
+	assert.Equal(t, "Lagos", u.Address.City)
+	assert.Nil(t, u.Address.Zip)
+
+	err := ApplyPatch(&u, struct{ Email Optional[string] }{Email: Some("x")})
+	assert.EqualError(t, err, "patch field 'Email' has no match in gofi.user")
+	err = ApplyPatch(&u, struct{ Name Optional[int] }{Name: Some(1)})
+	assert.EqualError(t, err, "cannot apply patch field 'Name' of type int to string")
+	assert.Error(t, ApplyPatch(u, userPatch{}))
+}
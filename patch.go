@@ -0,0 +1,550 @@
+package gofi
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"mime"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/michaelolof/gofi/cont"
+)
+
+// patchFormats maps the values of the `patch` tag on a request Body to their media types.
+var patchFormats = map[string]cont.ContentType{
+	"json":  cont.ApplicationJsonPatch,
+	"merge": cont.ApplicationMergePatch,
+}
+
+// patchMedia returns the patch media types listed in the `patch` tag of a request Body.
+func (r *RuleDef) patchMedia() ([]cont.ContentType, error) {
+	tag := r.tags["patch"]
+	if len(tag) == 0 {
+		return nil, nil
+	}
+
+	var media []cont.ContentType
+	for _, v := range strings.Split(tag[0], ",") {
+		ct, ok := patchFormats[strings.TrimSpace(v)]
+		if !ok {
+			return nil, fmt.Errorf("unknown patch format '%s' on request body. expected json or merge", v)
+		}
+		if !slices.Contains(media, ct) {
+			media = append(media, ct)
+		}
+	}
+	return media, nil
+}
+
+// PatchBody applies a JSON Patch (application/json-patch+json, RFC 6902) or a JSON Merge Patch
+// (application/merge-patch+json, RFC 7396) request body to current, and validates the result
+// against the request Body schema. T must be the type of the Body field. current is left as it
+// is and the patched value is returned.
+//
+// Errors in the patch document point into it: a path that does not exist in current is reported
+// at /<op index>/path with the patchPath rule, and a failed test op at /<op index>/value with the
+// patchTest rule. Errors found by validating the result point into the Body, as with ValidateAndBind.
+// When the Body has a `patch` tag, only the media types it lists are accepted.
+func PatchBody[T any](c Context, current T) (*T, error) {
+	ctx, ok := c.(*context)
+	if !ok {
+		return nil, errors.New("unknown context object passed")
+	}
+
+	rtn, err := patchBody(ctx, current)
+	if err != nil {
+		errs := appendValidationErrors(nil, err, RequestErr, schemaBody)
+		ctx.localizeErrors(errs)
+		return nil, errs
+	}
+	return rtn, nil
+}
+
+func patchBody[T any](c *context, current T) (*T, error) {
+	if c.rules() == nil {
+		return nil, newErrReport(RequestErr, schemaReq, "", "required", errors.New("schema not properly registered to route handler"))
+	}
+
+	pdef := c.rules().getReqRules(schemaBody)
+	if pdef == nil || pdef.kind == reflect.Invalid {
+		return nil, newErrReport(RequestErr, schemaBody, "", "required", errors.New("request body schema is not defined"))
+	}
+	if !matchesRuleType(reflect.TypeFor[T](), pdef) {
+		return nil, newErrReport(RequestErr, schemaBody, "", "typeMismatch", errors.New("patched value type does not match the request body schema"))
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(c.Request().Header.Get("Content-Type"))
+	accepted, _ := pdef.patchMedia()
+	if len(accepted) == 0 {
+		accepted = []cont.ContentType{cont.ApplicationJsonPatch, cont.ApplicationMergePatch}
+	}
+	if !slices.Contains(accepted, cont.ContentType(mediaType)) {
+		return nil, newErrReport(RequestErr, schemaBody, "", "typeMismatch", fmt.Errorf("unsupported patch media type '%s'", mediaType))
+	}
+
+	raw, err := readRequestBody(c.fctx, c.bodyLimit())
+	if err != nil {
+		return nil, err
+	}
+	if len(bytes.TrimSpace(raw)) == 0 {
+		return nil, newErrReport(RequestErr, schemaBody, "", "required", errors.New("request body is required"))
+	}
+
+	jp := c.jsonBodyParser()
+	pc := &parserContext{c: c}
+
+	var buf bytes.Buffer
+	cv := reflect.ValueOf(&current).Elem()
+	if err := jp.encodeFieldValue(pc, &buf, cv, stripValidationRules(pdef), nil); err != nil {
+		return nil, newErrReport(RequestErr, schemaBody, "", "encoder", err)
+	}
+	doc, err := decodePatchJSON(buf.Bytes())
+	if err != nil {
+		return nil, newErrReport(RequestErr, schemaBody, "", "encoder", err)
+	}
+
+	if cont.ContentType(mediaType) == cont.ApplicationMergePatch {
+		patch, err := decodePatchJSON(raw)
+		if err != nil {
+			return nil, newErrReport(RequestErr, schemaBody, "", "parser", err)
+		}
+		doc = mergePatch(doc, patch)
+	} else if doc, err = applyJSONPatch(doc, raw); err != nil {
+		return nil, err
+	}
+
+	patched, err := encodePatchJSON(doc)
+	if err != nil {
+		return nil, newErrReport(RequestErr, schemaBody, "", "encoder", err)
+	}
+	node, err := c.getParser().ParseBytes(patched)
+	if err != nil {
+		return nil, newErrReport(RequestErr, schemaBody, "", "parser", err)
+	}
+
+	var rtn T
+	rv := reflect.ValueOf(&rtn).Elem()
+	opts := RequestOptions{
+		ShouldBind:  true,
+		Context:     pc,
+		Body:        &rv,
+		SchemaRules: pdef,
+	}
+	if _, err := jp.walkStruct(node, schemaBody, opts, nil); err != nil {
+		return nil, err
+	}
+	return &rtn, nil
+}
+
+// jsonBodyParser returns the JSON body parser registered on the router, or a default one.
+func (c *context) jsonBodyParser() *JSONBodyParser {
+	if sz, err := c.serverOpts.getSerializer(cont.ApplicationJson); err == nil {
+		if j, ok := sz.(*JSONBodyParser); ok {
+			return j
+		}
+	}
+	return &JSONBodyParser{}
+}
+
+func decodePatchJSON(b []byte) (any, error) {
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.UseNumber()
+
+	var v any
+	if err := d.Decode(&v); err != nil {
+		return nil, err
+	}
+	if d.More() {
+		return nil, errors.New("unexpected data after the JSON document")
+	}
+	return v, nil
+}
+
+func encodePatchJSON(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// mergePatch applies an RFC 7396 merge patch: objects are merged member by member, null
+// removes a member and any other value replaces the target.
+func mergePatch(target any, patch any) any {
+	pm, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+
+	tm, ok := target.(map[string]any)
+	if !ok {
+		tm = make(map[string]any, len(pm))
+	}
+	for k, v := range pm {
+		if v == nil {
+			delete(tm, k)
+		} else {
+			tm[k] = mergePatch(tm[k], v)
+		}
+	}
+	return tm
+}
+
+type patchOp struct {
+	Op    string          `json:"op"`
+	Path  *string         `json:"path"`
+	From  *string         `json:"from"`
+	Value json.RawMessage `json:"value"`
+}
+
+// applyJSONPatch applies the operations of an RFC 6902 patch document to doc in order. It
+// stops at the first operation that fails.
+func applyJSONPatch(doc any, raw []byte) (any, error) {
+	var ops []patchOp
+	if err := json.Unmarshal(raw, &ops); err != nil {
+		return nil, newErrReport(RequestErr, schemaBody, "", "parser", fmt.Errorf("json patch must be an array of operations: %w", err))
+	}
+
+	for i, op := range ops {
+		key := strconv.Itoa(i)
+		if op.Path == nil {
+			return nil, newErrReport(RequestErr, schemaBody, key+".path", "required", errors.New("patch operation has no path"))
+		}
+		path, err := parsePointer(*op.Path)
+		if err != nil {
+			return nil, newErrReport(RequestErr, schemaBody, key+".path", "patchPath", err)
+		}
+
+		var value, from any
+		switch op.Op {
+		case "add", "replace", "test":
+			if op.Value == nil {
+				return nil, newErrReport(RequestErr, schemaBody, key+".value", "required", fmt.Errorf("patch operation '%s' has no value", op.Op))
+			}
+			if value, err = decodePatchJSON(op.Value); err != nil {
+				return nil, newErrReport(RequestErr, schemaBody, key+".value", "parser", err)
+			}
+		case "move", "copy":
+			if op.From == nil {
+				return nil, newErrReport(RequestErr, schemaBody, key+".from", "required", fmt.Errorf("patch operation '%s' has no from", op.Op))
+			}
+			fp, err := parsePointer(*op.From)
+			if err != nil {
+				return nil, newErrReport(RequestErr, schemaBody, key+".from", "patchPath", err)
+			}
+			if from, err = pointerGet(doc, fp); err != nil {
+				return nil, newErrReport(RequestErr, schemaBody, key+".from", "patchPath", err)
+			}
+			if op.Op == "move" {
+				if len(fp) < len(path) && slices.Equal(fp, path[:len(fp)]) {
+					return nil, newErrReport(RequestErr, schemaBody, key+".from", "patchPath", fmt.Errorf("cannot move '%s' into one of its children", *op.From))
+				}
+				if doc, err = pointerRemove(doc, fp); err != nil {
+					return nil, newErrReport(RequestErr, schemaBody, key+".from", "patchPath", err)
+				}
+			} else {
+				from = copyPatchValue(from)
+			}
+		case "remove":
+		default:
+			return nil, newErrReport(RequestErr, schemaBody, key+".op", "oneof", fmt.Errorf("unknown patch operation '%s'", op.Op))
+		}
+
+		switch op.Op {
+		case "add":
+			doc, err = pointerAdd(doc, path, value)
+		case "remove":
+			doc, err = pointerRemove(doc, path)
+		case "replace":
+			doc, err = pointerReplace(doc, path, value)
+		case "move", "copy":
+			doc, err = pointerAdd(doc, path, from)
+		case "test":
+			var cur any
+			if cur, err = pointerGet(doc, path); err == nil && !patchValuesEqual(cur, value) {
+				return nil, newErrReport(RequestErr, schemaBody, key+".value", "patchTest", fmt.Errorf("value at '%s' does not match", *op.Path))
+			}
+		}
+		if err != nil {
+			return nil, newErrReport(RequestErr, schemaBody, key+".path", "patchPath", err)
+		}
+	}
+	return doc, nil
+}
+
+// parsePointer splits an RFC 6901 JSON Pointer into its unescaped reference tokens.
+func parsePointer(p string) ([]string, error) {
+	if p == "" {
+		return nil, nil
+	}
+	if p[0] != '/' {
+		return nil, fmt.Errorf("path '%s' must be empty or start with '/'", p)
+	}
+
+	tokens := strings.Split(p[1:], "/")
+	for i, t := range tokens {
+		tokens[i] = strings.NewReplacer("~1", "/", "~0", "~").Replace(t)
+	}
+	return tokens, nil
+}
+
+// arrayIndex parses a JSON Pointer token as an index into an array of length n. With end set
+// the index may be n, and "-" stands for it.
+func arrayIndex(token string, n int, end bool) (int, error) {
+	if token == "-" && end {
+		return n, nil
+	}
+	i, err := strconv.Atoi(token)
+	if err != nil || i < 0 || (len(token) > 1 && token[0] == '0') || token[0] == '+' {
+		return 0, fmt.Errorf("'%s' is not an array index", token)
+	}
+	if i > n || (i == n && !end) {
+		return 0, fmt.Errorf("index %d is out of range", i)
+	}
+	return i, nil
+}
+
+func pointerGet(doc any, path []string) (any, error) {
+	for _, t := range path {
+		switch v := doc.(type) {
+		case map[string]any:
+			child, ok := v[t]
+			if !ok {
+				return nil, fmt.Errorf("member '%s' does not exist", t)
+			}
+			doc = child
+		case []any:
+			i, err := arrayIndex(t, len(v), false)
+			if err != nil {
+				return nil, err
+			}
+			doc = v[i]
+		default:
+			return nil, fmt.Errorf("cannot reference '%s' in a scalar value", t)
+		}
+	}
+	return doc, nil
+}
+
+// pointerUpdate calls fn with the container that holds the last token of path, and returns
+// doc with that container replaced by the one fn returns.
+func pointerUpdate(doc any, path []string, fn func(parent any, token string) (any, error)) (any, error) {
+	if len(path) == 1 {
+		return fn(doc, path[0])
+	}
+
+	var child any
+	switch v := doc.(type) {
+	case map[string]any:
+		c, ok := v[path[0]]
+		if !ok {
+			return nil, fmt.Errorf("member '%s' does not exist", path[0])
+		}
+		child = c
+	case []any:
+		i, err := arrayIndex(path[0], len(v), false)
+		if err != nil {
+			return nil, err
+		}
+		child = v[i]
+	default:
+		return nil, fmt.Errorf("cannot reference '%s' in a scalar value", path[0])
+	}
+
+	child, err := pointerUpdate(child, path[1:], fn)
+	if err != nil {
+		return nil, err
+	}
+	switch v := doc.(type) {
+	case map[string]any:
+		v[path[0]] = child
+	case []any:
+		i, _ := arrayIndex(path[0], len(v), false)
+		v[i] = child
+	}
+	return doc, nil
+}
+
+func pointerAdd(doc any, path []string, value any) (any, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+	return pointerUpdate(doc, path, func(parent any, t string) (any, error) {
+		switch v := parent.(type) {
+		case map[string]any:
+			v[t] = value
+			return v, nil
+		case []any:
+			i, err := arrayIndex(t, len(v), true)
+			if err != nil {
+				return nil, err
+			}
+			return slices.Insert(v, i, value), nil
+		}
+		return nil, fmt.Errorf("cannot add '%s' to a scalar value", t)
+	})
+}
+
+func pointerRemove(doc any, path []string) (any, error) {
+	if len(path) == 0 {
+		return nil, errors.New("cannot remove the whole document")
+	}
+	return pointerUpdate(doc, path, func(parent any, t string) (any, error) {
+		switch v := parent.(type) {
+		case map[string]any:
+			if _, ok := v[t]; !ok {
+				return nil, fmt.Errorf("member '%s' does not exist", t)
+			}
+			delete(v, t)
+			return v, nil
+		case []any:
+			i, err := arrayIndex(t, len(v), false)
+			if err != nil {
+				return nil, err
+			}
+			return slices.Delete(v, i, i+1), nil
+		}
+		return nil, fmt.Errorf("cannot remove '%s' from a scalar value", t)
+	})
+}
+
+func pointerReplace(doc any, path []string, value any) (any, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+	return pointerUpdate(doc, path, func(parent any, t string) (any, error) {
+		switch v := parent.(type) {
+		case map[string]any:
+			if _, ok := v[t]; !ok {
+				return nil, fmt.Errorf("member '%s' does not exist", t)
+			}
+			v[t] = value
+			return v, nil
+		case []any:
+			i, err := arrayIndex(t, len(v), false)
+			if err != nil {
+				return nil, err
+			}
+			v[i] = value
+			return v, nil
+		}
+		return nil, fmt.Errorf("cannot replace '%s' in a scalar value", t)
+	})
+}
+
+func copyPatchValue(v any) any {
+	switch tv := v.(type) {
+	case map[string]any:
+		m := make(map[string]any, len(tv))
+		for k, e := range tv {
+			m[k] = copyPatchValue(e)
+		}
+		return m
+	case []any:
+		s := make([]any, len(tv))
+		for i, e := range tv {
+			s[i] = copyPatchValue(e)
+		}
+		return s
+	}
+	return v
+}
+
+// patchValuesEqual compares two JSON values as the test operation does: numbers by value,
+// objects regardless of member order.
+func patchValuesEqual(a, b any) bool {
+	switch av := a.(type) {
+	case json.Number:
+		bv, ok := b.(json.Number)
+		if !ok {
+			return false
+		}
+		x, ok1 := new(big.Float).SetString(av.String())
+		y, ok2 := new(big.Float).SetString(bv.String())
+		return ok1 && ok2 && x.Cmp(y) == 0
+	case map[string]any:
+		bv, ok := b.(map[string]any)
+		if !ok || len(av) != len(bv) {
+			return false
+		}
+		for k, e := range av {
+			f, ok := bv[k]
+			if !ok || !patchValuesEqual(e, f) {
+				return false
+			}
+		}
+		return true
+	case []any:
+		bv, ok := b.([]any)
+		if !ok || len(av) != len(bv) {
+			return false
+		}
+		for i := range av {
+			if !patchValuesEqual(av[i], bv[i]) {
+				return false
+			}
+		}
+		return true
+	}
+	return a == b
+}
+
+// patchDocSchemas returns the request body content documented for the patch media types of a
+// route: a JSON Patch is an array of operations and a merge patch is the body with no
+// required members.
+func patchDocSchemas(media []cont.ContentType, body openapiSchema) map[string]openapiMediaObject {
+	content := make(map[string]openapiMediaObject, len(media))
+	for _, ct := range media {
+		switch ct {
+		case cont.ApplicationJsonPatch:
+			content[string(ct)] = openapiMediaObject{Schema: jsonPatchSchema()}
+		case cont.ApplicationMergePatch:
+			content[string(ct)] = openapiMediaObject{Schema: mergePatchSchema(body)}
+		}
+	}
+	return content
+}
+
+func jsonPatchSchema() openapiSchema {
+	str := openapiSchema{Type: "string"}
+	op := openapiSchema{
+		Type: "object",
+		Properties: map[string]openapiSchema{
+			"op":    {Type: "string", Enum: []any{"add", "remove", "replace", "move", "copy", "test"}},
+			"path":  str,
+			"from":  str,
+			"value": {},
+		},
+		Required: []string{"op", "path"},
+	}
+	return openapiSchema{Type: "array", Items: &op, Description: "A JSON Patch document (RFC 6902)."}
+}
+
+func mergePatchSchema(s openapiSchema) openapiSchema {
+	s.Required = nil
+	if len(s.Properties) > 0 {
+		props := make(map[string]openapiSchema, len(s.Properties))
+		for k, p := range s.Properties {
+			props[k] = mergePatchSchema(p)
+		}
+		s.Properties = props
+	}
+	return s
+}
+
+// setPatchMedia records the patch media types of a request Body for the docs.
+func (o *openapiOperationObject) setPatchMedia(def *RuleDef) error {
+	media, err := def.patchMedia()
+	if err != nil {
+		return err
+	}
+	o.patchMedia = media
+	return nil
+}
@@ -0,0 +1,222 @@
+package gofi
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type patchArticle struct {
+	Title  string            `json:"title" validate:"required,min=3"`
+	Status string            `json:"status" validate:"oneof=draft published"`
+	Tags   []string          `json:"tags" validate:"max=3"`
+	Author *patchAuthor      `json:"author"`
+	Meta   map[string]string `json:"meta"`
+	Views  int               `json:"views"`
+}
+
+type patchAuthor struct {
+	Name  string `json:"name" validate:"required"`
+	Email string `json:"email"`
+}
+
+type patchArticleSchema struct {
+	Request struct {
+		Body patchArticle `patch:"json,merge"`
+	}
+}
+
+var currentArticle = patchArticle{
+	Title:  "Hello",
+	Status: "draft",
+	Tags:   []string{"go"},
+	Author: &patchAuthor{Name: "Ada"},
+	Views:  7,
+}
+
+func sendPatch(t *testing.T, contentType string, body string) (*patchArticle, error) {
+	t.Helper()
+
+	var rtn *patchArticle
+	var patchErr error
+	_, err := NewRouter().Inject(InjectOptions{
+		Path:    "/articles/1",
+		Method:  "PATCH",
+		Headers: map[string]string{"Content-Type": contentType},
+		Body:    strings.NewReader(body),
+		Handler: &RouteOptions{
+			Schema: &patchArticleSchema{},
+			Handler: func(c Context) error {
+				rtn, patchErr = PatchBody(c, currentArticle)
+				return nil
+			},
+		},
+	})
+	require.Nil(t, err)
+	return rtn, patchErr
+}
+
+func patchErrOf(t *testing.T, err error) ValidationError {
+	t.Helper()
+
+	var verr ValidationError
+	require.True(t, errors.As(err, &verr), "%v", err)
+	return verr
+}
+
+func TestPatchBody_JSONPatch(t *testing.T) {
+	a, err := sendPatch(t, "application/json-patch+json", `[
+		{"op": "test", "path": "/views", "value": 7.0},
+		{"op": "replace", "path": "/title", "value": "Hello, world"},
+		{"op": "add", "path": "/tags/-", "value": "web"},
+		{"op": "add", "path": "/tags/0", "value": "news"},
+		{"op": "remove", "path": "/tags/1"},
+		{"op": "copy", "from": "/author/name", "path": "/meta"},
+		{"op": "replace", "path": "/meta", "value": {"a~b/c": "x"}},
+		{"op": "move", "from": "/meta/a~0b~1c", "path": "/meta/d"},
+		{"op": "add", "path": "/author/email", "value": "ada@example.com"}
+	]`)
+	require.NoError(t, err)
+
+	assert.Equal(t, "Hello, world", a.Title)
+	assert.Equal(t, "draft", a.Status)
+	assert.Equal(t, []string{"news", "web"}, a.Tags)
+	assert.Equal(t, map[string]string{"d": "x"}, a.Meta)
+	assert.Equal(t, &patchAuthor{Name: "Ada", Email: "ada@example.com"}, a.Author)
+	assert.Equal(t, 7, a.Views)
+
+	// The current value is left as it is.
+	assert.Equal(t, "Hello", currentArticle.Title)
+	assert.Equal(t, []string{"go"}, currentArticle.Tags)
+	assert.Empty(t, currentArticle.Author.Email)
+}
+
+func TestPatchBody_MergePatch(t *testing.T) {
+	a, err := sendPatch(t, "application/merge-patch+json", `{
+		"status": "published",
+		"tags": ["a", "b"],
+		"author": {"email": "ada@example.com"},
+		"meta": {"k": "v"}
+	}`)
+	require.NoError(t, err)
+
+	assert.Equal(t, "Hello", a.Title)
+	assert.Equal(t, "published", a.Status)
+	assert.Equal(t, []string{"a", "b"}, a.Tags)
+	assert.Equal(t, &patchAuthor{Name: "Ada", Email: "ada@example.com"}, a.Author)
+	assert.Equal(t, map[string]string{"k": "v"}, a.Meta)
+
+	a, err = sendPatch(t, "application/merge-patch+json; charset=utf-8", `{"author": null, "tags": null}`)
+	require.NoError(t, err)
+	assert.Nil(t, a.Author)
+	assert.Nil(t, a.Tags)
+	assert.Equal(t, 7, a.Views)
+}
+
+func TestPatchBody_Errors(t *testing.T) {
+	cases := []struct {
+		name        string
+		contentType string
+		body        string
+		pointer     string
+		rule        string
+	}{
+		{"missing member", "application/json-patch+json", `[{"op": "replace", "path": "/title", "value": "abc"}, {"op": "remove", "path": "/nope"}]`, "/1/path", "patchPath"},
+		{"index out of range", "application/json-patch+json", `[{"op": "add", "path": "/tags/5", "value": "x"}]`, "/0/path", "patchPath"},
+		{"bad index", "application/json-patch+json", `[{"op": "replace", "path": "/tags/01", "value": "x"}]`, "/0/path", "patchPath"},
+		{"bad pointer", "application/json-patch+json", `[{"op": "remove", "path": "title"}]`, "/0/path", "patchPath"},
+		{"missing from", "application/json-patch+json", `[{"op": "copy", "from": "/nope", "path": "/title"}]`, "/0/from", "patchPath"},
+		{"move into child", "application/json-patch+json", `[{"op": "move", "from": "/author", "path": "/author/name"}]`, "/0/from", "patchPath"},
+		{"failed test", "application/json-patch+json", `[{"op": "test", "path": "/title", "value": "Bye"}]`, "/0/value", "patchTest"},
+		{"no value", "application/json-patch+json", `[{"op": "add", "path": "/title"}]`, "/0/value", "required"},
+		{"unknown op", "application/json-patch+json", `[{"op": "upsert", "path": "/title"}]`, "/0/op", "oneof"},
+		{"not an array", "application/json-patch+json", `{"op": "remove"}`, "", "parser"},
+		{"invalid result", "application/json-patch+json", `[{"op": "replace", "path": "/status", "value": "gone"}]`, "/status", "oneof"},
+		{"wrong type", "application/json-patch+json", `[{"op": "replace", "path": "/views", "value": "many"}]`, "/views", "parser"},
+		{"merge invalid result", "application/merge-patch+json", `{"author": {"name": ""}}`, "/author/name", "required"},
+		{"merge removes required", "application/merge-patch+json", `{"title": null}`, "/title", "required"},
+		{"unsupported media type", "application/json", `{"title": "abc"}`, "", "typeMismatch"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := sendPatch(t, tc.contentType, tc.body)
+			verr := patchErrOf(t, err)
+			assert.Equal(t, "body", verr.Location())
+			assert.Equal(t, tc.pointer, verr.Pointer())
+			assert.Equal(t, tc.rule, verr.Rule())
+		})
+	}
+}
+
+func TestPatchBody_AcceptedMedia(t *testing.T) {
+	type schema struct {
+		Request struct {
+			Body patchArticle `patch:"merge"`
+		}
+	}
+
+	send := func(contentType string, body string) error {
+		var patchErr error
+		_, err := NewRouter().Inject(InjectOptions{
+			Path:    "/articles/1",
+			Method:  "PATCH",
+			Headers: map[string]string{"Content-Type": contentType},
+			Body:    strings.NewReader(body),
+			Handler: &RouteOptions{
+				Schema: &schema{},
+				Handler: func(c Context) error {
+					_, patchErr = PatchBody(c, currentArticle)
+					return nil
+				},
+			},
+		})
+		require.Nil(t, err)
+		return patchErr
+	}
+
+	assert.Equal(t, "typeMismatch", patchErrOf(t, send("application/json-patch+json", `[]`)).Rule())
+	assert.NoError(t, send("application/merge-patch+json", `{"views": 8}`))
+}
+
+func TestPatchBody_Docs(t *testing.T) {
+	r := NewRouter()
+	r.Patch("/articles/{id}", RouteOptions{Schema: &patchArticleSchema{}, Handler: func(c Context) error { return nil }})
+
+	doc := OpenAPISpec(r, DocsOptions{})
+	content := (*doc.Paths)["/articles/{id}"]["patch"].RequestBody.Content
+	require.Len(t, content, 2)
+
+	ops := content["application/json-patch+json"].Schema
+	assert.Equal(t, "array", ops.Type)
+	require.NotNil(t, ops.Items)
+	assert.Equal(t, []string{"op", "path"}, ops.Items.Required)
+	assert.Len(t, ops.Items.Properties["op"].Enum, 6)
+
+	merge := content["application/merge-patch+json"].Schema
+	assert.Equal(t, "object", merge.Type)
+	assert.Empty(t, merge.Required)
+	assert.Empty(t, merge.Properties["author"].Required)
+	assert.Equal(t, "string", merge.Properties["title"].Type)
+}
+
+func TestPatchPointers(t *testing.T) {
+	tokens, err := parsePointer("/a~1b/~0c/")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a/b", "~c", ""}, tokens)
+
+	tokens, err = parsePointer("")
+	require.NoError(t, err)
+	assert.Empty(t, tokens)
+
+	assert.True(t, patchValuesEqual(
+		map[string]any{"a": []any{json.Number("1"), "x", nil, true}},
+		map[string]any{"a": []any{json.Number("1.0"), "x", nil, true}},
+	))
+	assert.False(t, patchValuesEqual([]any{"x"}, []any{"x", "y"}))
+	assert.False(t, patchValuesEqual(json.Number("1"), "1"))
+}
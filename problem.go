@@ -0,0 +1,235 @@
+package gofi
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/michaelolof/gofi/cont"
+)
+
+// ProblemDetails is an RFC 9457 problem details object. It is what ProblemErrorHandler writes
+// as application/problem+json.
+type ProblemDetails struct {
+	Type     string
+	Title    string
+	Status   int
+	Detail   string
+	Instance string
+	// Errors lists the individual validation failures when the problem is a failed request.
+	Errors []ProblemError
+	// Extensions are written as top-level members next to the standard ones.
+	Extensions map[string]any
+}
+
+// ProblemError is one validation failure in ProblemDetails.Errors.
+type ProblemError struct {
+	// Location is the part of the request that failed: path, query, header, cookie or body.
+	Location string `json:"location"`
+	// Pointer is a JSON Pointer to the failing field within Location, e.g. /items/3/email.
+	Pointer string   `json:"pointer,omitempty"`
+	Field   string   `json:"field,omitempty"`
+	Rule    string   `json:"rule,omitempty"`
+	Args    []string `json:"args,omitempty"`
+	Value   string   `json:"value,omitempty"`
+	Detail  string   `json:"detail"`
+}
+
+func (p ProblemDetails) MarshalJSON() ([]byte, error) {
+	m := make(map[string]any, len(p.Extensions)+6)
+	for k, v := range p.Extensions {
+		m[k] = v
+	}
+
+	m["type"] = p.Type
+	m["title"] = p.Title
+	m["status"] = p.Status
+	if p.Detail != "" {
+		m["detail"] = p.Detail
+	}
+	if p.Instance != "" {
+		m["instance"] = p.Instance
+	}
+	if len(p.Errors) > 0 {
+		m["errors"] = p.Errors
+	}
+	return json.Marshal(m)
+}
+
+// NewProblemDetails converts err into problem details. An *HTTPError supplies the status and
+// any problem fields it sets. Request validation failures become a 400 with one Errors entry
+// per failure. Response validation failures are a server bug, so they become a 500 without
+// exposing the failing response. Other errors become a 500 whose detail is the status text,
+// so internal error messages never reach the client.
+func NewProblemDetails(err error) ProblemDetails {
+	p := ProblemDetails{Status: http.StatusInternalServerError}
+
+	var httpErr *HTTPError
+	isHTTPErr := errors.As(err, &httpErr) && httpErr != nil
+	if isHTTPErr {
+		if httpErr.Code > 0 {
+			p.Status = httpErr.Code
+		}
+		p.Type = httpErr.Type
+		p.Title = httpErr.Title
+		p.Detail = httpErr.Detail
+		if p.Detail == "" {
+			p.Detail = httpErr.Message
+		}
+		p.Instance = httpErr.Instance
+		p.Extensions = httpErr.Extensions
+	}
+
+	reports := validationErrorsOf(err)
+	switch {
+	case len(reports) > 0 && reports[0].Type() == RequestErr:
+		if !isHTTPErr {
+			p.Status = http.StatusBadRequest
+			p.Detail = "the request failed validation"
+		}
+		p.Errors = make([]ProblemError, 0, len(reports))
+		for _, r := range reports {
+			p.Errors = append(p.Errors, ProblemError{
+				Location: r.Location(),
+				Pointer:  r.Pointer(),
+				Field:    r.SchemaValue(),
+				Rule:     r.Rule(),
+				Args:     r.Args(),
+				Value:    r.Value(),
+				Detail:   r.Message(),
+			})
+		}
+	case len(reports) > 0:
+		if !isHTTPErr {
+			p.Detail = "the server produced an invalid response"
+		}
+	case !isHTTPErr:
+		// Plain errors are internal; only the handler's log sees their text.
+		p.Detail = http.StatusText(http.StatusInternalServerError)
+	}
+
+	if p.Type == "" {
+		p.Type = "about:blank"
+	}
+	if p.Title == "" {
+		p.Title = http.StatusText(p.Status)
+	}
+	return p
+}
+
+// ProblemErrorHandler is an error handler that writes errors as RFC 9457 problem details
+// (application/problem+json). Register it with Router.UseProblemDetails so the problem
+// schema is also documented on each route's error responses.
+func ProblemErrorHandler(err error, c Context) {
+	p := NewProblemDetails(err)
+	if p.Status >= 500 {
+		slog.Error("gofi: request failed", "method", c.Method(), "path", c.Path(), "error", err)
+	}
+
+	bs, merr := json.Marshal(p)
+	if merr != nil {
+		// Extensions are caller supplied and may not be encodable; drop them rather than fail.
+		p.Extensions = nil
+		bs, _ = json.Marshal(p)
+	}
+
+	if ctx, ok := c.(*context); ok {
+		ctx.fctx.Response.Header.Set("Content-Type", string(cont.ApplicationProblemJson))
+	}
+	if err := c.SendBytes(p.Status, bs); err != nil {
+		slog.Error("gofi: error handler failed", "error", err)
+	}
+}
+
+// validationErrorsOf flattens the validation errors joined into err, in order.
+func validationErrorsOf(err error) ValidationErrors {
+	var out ValidationErrors
+	var walk func(error)
+	walk = func(e error) {
+		switch v := e.(type) {
+		case nil:
+		case ValidationError:
+			out = append(out, v)
+		case interface{ Unwrap() []error }:
+			for _, ie := range v.Unwrap() {
+				walk(ie)
+			}
+		case interface{ Unwrap() error }:
+			walk(v.Unwrap())
+		}
+	}
+	walk(err)
+	return out
+}
+
+// problemSchema documents ProblemDetails.
+func problemSchema() openapiSchema {
+	str := func(desc string) openapiSchema { return openapiSchema{Type: "string", Description: desc} }
+	return openapiSchema{
+		Type:        "object",
+		Description: "RFC 9457 problem details",
+		Properties: map[string]openapiSchema{
+			"type":     {Type: "string", Format: "uri-reference", Default: "about:blank"},
+			"title":    str("Short summary of the problem type"),
+			"status":   {Type: "integer", Description: "HTTP status code"},
+			"detail":   str("Explanation specific to this occurrence"),
+			"instance": {Type: "string", Format: "uri-reference"},
+			"errors": {
+				Type:        "array",
+				Description: "Validation failures, one per failing field",
+				Items: &openapiSchema{
+					Type: "object",
+					Properties: map[string]openapiSchema{
+						"location": {Type: "string", Enum: []any{"path", "query", "header", "cookie", "body"}},
+						"pointer":  str("JSON Pointer to the failing field within its location"),
+						"field":    str("Name or key path of the failing field"),
+						"rule":     str("Validation rule that failed"),
+						"args":     {Type: "array", Items: &openapiSchema{Type: "string"}, Description: "Rule arguments"},
+						"value":    str("Rendering of the rejected value. Credentials are redacted"),
+						"detail":   str("Failure message"),
+					},
+					Required: []string{"location", "detail"},
+				},
+			},
+		},
+		Required:             []string{"type", "title", "status"},
+		AdditionalProperties: &openapiSchema{},
+	}
+}
+
+// withProblemResponses documents problem details on the error responses of ops. Declared error
+// responses without a body get the problem schema, routes that validate input get a 400, and a
+// default response covers the remaining errors. ops is left untouched.
+func withProblemResponses(ops map[string]openapiOperationObject) map[string]openapiOperationObject {
+	content := map[string]openapiMediaObject{
+		string(cont.ApplicationProblemJson): {Schema: problemSchema()},
+	}
+
+	out := make(map[string]openapiOperationObject, len(ops))
+	for method, op := range ops {
+		responses := make(map[string]openapiResponseObject, len(op.Responses)+2)
+		has400, has5xx := false, false
+		for code, resp := range op.Responses {
+			isErr := code == "default" || strings.HasPrefix(code, "4") || strings.HasPrefix(code, "5")
+			if isErr && len(resp.Content) == 0 {
+				resp.Content = content
+			}
+			has400 = has400 || code == "400" || code == "4XX"
+			has5xx = has5xx || strings.HasPrefix(code, "5") || code == "default"
+			responses[code] = resp
+		}
+
+		if !has400 && (len(op.Parameters) > 0 || op.RequestBody != nil) {
+			responses["400"] = openapiResponseObject{Description: statuses["BadRequest"][0].Description, Content: content}
+		}
+		if !has5xx {
+			responses["default"] = openapiResponseObject{Description: "Error Response", Content: content}
+		}
+
+		op.Responses = responses
+		out[method] = op
+	}
+	return out
+}
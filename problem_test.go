@@ -0,0 +1,136 @@
+package gofi
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type problemSchemaFixture struct {
+	Request struct {
+		Query struct {
+			Page int `json:"page" validate:"min=1"`
+		}
+		Body struct {
+			Name string `json:"name" validate:"required"`
+		}
+	}
+	Ok struct {
+		Body struct {
+			Name string `json:"name"`
+		}
+	}
+	NotFound struct {
+		Header struct {
+			Retry string `json:"retry-after"`
+		}
+	}
+}
+
+func TestProblemErrorHandler_HTTPError(t *testing.T) {
+	r := NewRouter()
+	r.UseProblemDetails()
+
+	res, err := r.Inject(InjectOptions{
+		Path:   "/orders/7",
+		Method: "GET",
+		Handler: &RouteOptions{
+			Handler: func(c Context) error {
+				return &HTTPError{
+					Code:       409,
+					Message:    "order already shipped",
+					Type:       "https://example.com/problems/shipped",
+					Instance:   "/orders/7",
+					Extensions: map[string]any{"orderId": 7},
+				}
+			},
+		},
+	})
+	require.Nil(t, err)
+	assert.Equal(t, 409, res.StatusCode)
+	assert.Equal(t, "application/problem+json", res.Header("Content-Type"))
+	assert.JSONEq(t, `{
+		"type": "https://example.com/problems/shipped",
+		"title": "Conflict",
+		"status": 409,
+		"detail": "order already shipped",
+		"instance": "/orders/7",
+		"orderId": 7
+	}`, string(res.Body))
+}
+
+func TestProblemErrorHandler_ValidationErrors(t *testing.T) {
+	r := NewRouter()
+	r.UseProblemDetails()
+
+	res, err := r.Inject(InjectOptions{
+		Path:    "/items",
+		Method:  "POST",
+		Headers: map[string]string{"Content-Type": "application/json"},
+		Body:    strings.NewReader(`{}`),
+		Handler: &RouteOptions{
+			Schema: &problemSchemaFixture{},
+			Handler: func(c Context) error {
+				_, err := ValidateAndBind[problemSchemaFixture](c, Body)
+				return err
+			},
+		},
+	})
+	require.Nil(t, err)
+	assert.Equal(t, 400, res.StatusCode)
+
+	var body map[string]any
+	require.NoError(t, json.Unmarshal(res.Body, &body))
+	assert.Equal(t, "about:blank", body["type"])
+	assert.Equal(t, "Bad Request", body["title"])
+	require.Len(t, body["errors"], 1)
+	first := body["errors"].([]any)[0].(map[string]any)
+	assert.Equal(t, "body", first["location"])
+	assert.Equal(t, "name", first["field"])
+	assert.Equal(t, "required", first["rule"])
+}
+
+func TestProblemErrorHandler_PlainErrors(t *testing.T) {
+	p := NewProblemDetails(errors.New("boom"))
+	assert.Equal(t, 500, p.Status)
+	assert.Equal(t, "Internal Server Error", p.Title)
+	assert.Equal(t, "Internal Server Error", p.Detail)
+
+	p = NewProblemDetails(newErrReport(ResponseErr, schemaBody, "secret", "required", errors.New("missing")))
+	assert.Equal(t, 500, p.Status)
+	assert.Empty(t, p.Errors)
+	assert.NotContains(t, p.Detail, "secret")
+}
+
+func TestProblemErrorHandler_DocumentsErrorResponses(t *testing.T) {
+	r := NewRouter()
+	r.UseProblemDetails()
+	r.Post("/items", RouteOptions{Schema: &problemSchemaFixture{}, Handler: func(c Context) error { return nil }})
+
+	doc := OpenAPISpec(r, DocsOptions{})
+	op := (*doc.Paths)["/items"]["post"]
+
+	for _, code := range []string{"400", "404", "default"} {
+		resp, ok := op.Responses[code]
+		require.True(t, ok, code)
+		media, ok := resp.Content["application/problem+json"]
+		require.True(t, ok, code)
+		assert.Contains(t, media.Schema.Properties, "errors")
+	}
+	_, ok := op.Responses["200"].Content["application/problem+json"]
+	assert.False(t, ok)
+
+	// The registered route keeps its own responses.
+	_, ok = r.(*serveMux).paths["/items"]["post"].Responses["400"]
+	assert.False(t, ok)
+
+	// Replacing the handler stops documenting problem details.
+	r.UseErrorHandler(func(err error, c Context) {})
+	doc = OpenAPISpec(r, DocsOptions{})
+	_, ok = (*doc.Paths)["/items"]["post"].Responses["400"]
+	assert.False(t, ok)
+}
@@ -0,0 +1,433 @@
+package gofi
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/valyala/fasthttp"
+)
+
+// Styles of slice, map and struct query parameters, as defined by OpenAPI.
+const (
+	styleForm           = "form"           // ?tag=a&tag=b, or ?tag=a,b without explode
+	styleSpaceDelimited = "spaceDelimited" // ?tag=a%20b
+	stylePipeDelimited  = "pipeDelimited"  // ?tag=a|b
+	styleDeepObject     = "deepObject"     // ?filter[status]=open&filter[owner]=me
+)
+
+// isStructured reports whether a query parameter holds several values: a slice, a map or a
+// struct without a spec of its own.
+func (r *RuleDef) isStructured() bool {
+	if r.format != "" {
+		return false
+	}
+	switch r.kind {
+	case reflect.Slice, reflect.Map, reflect.Struct:
+		return true
+	}
+	return false
+}
+
+// setQueryStyle resolves the style and explode tags of a query parameter. Slices default to
+// form with explode, maps and structs to deepObject.
+func (r *RuleDef) setQueryStyle() error {
+	var style, explodeTag string
+	if v := r.tags["style"]; len(v) > 0 {
+		style = v[0]
+	}
+	if v := r.tags["explode"]; len(v) > 0 {
+		explodeTag = v[0]
+	}
+
+	if !r.isStructured() {
+		if style != "" {
+			return fmt.Errorf("style '%s' on query parameter '%s' needs a slice, map or struct", style, r.field)
+		}
+		return nil
+	}
+
+	object := r.kind != reflect.Slice
+	if style == "" {
+		style = styleForm
+		if object {
+			style = styleDeepObject
+		}
+	}
+
+	r.explode = style == styleForm || style == styleDeepObject
+	if explodeTag != "" {
+		explode, err := strconv.ParseBool(explodeTag)
+		if err != nil {
+			return fmt.Errorf("invalid explode tag '%s' on query parameter '%s'", explodeTag, r.field)
+		}
+		r.explode = explode
+	}
+
+	var err error
+	switch style {
+	case styleForm:
+		if object && r.explode && r.kind == reflect.Map {
+			err = fmt.Errorf("query parameter '%s' is a map: use style deepObject or explode=false", r.field)
+		}
+	case styleSpaceDelimited, stylePipeDelimited:
+		if object && r.explode {
+			err = fmt.Errorf("style %s on query parameter '%s' needs explode=false for a map or struct", style, r.field)
+		}
+	case styleDeepObject:
+		if !object || !r.explode {
+			err = fmt.Errorf("style deepObject on query parameter '%s' needs a map or struct with explode", r.field)
+		}
+	default:
+		err = fmt.Errorf("unknown style '%s' on query parameter '%s'", style, r.field)
+	}
+	r.style = style
+	return err
+}
+
+// querySeparator is the delimiter of a query parameter without explode.
+func (r *RuleDef) querySeparator() string {
+	switch r.style {
+	case styleSpaceDelimited:
+		return " "
+	case stylePipeDelimited:
+		return "|"
+	}
+	return ","
+}
+
+// paramTree holds the values of a parameter by path, e.g. the values of the query parameter
+// filter[owner][name] under owner, name.
+type paramTree struct {
+	vals     []string
+	children map[string]*paramTree
+}
+
+func (n *paramTree) child(key string) *paramTree {
+	if key == "" { // tags[]=a is the same as tags=a
+		return n
+	}
+	if n.children == nil {
+		n.children = make(map[string]*paramTree)
+	}
+	c, ok := n.children[key]
+	if !ok {
+		c = &paramTree{}
+		n.children[key] = c
+	}
+	return c
+}
+
+// bracketPath splits a key such as filter[owner][name] into [owner name] when it is a key of
+// the parameter name.
+func bracketPath(key, name string) ([]string, bool) {
+	rest, ok := strings.CutPrefix(key, name)
+	if !ok || rest == "" || rest[0] != '[' {
+		return nil, false
+	}
+
+	var segs []string
+	for rest != "" {
+		end := strings.IndexByte(rest, ']')
+		if rest[0] != '[' || end < 0 {
+			return nil, false
+		}
+		segs = append(segs, rest[1:end])
+		rest = rest[end+1:]
+	}
+	return segs, true
+}
+
+func splitQueryValue(s, sep string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, sep)
+}
+
+// queryTreeOf collects the values of a slice, map or struct query parameter in its style. It
+// returns nil when the parameter is absent.
+func queryTreeOf(args *fasthttp.Args, def *RuleDef) (*paramTree, error) {
+	switch {
+	case def.style == styleDeepObject:
+		var root *paramTree
+		args.VisitAll(func(k, v []byte) {
+			segs, ok := bracketPath(string(k), def.field)
+			if !ok {
+				return
+			}
+			if root == nil {
+				root = &paramTree{}
+			}
+			n := root
+			for _, s := range segs {
+				n = n.child(s)
+			}
+			n.vals = append(n.vals, string(v))
+		})
+		return root, nil
+
+	case def.kind == reflect.Slice && def.explode:
+		vals := args.PeekMulti(def.field)
+		if len(vals) == 0 {
+			return nil, nil
+		}
+		n := &paramTree{vals: make([]string, 0, len(vals))}
+		for _, v := range vals {
+			n.vals = append(n.vals, string(v))
+		}
+		return n, nil
+
+	case def.kind == reflect.Slice:
+		if !args.Has(def.field) {
+			return nil, nil
+		}
+		return &paramTree{vals: splitQueryValue(string(args.Peek(def.field)), def.querySeparator())}, nil
+
+	case def.explode:
+		// Exploded form structs read each field from its own parameter.
+		var root *paramTree
+		for _, p := range def.orderedProps {
+			vals := args.PeekMulti(p.field)
+			if len(vals) == 0 {
+				continue
+			}
+			if root == nil {
+				root = &paramTree{}
+			}
+			n := root.child(p.field)
+			for _, v := range vals {
+				n.vals = append(n.vals, string(v))
+			}
+		}
+		return root, nil
+
+	default:
+		// Objects without explode alternate names and values: ?color=R,100,G,200
+		if !args.Has(def.field) {
+			return nil, nil
+		}
+		parts := splitQueryValue(string(args.Peek(def.field)), def.querySeparator())
+		if len(parts)%2 != 0 {
+			return nil, newErrReport(RequestErr, schemaQuery, def.field, "typeCast", errors.New("value must be a list of name and value pairs"))
+		}
+		root := &paramTree{}
+		for i := 0; i < len(parts); i += 2 {
+			n := root.child(parts[i])
+			n.vals = append(n.vals, parts[i+1])
+		}
+		return root, nil
+	}
+}
+
+// bindQueryParam validates a slice, map or struct query parameter and binds it when
+// shouldBind is set.
+func (c *context) bindQueryParam(def *RuleDef, shouldBind bool, reqStruct reflect.Value) error {
+	n, err := queryTreeOf(c.fctx.QueryArgs(), def)
+	if err != nil {
+		return err
+	}
+	return c.bindParam(schemaQuery, def, n, shouldBind, reqStruct)
+}
+
+// bindParam validates the values in n against def and binds them to the field of the request
+// part when shouldBind is set. A nil n is an absent parameter.
+func (c *context) bindParam(field schemaField, def *RuleDef, n *paramTree, shouldBind bool, reqStruct reflect.Value) error {
+	if n == nil {
+		if def.required || def.present {
+			return runValidation(nil, RequestErr, field, def.field, def.rules)
+		}
+		return nil
+	}
+
+	v, err := c.paramValue(field, def, n, def.field)
+	if err != nil {
+		return err
+	}
+	if shouldBind && v.IsValid() {
+		reqStruct.FieldByName(string(field)).FieldByName(def.fieldName).Set(v)
+	}
+	return nil
+}
+
+// paramValue builds the value of def from the values in n. The returned value is invalid
+// when there is nothing to bind.
+func (c *context) paramValue(field schemaField, def *RuleDef, n *paramTree, key string) (reflect.Value, error) {
+	if !def.isStructured() {
+		if len(n.vals) == 0 {
+			return reflect.Value{}, nil
+		}
+		return c.paramLeaf(field, def, n.vals[len(n.vals)-1], key)
+	}
+
+	typ := def.typ
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+
+	var v reflect.Value
+	var err error
+	switch def.kind {
+	case reflect.Slice:
+		v, err = c.paramSlice(field, def, typ, n.vals, key)
+	case reflect.Map:
+		v, err = c.paramMap(field, def, typ, n, key)
+	default:
+		v, err = c.paramStruct(field, def, typ, n, key)
+	}
+	if err != nil {
+		return reflect.Value{}, err
+	}
+
+	if err := runValidation(v.Interface(), RequestErr, field, key, def.rules); err != nil {
+		return reflect.Value{}, err
+	}
+	if def.typ.Kind() == reflect.Pointer {
+		ptr := reflect.New(typ)
+		ptr.Elem().Set(v)
+		return ptr, nil
+	}
+	return v, nil
+}
+
+func (c *context) paramSlice(field schemaField, def *RuleDef, typ reflect.Type, vals []string, key string) (reflect.Value, error) {
+	out := reflect.MakeSlice(typ, 0, len(vals))
+	var errs []error
+	for i, s := range vals {
+		v, err := c.paramLeaf(field, def.item, s, key+"."+strconv.Itoa(i))
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if !v.IsValid() {
+			v = reflect.Zero(typ.Elem())
+		}
+		out = reflect.Append(out, v)
+	}
+	return out, errors.Join(errs...)
+}
+
+func (c *context) paramMap(field schemaField, def *RuleDef, typ reflect.Type, n *paramTree, key string) (reflect.Value, error) {
+	keyDef := def.keys
+	if keyDef == nil {
+		keyDef = &RuleDef{typ: typ.Key(), kind: typ.Key().Kind()}
+	}
+
+	out := reflect.MakeMapWithSize(typ, len(n.children))
+	var errs []error
+	for _, k := range slices.Sorted(maps.Keys(n.children)) {
+		path := key + "." + k
+		kv, err := c.paramLeaf(field, keyDef, k, path)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		vv, err := c.paramValue(field, def.additionalProperties, n.children[k], path)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if !kv.IsValid() {
+			kv = reflect.Zero(typ.Key())
+		}
+		if !vv.IsValid() {
+			vv = reflect.Zero(typ.Elem())
+		}
+		out.SetMapIndex(kv, vv)
+	}
+	return out, errors.Join(errs...)
+}
+
+func (c *context) paramStruct(field schemaField, def *RuleDef, typ reflect.Type, n *paramTree, key string) (reflect.Value, error) {
+	out := reflect.New(typ).Elem()
+	var errs []error
+	for _, p := range def.orderedProps {
+		path := key + "." + p.field
+		child, ok := n.children[p.field]
+		if !ok {
+			if p.defStr != "" && !p.isStructured() {
+				child = &paramTree{vals: []string{""}}
+			} else {
+				if p.required || p.present {
+					if err := runValidation(nil, RequestErr, field, path, p.rules); err != nil {
+						errs = append(errs, err)
+					}
+				}
+				continue
+			}
+		}
+
+		v, err := c.paramValue(field, p, child, path)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if v.IsValid() {
+			out.FieldByName(p.fieldName).Set(v)
+		}
+	}
+	return out, errors.Join(errs...)
+}
+
+// paramLeaf converts and validates a single value of a slice, map or struct parameter.
+func (c *context) paramLeaf(field schemaField, def *RuleDef, s string, key string) (reflect.Value, error) {
+	s = def.modify(s)
+	if s == "" {
+		s = def.defaultText()
+	}
+	if s == "" && !def.required && !def.present {
+		return reflect.Value{}, nil
+	}
+
+	val, err := c.parseStrValue(def, s)
+	if err != nil {
+		return reflect.Value{}, newErrReport(RequestErr, field, key, "typeCast", err)
+	}
+	if err := runValidation(val, RequestErr, field, key, def.rules); err != nil {
+		return reflect.Value{}, err
+	}
+	if val == nil {
+		return reflect.Value{}, nil
+	}
+
+	rv := reflect.ValueOf(val)
+	switch {
+	case rv.Type().ConvertibleTo(def.typ):
+		return rv.Convert(def.typ), nil
+	case def.typ.Kind() == reflect.Pointer && rv.Type().ConvertibleTo(def.typ.Elem()):
+		ptr := reflect.New(def.typ.Elem())
+		ptr.Elem().Set(rv.Convert(def.typ.Elem()))
+		return ptr, nil
+	}
+	return reflect.Value{}, newErrReport(RequestErr, field, key, "typeMismatch", fmt.Errorf("cannot convert %s to %s", rv.Type(), def.typ))
+}
+
+// normalizeFormKeys rewrites bracketed form keys such as user[name] or items[0][id] to the
+// dotted keys the form binders read (user.name, items.0.id). A trailing [] is dropped, so
+// tags[]=a&tags[]=b binds like tags=a&tags=b.
+func normalizeFormKeys[V any](values map[string][]V) {
+	for k, v := range values {
+		i := strings.IndexByte(k, '[')
+		if i <= 0 {
+			continue
+		}
+		segs, ok := bracketPath(k, k[:i])
+		if !ok {
+			continue
+		}
+
+		parts := []string{k[:i]}
+		for _, s := range segs {
+			if s != "" {
+				parts = append(parts, s)
+			}
+		}
+		nk := strings.Join(parts, ".")
+		delete(values, k)
+		values[nk] = append(values[nk], v...)
+	}
+}
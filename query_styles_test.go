@@ -0,0 +1,207 @@
+package gofi
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type queryFilter struct {
+	Status string `json:"status" validate:"oneof=open closed"`
+	Owner  struct {
+		Name string `json:"name"`
+	} `json:"owner"`
+	Limit int `json:"limit" default:"20"`
+}
+
+type queryStyleSchema struct {
+	Request struct {
+		Query struct {
+			Tags   []string       `json:"tag" validate:"max=3,dive,alpha"`
+			Ids    []int          `json:"ids" explode:"false"`
+			Codes  []string       `json:"codes" style:"pipeDelimited"`
+			Words  []string       `json:"words" style:"spaceDelimited"`
+			Filter *queryFilter   `json:"filter"`
+			Labels map[string]int `json:"labels"`
+			Color  map[string]int `json:"color" style:"form" explode:"false"`
+			Page   struct {
+				Number int `json:"page"`
+				Size   int `json:"size"`
+			} `json:"paging" style:"form"`
+			Sort string `json:"sort"`
+		}
+	}
+}
+
+func bindQueryStyles(t *testing.T, query string) (*queryStyleSchema, error) {
+	t.Helper()
+
+	var s *queryStyleSchema
+	var bindErr error
+	_, err := NewRouter().Inject(InjectOptions{
+		Path:   "/search?" + query,
+		Method: "GET",
+		Handler: &RouteOptions{
+			Schema: &queryStyleSchema{},
+			Handler: func(c Context) error {
+				s, bindErr = ValidateAndBind[queryStyleSchema](c)
+				return nil
+			},
+		},
+	})
+	require.Nil(t, err)
+	return s, bindErr
+}
+
+func TestQueryStyles(t *testing.T) {
+	s, err := bindQueryStyles(t, strings.Join([]string{
+		"tag=go&tag=web",
+		"ids=1,2,3",
+		"codes=a|b",
+		"words=hello+world",
+		"filter[status]=open&filter[owner][name]=me",
+		"labels[a]=1&labels[b]=2",
+		"color=R,100,G,200",
+		"page=2&size=50",
+		"sort=name",
+	}, "&"))
+	require.NoError(t, err)
+
+	q := s.Request.Query
+	assert.Equal(t, []string{"go", "web"}, q.Tags)
+	assert.Equal(t, []int{1, 2, 3}, q.Ids)
+	assert.Equal(t, []string{"a", "b"}, q.Codes)
+	assert.Equal(t, []string{"hello", "world"}, q.Words)
+	require.NotNil(t, q.Filter)
+	assert.Equal(t, "open", q.Filter.Status)
+	assert.Equal(t, "me", q.Filter.Owner.Name)
+	assert.Equal(t, 20, q.Filter.Limit)
+	assert.Equal(t, map[string]int{"a": 1, "b": 2}, q.Labels)
+	assert.Equal(t, map[string]int{"R": 100, "G": 200}, q.Color)
+	assert.Equal(t, 2, q.Page.Number)
+	assert.Equal(t, 50, q.Page.Size)
+	assert.Equal(t, "name", q.Sort)
+}
+
+func TestQueryStyles_Absent(t *testing.T) {
+	s, err := bindQueryStyles(t, "sort=name")
+	require.NoError(t, err)
+	assert.Nil(t, s.Request.Query.Tags)
+	assert.Nil(t, s.Request.Query.Filter)
+	assert.Nil(t, s.Request.Query.Labels)
+}
+
+func TestQueryStyles_Errors(t *testing.T) {
+	_, err := bindQueryStyles(t, "tag=go&tag=v2&tag=a&tag=b&ids=1,x&filter[status]=pending&labels[a]=one&color=R")
+
+	var verrs ValidationErrors
+	require.True(t, errors.As(err, &verrs))
+	got := map[string]string{}
+	for _, e := range verrs {
+		got[e.Pointer()] = e.Rule()
+	}
+	assert.Equal(t, map[string]string{
+		"/tag/1":         "alpha",
+		"/ids/1":         "typeCast",
+		"/filter/status": "oneof",
+		"/labels/a":      "typeCast",
+		"/color":         "typeCast",
+	}, got)
+
+	_, err = bindQueryStyles(t, "tag=a&tag=b&tag=c&tag=d")
+	var verr ValidationError
+	require.True(t, errors.As(err, &verr))
+	assert.Equal(t, "/tag", verr.Pointer())
+	assert.Equal(t, "max", verr.Rule())
+}
+
+func TestQueryStyles_Docs(t *testing.T) {
+	r := NewRouter()
+	r.Get("/search", RouteOptions{Schema: &queryStyleSchema{}, Handler: func(c Context) error { return nil }})
+
+	doc := OpenAPISpec(r, DocsOptions{})
+	params := map[string]openapiParameter{}
+	for _, p := range (*doc.Paths)["/search"]["get"].Parameters {
+		params[p.Name] = p
+	}
+
+	for name, want := range map[string]struct {
+		style   string
+		explode bool
+	}{
+		"tag":    {"form", true},
+		"ids":    {"form", false},
+		"codes":  {"pipeDelimited", false},
+		"words":  {"spaceDelimited", false},
+		"filter": {"deepObject", true},
+		"color":  {"form", false},
+		"paging": {"form", true},
+	} {
+		require.NotNil(t, params[name].Explode, name)
+		assert.Equal(t, want.style, params[name].Style, name)
+		assert.Equal(t, want.explode, *params[name].Explode, name)
+	}
+	assert.Empty(t, params["sort"].Style)
+	assert.Nil(t, params["sort"].Explode)
+	assert.Equal(t, "object", params["filter"].Schema.Type)
+}
+
+func TestSetQueryStyle_Invalid(t *testing.T) {
+	cases := map[string]struct {
+		def  RuleDef
+		want string
+	}{
+		"unknown style":    {RuleDef{kind: reflect.Slice, field: "a", tags: map[string][]string{"style": {"matrix"}}}, "unknown style"},
+		"deepObject slice": {RuleDef{kind: reflect.Slice, field: "a", tags: map[string][]string{"style": {"deepObject"}}}, "needs a map or struct"},
+		"exploded map":     {RuleDef{kind: reflect.Map, field: "a", tags: map[string][]string{"style": {"form"}}}, "is a map"},
+		"scalar":           {RuleDef{kind: reflect.String, field: "a", tags: map[string][]string{"style": {"form"}}}, "needs a slice, map or struct"},
+	}
+	for name, tc := range cases {
+		err := tc.def.setQueryStyle()
+		if assert.Error(t, err, name) {
+			assert.Contains(t, err.Error(), tc.want, name)
+		}
+	}
+}
+
+func TestFormBracketKeys(t *testing.T) {
+	type schema struct {
+		Request struct {
+			Body struct {
+				User struct {
+					Name string `json:"name"`
+				} `json:"user"`
+				Tags  []string `json:"tags"`
+				Items []struct {
+					Id int `json:"id"`
+				} `json:"items"`
+			}
+		}
+	}
+
+	var s *schema
+	var bindErr error
+	_, err := NewRouter().Inject(InjectOptions{
+		Path:    "/form",
+		Method:  "POST",
+		Headers: map[string]string{"Content-Type": "application/x-www-form-urlencoded"},
+		Body:    strings.NewReader("user%5Bname%5D=ada&tags%5B%5D=a&tags%5B%5D=b&items%5B0%5D%5Bid%5D=7"),
+		Handler: &RouteOptions{
+			Schema: &schema{},
+			Handler: func(c Context) error {
+				s, bindErr = ValidateAndBind[schema](c)
+				return nil
+			},
+		},
+	})
+	require.Nil(t, err)
+	require.NoError(t, bindErr)
+	assert.Equal(t, "ada", s.Request.Body.User.Name)
+	assert.Equal(t, []string{"a", "b"}, s.Request.Body.Tags)
+	require.Len(t, s.Request.Body.Items, 1)
+	assert.Equal(t, 7, s.Request.Body.Items[0].Id)
+}
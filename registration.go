@@ -0,0 +1,145 @@
+package gofi
+
+import (
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// RouteError is a problem found in a route while it was registered, such as a malformed
+// struct tag in its schema or a path that conflicts with another route. A route with schema
+// errors is still registered and a conflicting one is left out; either way Build returns the
+// error and Listen refuses to start. Rejected router settings, such as an alias that forms a
+// cycle, are reported the same way with no Method or Path.
+type RouteError struct {
+	Method string
+	Path   string
+	// Field is the dotted path of the schema field at fault, e.g. Request.Body.address.city.
+	Field string
+	// Tag is the struct tag at fault, e.g. validate:"oneof=a b".
+	Tag string
+	Err error
+}
+
+func (e *RouteError) Error() string {
+	where := slices.DeleteFunc([]string{e.Method, e.Path, e.Field, e.Tag}, func(s string) bool { return s == "" })
+	if len(where) == 0 {
+		return e.Err.Error()
+	}
+	return strings.Join(where, " ") + ": " + e.Err.Error()
+}
+
+func (e *RouteError) Unwrap() error {
+	return e.Err
+}
+
+// registry collects the route errors of a router and the routers derived from it.
+type registry struct {
+	errs []*RouteError
+	// unknown holds where compiled routes used a rule that named no validator, by rule name.
+	unknown map[string][]*RouteError
+	// method, path and fields locate the route and the schema field being compiled.
+	method string
+	path   string
+	fields []string
+}
+
+// begin starts compiling the route method path and returns the number of errors so far.
+func (g *registry) begin(method, path string) int {
+	g.method, g.path, g.fields = method, path, g.fields[:0]
+	return len(g.errs)
+}
+
+// enter descends into the schema field name and returns the depth to leave back to.
+func (g *registry) enter(name string) int {
+	n := len(g.fields)
+	g.fields = append(g.fields, name)
+	return n
+}
+
+// at replaces the field at depth and below with name.
+func (g *registry) at(depth int, name string) {
+	g.fields = append(g.fields[:depth], name)
+}
+
+func (g *registry) leave(n int) {
+	g.fields = g.fields[:n]
+}
+
+// fail records err for the field being compiled. tag is the struct tag at fault, if any.
+func (g *registry) fail(tag string, err error) {
+	g.errs = append(g.errs, &RouteError{
+		Method: g.method,
+		Path:   g.path,
+		Field:  strings.Join(g.fields, "."),
+		Tag:    tag,
+		Err:    err,
+	})
+}
+
+// failSetting records err for a router setting rather than a route.
+func (g *registry) failSetting(err error) {
+	g.errs = append(g.errs, &RouteError{Err: err})
+}
+
+// unknownRule records that the field being compiled uses rule, which names no validator yet.
+func (g *registry) unknownRule(rule, tag string) {
+	if g.unknown == nil {
+		g.unknown = make(map[string][]*RouteError)
+	}
+	g.unknown[rule] = append(g.unknown[rule], &RouteError{
+		Method: g.method,
+		Path:   g.path,
+		Field:  strings.Join(g.fields, "."),
+		Tag:    tag,
+	})
+}
+
+// registeredLate records err for every field compiled before a validator named rule was
+// registered, since those fields never run it.
+func (g *registry) registeredLate(rule string, err error) {
+	for _, e := range g.unknown[rule] {
+		e.Err = err
+		g.errs = append(g.errs, e)
+	}
+	delete(g.unknown, rule)
+}
+
+// since joins the errors recorded after the first n.
+func (g *registry) since(n int) error {
+	errs := make([]error, 0, len(g.errs)-n)
+	for _, err := range g.errs[n:] {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+// tagOf formats the struct tag key with value val as it is written in Go source.
+func tagOf(key, val string) string {
+	return key + ":" + strconv.Quote(val)
+}
+
+// addRoute adds data to the tree of root, returning a conflict with another route as an error.
+func addRoute(root *node, path string, data *routeData) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			msg, ok := r.(string)
+			if !ok {
+				panic(r)
+			}
+			err = errors.New(msg)
+		}
+	}()
+
+	root.addRoute(path, data)
+	return nil
+}
+
+func (s *serveMux) Errors() []*RouteError {
+	return slices.Clone(s.reg.errs)
+}
+
+func (s *serveMux) Build() error {
+	return s.reg.since(0)
+}
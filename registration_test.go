@@ -0,0 +1,109 @@
+package gofi
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type badTagsSchema struct {
+	Request struct {
+		Query struct {
+			Sort string `json:"sort" mod:"shout"`
+		}
+		Body struct {
+			Age     int `json:"age" validate:"oneof=1 two"`
+			Address struct {
+				Zip string `json:"zip" default:"ab" validate:"numeric"`
+			} `json:"address"`
+			Tenant string `json:"tenant" default:"$tenant"`
+		}
+	}
+}
+
+func noopHandler(c Context) error { return nil }
+
+func TestRegistration_SchemaErrors(t *testing.T) {
+	r := NewRouter()
+	require.NotPanics(t, func() {
+		r.Post("/users", RouteOptions{Schema: &badTagsSchema{}, Handler: noopHandler})
+	})
+
+	errs := r.Errors()
+	require.Len(t, errs, 4)
+	fields := map[string]*RouteError{}
+	for _, err := range errs {
+		assert.Equal(t, "POST", err.Method)
+		assert.Equal(t, "/users", err.Path)
+		fields[err.Field] = err
+	}
+
+	require.Contains(t, fields, "Request.Query.sort")
+	assert.Equal(t, `mod:"shout"`, fields["Request.Query.sort"].Tag)
+	require.Contains(t, fields, "Request.Body.age")
+	assert.Equal(t, `validate:"oneof=1 two"`, fields["Request.Body.age"].Tag)
+	assert.Equal(t, `POST /users Request.Body.age validate:"oneof=1 two": option 'two' of oneof is not a number`, fields["Request.Body.age"].Error())
+	require.Contains(t, fields, "Request.Body.address.zip")
+	assert.Equal(t, `default:"ab"`, fields["Request.Body.address.zip"].Tag)
+	var verr ValidationError
+	assert.True(t, errors.As(fields["Request.Body.address.zip"], &verr))
+	require.Contains(t, fields, "Request.Body.tenant")
+	assert.Equal(t, `default:"$tenant"`, fields["Request.Body.tenant"].Tag)
+
+	err := r.Build()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown modifier 'shout'")
+	assert.Equal(t, err, r.Listen(":0"))
+}
+
+func TestRegistration_RouteConflicts(t *testing.T) {
+	r := NewRouter()
+	require.NotPanics(t, func() {
+		r.Get("/users/:id", RouteOptions{Handler: noopHandler})
+		r.Get("/users/:name", RouteOptions{Handler: noopHandler})
+		r.Route("/api", func(r Router) {
+			r.Get("/files", RouteOptions{Handler: noopHandler})
+			r.Get("/files", RouteOptions{Handler: noopHandler})
+		})
+	})
+
+	errs := r.Errors()
+	require.Len(t, errs, 2)
+	assert.Equal(t, "/users/:name", errs[0].Path)
+	assert.Contains(t, errs[0].Error(), "conflicts with existing wildcard ':id'")
+	assert.Equal(t, "/api/files", errs[1].Path)
+	assert.Contains(t, errs[1].Error(), "a route is already registered")
+
+	res, err := r.Test(TestOptions{Method: "GET", Path: "/users/7"})
+	require.NoError(t, err)
+	assert.Equal(t, 200, res.StatusCode)
+}
+
+func TestRegistration_Inject(t *testing.T) {
+	r := NewRouter()
+	_, err := r.Inject(InjectOptions{
+		Method:  "POST",
+		Path:    "/users",
+		Handler: &RouteOptions{Schema: &badTagsSchema{}, Handler: noopHandler},
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "POST /users Request.Body.age")
+}
+
+func TestRegistration_Clean(t *testing.T) {
+	r := NewRouter()
+	r.Post("/users", RouteOptions{Schema: &defaultsSchema{}, Handler: noopHandler})
+	r.RegisterDefault("tenant", func() any { return "acme" })
+	r.Post("/accounts", RouteOptions{Schema: &defaultsSchema{}, Handler: noopHandler})
+
+	require.Len(t, r.Errors(), 1)
+	assert.Equal(t, "/users", r.Errors()[0].Path)
+
+	r = NewRouter()
+	r.RegisterDefault("tenant", func() any { return "acme" })
+	r.Post("/users", RouteOptions{Schema: &defaultsSchema{}, Handler: noopHandler})
+	assert.Empty(t, r.Errors())
+	assert.NoError(t, r.Build())
+}
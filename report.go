@@ -1,12 +1,21 @@
 package gofi
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/michaelolof/gofi/utils"
+)
 
 type errorType string
 
 const (
 	RequestErr  errorType = "Request"
 	ResponseErr errorType = "Response"
+	// StructErr is the type of the failures found by ValidateStruct.
+	StructErr errorType = "Struct"
 )
 
 type errReport struct {
@@ -16,13 +25,25 @@ type errReport struct {
 	field       schemaField
 	schemaValue string
 	rule        string
+	args        []string
+	value       any
+	// keys is the path of schemaValue when it was built from keys that may contain dots.
+	keys []string
+	// localized is the message rendered from the catalog template of the rule, if it has one.
+	localized string
 }
 
 func (e *errReport) Error() string {
+	if e.localized != "" {
+		return e.localized
+	}
 	if e.schemaValue != "" {
-		if e.typ == RequestErr {
+		switch e.typ {
+		case RequestErr:
 			return fmt.Sprintf("%s at request %s(%s)", e.err.Error(), e.field, e.schemaValue)
-		} else {
+		case StructErr:
+			return fmt.Sprintf("%s at %s", e.err.Error(), e.schemaValue)
+		default:
 			return fmt.Sprintf("%s at response %s(%s)", e.err.Error(), e.field, e.schemaValue)
 		}
 	} else {
@@ -50,6 +71,42 @@ func (e *errReport) Rule() string {
 	return e.rule
 }
 
+func (e *errReport) Location() string {
+	return strings.ToLower(string(e.field))
+}
+
+func (e *errReport) Pointer() string {
+	if e.schemaValue == "" {
+		return ""
+	}
+
+	keys := e.keys
+	if keys == nil {
+		keys = strings.Split(e.schemaValue, ".")
+	}
+	var sb strings.Builder
+	for _, key := range keys {
+		sb.WriteByte('/')
+		sb.WriteString(pointerEscaper.Replace(key))
+	}
+	return sb.String()
+}
+
+func (e *errReport) Args() []string {
+	return e.args
+}
+
+func (e *errReport) Value() string {
+	return renderErrValue(e.field, e.schemaValue, e.value)
+}
+
+func (e *errReport) Message() string {
+	if e.localized != "" {
+		return e.localized
+	}
+	return e.err.Error()
+}
+
 func newErrReport(typ errorType, schema schemaField, schemaValue string, rule string, err error) *errReport {
 	return &errReport{
 		typ:         typ,
@@ -61,6 +118,19 @@ func newErrReport(typ errorType, schema schemaField, schemaValue string, rule st
 	}
 }
 
+// newKeysReport is newErrReport for a value found at the path keys. The path is copied, so
+// keys may be reused by the caller.
+func newKeysReport(typ errorType, schema schemaField, keys []string, rule string, err error) *errReport {
+	report := newErrReport(typ, schema, strings.Join(keys, "."), rule, err)
+	if len(keys) > 0 {
+		report.keys = slices.Clone(keys)
+	}
+	return report
+}
+
+// pointerEscaper escapes a JSON Pointer reference token (RFC 6901).
+var pointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
 type ValidationError interface {
 	Type() errorType
 	Error() string
@@ -68,4 +138,92 @@ type ValidationError interface {
 	SchemaType() schemaField
 	SchemaValue() string
 	Rule() string
+
+	// Location is the part of the message that failed: path, query, header, cookie or body
+	// (or events for a server-sent event payload).
+	Location() string
+	// Pointer is an RFC 6901 JSON Pointer to the failing field within its location,
+	// e.g. /items/3/email. It is empty when the location itself failed (such as a missing body).
+	Pointer() string
+	// Args are the rule arguments, e.g. ["5"] for min=5.
+	Args() []string
+	// Value is a printable rendering of the offending value. Long strings are truncated,
+	// collections are summarised and cookie or credential header values are redacted.
+	Value() string
+	// Message is the failure message without the location suffix Error adds. Request failures
+	// are rendered from the message catalog of the request language (see DefaultMessages).
+	Message() string
+}
+
+// ValidationErrors is every validation failure found for a request, a response or a struct
+// passed to ValidateStruct, in the order they were found. ValidateAndBind returns it when
+// validation fails:
+//
+//	var verrs gofi.ValidationErrors
+//	if errors.As(err, &verrs) {
+//		for _, e := range verrs {
+//			log.Println(e.Location(), e.Pointer(), e.Rule())
+//		}
+//	}
+type ValidationErrors []ValidationError
+
+func (v ValidationErrors) Error() string {
+	msgs := make([]string, 0, len(v))
+	for _, e := range v {
+		msgs = append(msgs, e.Error())
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// Unwrap lets errors.As and errors.Is reach the individual failures.
+func (v ValidationErrors) Unwrap() []error {
+	errs := make([]error, 0, len(v))
+	for _, e := range v {
+		errs = append(errs, e)
+	}
+	return errs
+}
+
+// appendValidationErrors flattens the failures joined into err onto dst. Leaf errors that are
+// not validation errors (e.g. from a custom body parser) are reported against field.
+func appendValidationErrors(dst ValidationErrors, err error, typ errorType, field schemaField) ValidationErrors {
+	switch v := err.(type) {
+	case nil:
+	case ValidationErrors:
+		dst = append(dst, v...)
+	case *errReport:
+		dst = append(dst, v)
+	case interface{ Unwrap() []error }:
+		for _, ie := range v.Unwrap() {
+			dst = appendValidationErrors(dst, ie, typ, field)
+		}
+	default:
+		var verr ValidationError
+		if errors.As(err, &verr) {
+			dst = append(dst, verr)
+		} else {
+			dst = append(dst, newErrReport(typ, field, "", "parser", err))
+		}
+	}
+	return dst
+}
+
+// renderErrValue renders a failing value for error output without leaking credentials or
+// dumping large payloads.
+func renderErrValue(field schemaField, key string, val any) string {
+	if val == nil {
+		return ""
+	}
+
+	if field == schemaCookies {
+		return "[redacted]"
+	}
+	if field == schemaHeaders {
+		switch strings.ToLower(key) {
+		case "authorization", "proxy-authorization", "x-api-key", "cookie", "set-cookie":
+			return "[redacted]"
+		}
+	}
+
+	return utils.RenderValue(val)
 }
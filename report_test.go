@@ -0,0 +1,132 @@
+package gofi
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type reportSchema struct {
+	Request struct {
+		Header struct {
+			Authorization string `json:"authorization" validate:"min=10"`
+		}
+		Query struct {
+			Page int `json:"page" validate:"min=1"`
+		}
+		Body struct {
+			Items []struct {
+				Email string `json:"email" validate:"email"`
+			} `json:"items"`
+		}
+	}
+}
+
+func TestValidateAndBind_CollectsErrorsAcrossParts(t *testing.T) {
+	var bindErr error
+	r := NewRouter()
+	_, err := r.Inject(InjectOptions{
+		Path:    "/items",
+		Method:  "POST",
+		Query:   map[string]string{"page": "0"},
+		Headers: map[string]string{"Content-Type": "application/json", "Authorization": "short"},
+		Body:    strings.NewReader(`{"items":[{"email":"a@example.com"},{"email":"not-an-email"}]}`),
+		Handler: &RouteOptions{
+			Schema: &reportSchema{},
+			Handler: func(c Context) error {
+				_, bindErr = ValidateAndBind[reportSchema](c)
+				return nil
+			},
+		},
+	})
+	require.Nil(t, err)
+
+	var verrs ValidationErrors
+	require.ErrorAs(t, bindErr, &verrs)
+	require.Len(t, verrs, 3)
+
+	byLocation := map[string]ValidationError{}
+	for _, e := range verrs {
+		byLocation[e.Location()] = e
+	}
+
+	header := byLocation["header"]
+	require.NotNil(t, header)
+	assert.Equal(t, "min", header.Rule())
+	assert.Equal(t, []string{"10"}, header.Args())
+	assert.Equal(t, "[redacted]", header.Value())
+
+	query := byLocation["query"]
+	require.NotNil(t, query)
+	assert.Equal(t, "/page", query.Pointer())
+	assert.Equal(t, "0", query.Value())
+
+	body := byLocation["body"]
+	require.NotNil(t, body)
+	assert.Equal(t, "/items/1/email", body.Pointer())
+	assert.Equal(t, "email", body.Rule())
+	assert.Equal(t, "not-an-email", body.Value())
+
+	// The individual failures are still reachable as ValidationError.
+	var verr ValidationError
+	assert.True(t, errors.As(bindErr, &verr))
+}
+
+func TestErrReport_Value(t *testing.T) {
+	long := strings.Repeat("x", 100)
+	assert.Equal(t, strings.Repeat("x", 64)+"…", renderErrValue(schemaBody, "name", long))
+	assert.Equal(t, "[]int(len=3)", renderErrValue(schemaBody, "ids", []int{1, 2, 3}))
+	assert.Equal(t, "[redacted]", renderErrValue(schemaCookies, "session", "abc"))
+	assert.Equal(t, "", renderErrValue(schemaBody, "name", nil))
+
+	e := newErrReport(RequestErr, schemaBody, "a/b.c~d", "required", errors.New("missing"))
+	assert.Equal(t, "/a~1b/c~0d", e.Pointer())
+}
+
+func TestValidationErrors_PointerAndOrder(t *testing.T) {
+	type schema struct {
+		Request struct {
+			Query struct {
+				A int `json:"a" validate:"min=1"`
+				B int `json:"b" validate:"min=1"`
+				C int `json:"c" validate:"min=1"`
+				D int `json:"d" validate:"min=1"`
+			}
+			Body struct {
+				Scores map[string]int `json:"scores" validate:"dive,min=1"`
+			}
+		}
+	}
+
+	for range 10 {
+		var bindErr error
+		r := NewRouter()
+		_, err := r.Inject(InjectOptions{
+			Path:    "/scores",
+			Method:  "POST",
+			Query:   map[string]string{"a": "0", "b": "0", "c": "0", "d": "0"},
+			Headers: map[string]string{"Content-Type": "application/json"},
+			Body:    strings.NewReader(`{"scores":{"a.b/c~d":0}}`),
+			Handler: &RouteOptions{
+				Schema: &schema{},
+				Handler: func(c Context) error {
+					_, bindErr = ValidateAndBind[schema](c)
+					return nil
+				},
+			},
+		})
+		require.NoError(t, err)
+
+		var verrs ValidationErrors
+		require.ErrorAs(t, bindErr, &verrs)
+		pointers := make([]string, 0, len(verrs))
+		for _, e := range verrs {
+			pointers = append(pointers, e.Pointer())
+		}
+		assert.Equal(t, []string{"/a", "/b", "/c", "/d", "/scores/a.b~1c~0d"}, pointers)
+		assert.Equal(t, "scores.a.b/c~d", verrs[4].SchemaValue())
+	}
+}
@@ -1,16 +1,25 @@
 package gofi
 
 import (
+	"bufio"
 	"bytes"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
+	"iter"
 	"log/slog"
+	"mime"
 	"net/http"
 	"reflect"
+	"slices"
+	"strconv"
 	"time"
 
 	"github.com/michaelolof/gofi/cont"
 	"github.com/michaelolof/gofi/utils"
+	"github.com/valyala/fasthttp"
+	"github.com/valyala/fastjson"
 )
 
 // RequestSchema defines supported request segments for selective validation/binding.
@@ -36,6 +45,18 @@ const (
 
 const partAll = partHeader | partPath | partQuery | partCookie | partBody
 
+// requestParts lists the request parts in the order they are validated.
+var requestParts = []struct {
+	mask  requestPartMask
+	field schemaField
+}{
+	{partHeader, schemaHeaders},
+	{partQuery, schemaQuery},
+	{partPath, schemaPath},
+	{partCookie, schemaCookies},
+	{partBody, schemaBody},
+}
+
 func buildRequestPartMask(selectors []RequestSchema) (requestPartMask, error) {
 	if len(selectors) == 0 {
 		return partAll, nil
@@ -119,7 +140,16 @@ func ValidateAndBind[T any](c Context, s ...RequestSchema) (*T, error) {
 		}
 	}
 
-	schema, err := validateAndOrBindRequest[T](ctx, true, mask)
+	var schema *T
+	if gen := ctx.rules().genSchema(); gen != nil && useCache {
+		if ctx.serverOpts.verifyGenerated {
+			schema, err = verifyBind[T](ctx, gen)
+		} else {
+			schema, err = bindGenerated[T](ctx, gen)
+		}
+	} else {
+		schema, err = validateAndOrBindRequest[T](ctx, true, mask)
+	}
 	if err != nil {
 		if useCache {
 			ctx.bindedCacheResult = bindedResult{bound: true, err: err}
@@ -152,40 +182,51 @@ func validateAndOrBindRequest[T any](c *context, shouldBind bool, mask requestPa
 		return schemaPtr, nil
 	}
 
+	// Validate hooks read bound values, so a call that only validates binds into a scratch
+	// schema when the parts it checks have hooks.
+	bind, target := shouldBind, any(schemaPtr)
+	if !shouldBind && c.rules().reqHooked(mask) {
+		bind, target = true, reflect.New(c.rules().schemaType).Interface()
+	}
+
 	var reqStruct reflect.Value
-	if shouldBind {
-		reqStruct = reflect.ValueOf(schemaPtr).Elem().FieldByName(string(schemaReq))
+	if bind {
+		reqStruct = reflect.ValueOf(target).Elem().FieldByName(string(schemaReq))
 	}
 
-	// Shared error buffer — nil-initialized, only allocates on first error
-	var errs []error
+	// Failures from every part are collected so the caller sees them all at once.
+	// Nil-initialized, only allocates on first error.
+	var errs ValidationErrors
 
 	// Handle Headers
 	if mask&partHeader != 0 {
 		if pdef := c.rules().getReqRules(schemaHeaders); pdef != nil && len(pdef.properties) > 0 {
-			for _, def := range pdef.properties {
-				hv := c.headerGet(def.field)
-				if err := doValidateStrAndBind(c, schemaHeaders, hv, def, shouldBind, reqStruct); err != nil {
-					errs = append(errs, err)
+			for _, def := range pdef.orderedProps {
+				if err := c.bindParamDef(schemaHeaders, def, bind, reqStruct); err != nil {
+					errs = appendValidationErrors(errs, err, RequestErr, schemaHeaders)
 				}
 			}
-			if len(errs) > 0 {
-				return nil, errors.Join(errs...)
+			if err := validateCrossFields(pdef, RequestErr, schemaHeaders, nil, c.strFieldLookup(c.headerGet)); err != nil {
+				errs = appendValidationErrors(errs, err, RequestErr, schemaHeaders)
 			}
 		}
 	}
 
 	// Handle queries
 	if mask&partQuery != 0 {
+		if c.rules().strictQuery {
+			if err := checkUnknownQuery(c.fctx.QueryArgs(), c.rules().getReqRules(schemaQuery)); err != nil {
+				errs = appendValidationErrors(errs, err, RequestErr, schemaQuery)
+			}
+		}
 		if pdef := c.rules().getReqRules(schemaQuery); pdef != nil && len(pdef.properties) > 0 {
-			for _, def := range pdef.properties {
-				qv := c.queryGet(def.field)
-				if err := doValidateStrAndBind(c, schemaQuery, qv, def, shouldBind, reqStruct); err != nil {
-					errs = append(errs, err)
+			for _, def := range pdef.orderedProps {
+				if err := c.bindParamDef(schemaQuery, def, bind, reqStruct); err != nil {
+					errs = appendValidationErrors(errs, err, RequestErr, schemaQuery)
 				}
 			}
-			if len(errs) > 0 {
-				return nil, errors.Join(errs...)
+			if err := validateCrossFields(pdef, RequestErr, schemaQuery, nil, c.strFieldLookup(c.queryGet)); err != nil {
+				errs = appendValidationErrors(errs, err, RequestErr, schemaQuery)
 			}
 		}
 	}
@@ -193,107 +234,172 @@ func validateAndOrBindRequest[T any](c *context, shouldBind bool, mask requestPa
 	// Handle Paths
 	if mask&partPath != 0 {
 		if pdef := c.rules().getReqRules(schemaPath); pdef != nil && len(pdef.properties) > 0 {
-			for _, def := range pdef.properties {
-				pv := c.params.Get(def.field)
-				if err := doValidateStrAndBind(c, schemaPath, pv, def, shouldBind, reqStruct); err != nil {
-					errs = append(errs, err)
+			for _, def := range pdef.orderedProps {
+				if err := c.bindParamDef(schemaPath, def, bind, reqStruct); err != nil {
+					errs = appendValidationErrors(errs, err, RequestErr, schemaPath)
 				}
 			}
-			if len(errs) > 0 {
-				return nil, errors.Join(errs...)
-			}
 		}
 	}
 
 	// Handle Cookies
 	if mask&partCookie != 0 {
 		if pdef := c.rules().getReqRules(schemaCookies); pdef != nil && len(pdef.properties) > 0 {
-			for _, def := range pdef.properties {
-				cv, err := c.cookieGet(def.field)
-				if def.required && err == http.ErrNoCookie {
-					errs = append(errs, newErrReport(RequestErr, schemaCookies, def.field, "required", err))
-					continue
-				} else if !def.required && cv == nil {
-					continue
-				} else if err != nil {
-					errs = append(errs, err)
-					continue
+			for _, def := range pdef.orderedProps {
+				if err := c.bindParamDef(schemaCookies, def, bind, reqStruct); err != nil {
+					errs = appendValidationErrors(errs, err, RequestErr, schemaCookies)
 				}
+			}
+		}
+	}
 
-				switch def.format {
-				case utils.CookieObjectFormat:
-					err := runValidation(cv.Value, RequestErr, schemaCookies, def.field, def.rules)
-					if err != nil {
-						errs = append(errs, err)
-						continue
-					}
+	if mask&partBody == 0 {
+		if bind {
+			bindRequestBodyWithoutValidation(c, target, reqStruct)
+		}
+	} else if pdef := c.rules().getReqRules(schemaBody); pdef != nil && pdef.kind != reflect.Invalid {
+		err := validateAndOrBindRequestBody(c, bind, target, reqStruct, pdef)
+		errs = appendValidationErrors(errs, err, RequestErr, schemaBody)
+	}
 
-					if shouldBind {
-						sf := reqStruct.FieldByName(string(schemaCookies)).FieldByName(def.fieldName)
-						if sf.Kind() == reflect.Pointer {
-							sf.Set(reflect.ValueOf(cv).Convert(sf.Type()))
-						} else if cv != nil {
-							sf.Set(reflect.ValueOf(*cv).Convert(sf.Type()))
-						}
-					}
+	// Async rules and Validate hooks see the bound values, so they only run once the
+	// synchronous rules pass.
+	if len(errs) == 0 && shouldBind {
+		errs = c.runAsyncValidation(reqStruct, mask)
+	}
+	if len(errs) == 0 && bind {
+		errs = c.runRequestHooks(reqStruct, mask)
+	}
 
-				default:
-					cvs, err := utils.PrimitiveFromStr(def.kind, cv.Value)
-					if err != nil {
-						errs = append(errs, err)
-						continue
-					}
+	if len(errs) > 0 {
+		c.localizeErrors(errs)
+		return nil, errs
+	}
+	return schemaPtr, nil
+}
 
-					if utils.NotPrimitive(cvs) {
-						errs = append(errs, newErrReport(RequestErr, schemaCookies, def.field, "invalid_type", errors.New("only primitives and http.Cookie types are supported")))
-						continue
-					}
+// bindParamDef validates the header, query, path or cookie parameter of def and binds it to
+// reqStruct when shouldBind is set.
+func (c *context) bindParamDef(field schemaField, def *RuleDef, shouldBind bool, reqStruct reflect.Value) error {
+	switch field {
+	case schemaHeaders:
+		if def.isMultiValue() {
+			return c.bindParam(schemaHeaders, def, c.headerValues(def), shouldBind, reqStruct)
+		}
+		return doValidateStrAndBind(c, schemaHeaders, c.headerGet(def.field), def, shouldBind, reqStruct)
 
-					err = runValidation(cvs, RequestErr, schemaCookies, def.field, def.rules)
-					if err != nil {
-						errs = append(errs, err)
-						continue
-					}
+	case schemaQuery:
+		if def.style != "" {
+			return c.bindQueryParam(def, shouldBind, reqStruct)
+		}
+		return doValidateStrAndBind(c, schemaQuery, c.queryGet(def.field), def, shouldBind, reqStruct)
 
-					if shouldBind {
-						sf := reqStruct.FieldByName(string(schemaCookies)).FieldByName(def.fieldName)
-						if sf.Kind() == reflect.Pointer {
-							sf.Elem().Set(reflect.ValueOf(cvs).Convert(sf.Elem().Type()))
-						} else {
-							sf.Set(reflect.ValueOf(cvs).Convert(sf.Type()))
-						}
-					}
+	case schemaPath:
+		return doValidateStrAndBind(c, schemaPath, c.params.Get(def.field), def, shouldBind, reqStruct)
+
+	case schemaCookies:
+		if def.isMultiValue() {
+			return c.bindParam(schemaCookies, def, c.cookieValues(def), shouldBind, reqStruct)
+		}
+		cv, err := c.requestCookie(def)
+		if err != nil || cv == nil {
+			return err
+		}
+
+		switch def.format {
+		case utils.CookieObjectFormat:
+			if err := runValidation(cv.Value, RequestErr, schemaCookies, def.field, def.rules); err != nil {
+				return err
+			}
+
+			if shouldBind {
+				sf := reqStruct.FieldByName(string(schemaCookies)).FieldByName(def.fieldName)
+				if sf.Kind() == reflect.Pointer {
+					sf.Set(reflect.ValueOf(cv).Convert(sf.Type()))
+				} else {
+					sf.Set(reflect.ValueOf(*cv).Convert(sf.Type()))
 				}
 			}
-			if len(errs) > 0 {
-				return nil, errors.Join(errs...)
+
+		default:
+			cvs, err := c.cookieValue(def, cv)
+			if err != nil {
+				return err
+			}
+
+			if shouldBind {
+				sf := reqStruct.FieldByName(string(schemaCookies)).FieldByName(def.fieldName)
+				if sf.Kind() == reflect.Pointer {
+					if sf.IsNil() {
+						sf.Set(reflect.New(sf.Type().Elem()))
+					}
+					sf.Elem().Set(reflect.ValueOf(cvs).Convert(sf.Elem().Type()))
+				} else {
+					sf.Set(reflect.ValueOf(cvs).Convert(sf.Type()))
+				}
 			}
 		}
 	}
+	return nil
+}
 
-	if mask&partBody == 0 {
-		if shouldBind {
-			bindRequestBodyWithoutValidation(c, schemaPtr, reqStruct)
-		}
-		return schemaPtr, nil
+// requestCookie returns the cookie of def. It returns nil when an optional cookie is missing.
+func (c *context) requestCookie(def *RuleDef) (*http.Cookie, error) {
+	cv, err := c.cookieGet(def.field)
+	if def.required && err == http.ErrNoCookie {
+		return nil, newErrReport(RequestErr, schemaCookies, def.field, "required", err)
+	} else if !def.required && cv == nil {
+		return nil, nil
+	} else if err != nil {
+		return nil, newErrReport(RequestErr, schemaCookies, def.field, "parser", err)
+	}
+	return cv, nil
+}
+
+// cookieValue converts the value of cookie cv to the type of def and validates it.
+func (c *context) cookieValue(def *RuleDef, cv *http.Cookie) (any, error) {
+	cvs, err := c.parseStrValue(def, def.modify(cv.Value))
+	if err != nil {
+		return nil, newErrReport(RequestErr, schemaCookies, def.field, "typeMismatch", err)
 	}
 
-	// Handle Body
-	pdef := c.rules().getReqRules(schemaBody)
-	if pdef == nil || pdef.kind == reflect.Invalid {
-		return schemaPtr, nil
+	if err := runValidation(cvs, RequestErr, schemaCookies, def.field, def.rules); err != nil {
+		return nil, err
 	}
+	return cvs, nil
+}
 
-	bodyBytes := c.Body()
-	if len(bodyBytes) == 0 && pdef.required {
-		return schemaPtr, newErrReport(RequestErr, schemaBody, "", "required", errors.New("request body is required"))
-	} else if len(bodyBytes) == 0 {
-		return schemaPtr, nil
+func validateAndOrBindRequestBody(c *context, shouldBind bool, schemaPtr any, reqStruct reflect.Value, pdef *RuleDef) error {
+	bodyBytes, sz, err := c.requestBody(pdef)
+	if err != nil || bodyBytes == nil {
+		return err
 	}
 
 	// Create an io.ReadCloser from the body bytes
 	body := io.NopCloser(bytes.NewReader(bodyBytes))
 
+	return sz.ValidateAndDecodeRequest(body, RequestOptions{
+		ShouldBind:  shouldBind,
+		Context:     &parserContext{c: c},
+		SchemaPtr:   schemaPtr,
+		Body:        &reqStruct,
+		SchemaRules: pdef,
+	})
+}
+
+// requestBody reads the request body and finds the parser for its content type. It returns a
+// nil body when an optional body is empty.
+func (c *context) requestBody(pdef *RuleDef) ([]byte, BodyParser, error) {
+	bodyBytes, err := readRequestBody(c.fctx, c.bodyLimit())
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(bodyBytes) == 0 && pdef.required {
+		return nil, nil, newErrReport(RequestErr, schemaBody, "", "required", errors.New("request body is required"))
+	} else if len(bodyBytes) == 0 {
+		return nil, nil, nil
+	}
+
 	contentType := c.rules().reqContent()
 	// Fall back to the actual request Content-Type header if the schema
 	// didn't specify one explicitly (allows form/multipart parsing to work
@@ -305,25 +411,12 @@ func validateAndOrBindRequest[T any](c *context, shouldBind bool, mask requestPa
 	}
 	sz, err := c.serverOpts.getSerializer(contentType)
 	if err != nil {
-		return schemaPtr, newErrReport(RequestErr, schemaBody, string(contentType), "required", err)
+		return nil, nil, newErrReport(RequestErr, schemaBody, string(contentType), "required", err)
 	}
-
-	err = sz.ValidateAndDecodeRequest(body, RequestOptions{
-		ShouldBind:  shouldBind,
-		Context:     &parserContext{c: c},
-		SchemaPtr:   schemaPtr,
-		Body:        &reqStruct,
-		SchemaRules: pdef,
-	})
-	if err != nil {
-		return schemaPtr, err
-	}
-
-	return schemaPtr, nil
-
+	return bodyBytes, sz, nil
 }
 
-func bindRequestBodyWithoutValidation[T any](c *context, schemaPtr *T, reqStruct reflect.Value) {
+func bindRequestBodyWithoutValidation(c *context, schemaPtr any, reqStruct reflect.Value) {
 	pdef := c.rules().getReqRules(schemaBody)
 	if pdef == nil || pdef.kind == reflect.Invalid {
 		return
@@ -366,6 +459,8 @@ func stripValidationRules(rule *RuleDef) *RuleDef {
 	clone.required = false
 	clone.max = nil
 	clone.rules = nil
+	clone.crossFields = nil
+	clone.strict = false
 
 	if rule.item != nil {
 		clone.item = stripValidationRules(rule.item)
@@ -375,6 +470,10 @@ func stripValidationRules(rule *RuleDef) *RuleDef {
 		clone.additionalProperties = stripValidationRules(rule.additionalProperties)
 	}
 
+	if rule.keys != nil {
+		clone.keys = stripValidationRules(rule.keys)
+	}
+
 	if rule.properties != nil {
 		clone.properties = make(map[string]*RuleDef, len(rule.properties))
 		for key, child := range rule.properties {
@@ -395,55 +494,328 @@ func stripValidationRules(rule *RuleDef) *RuleDef {
 // doValidateStrAndBind validates a string value against rules and optionally binds to a struct field.
 // Extracted from closure to avoid per-request heap allocation.
 func doValidateStrAndBind(c *context, field schemaField, qv string, def *RuleDef, shouldBind bool, reqStruct reflect.Value) error {
+	if def.isWrapper() {
+		return c.bindWrappedStr(field, def, qv, shouldBind, reqStruct)
+	}
+
+	val, err := c.validateStr(field, qv, def)
+	if err != nil || val == nil {
+		return err
+	}
+
+	if shouldBind {
+		sf := reqStruct.FieldByName(string(field)).FieldByName(def.fieldName)
+		rv := reflect.ValueOf(val)
+		if rv.Type().ConvertibleTo(sf.Type()) {
+			sf.Set(rv.Convert(sf.Type()))
+		} else if sf.Kind() == reflect.Pointer && rv.Type().ConvertibleTo(sf.Type().Elem()) {
+			ptr := reflect.New(sf.Type().Elem())
+			ptr.Elem().Set(rv.Convert(sf.Type().Elem()))
+			sf.Set(ptr)
+		} else {
+			slog.Error(newErrReport(ResponseErr, schemaBody, def.field, "typeMismatch",
+				errors.New("cannot convert "+rv.Type().String()+" to "+sf.Type().String())).Error())
+		}
+	}
+
+	return nil
+}
+
+// validateStr converts a header, query or path value to the type declared by def and validates
+// it. It returns nil when an optional value is missing.
+func (c *context) validateStr(field schemaField, qv string, def *RuleDef) (any, error) {
+	qv = def.modify(qv)
 	if qv == "" && def.defStr != "" {
-		qv = def.defStr
+		qv = def.defaultText()
 	}
 
 	if !def.required && qv == "" {
-		return nil
+		return nil, nil
+	}
+
+	val, err := c.parseStrValue(def, qv)
+	if err != nil {
+		return nil, newErrReport(RequestErr, field, def.field, "typeCast", err)
+	}
+
+	if err := runValidation(val, RequestErr, field, def.field, def.rules); err != nil {
+		return nil, err
 	}
+	return val, nil
+}
 
-	var val any
-	var err error
+// parseStrValue converts a header, query or path value to the type declared by def.
+func (c *context) parseStrValue(def *RuleDef, s string) (any, error) {
 	if spec, ok := c.serverOpts.customSpecs.Find(string(def.format)); ok {
-		val, err = spec.Decode(qv)
-		if err != nil {
-			return newErrReport(RequestErr, field, def.field, "typeCast", err)
+		return spec.Decode(s)
+	}
+
+	val, err := utils.PrimitiveFromStr(def.kind, s)
+	if err == nil && !utils.NotPrimitive(val) {
+		return val, nil
+	}
+	// Handle special cases.
+	if def.format == utils.TimeObjectFormat {
+		return time.Parse(def.pattern, s)
+	}
+	if err == nil {
+		err = errors.New("unsupported header type passed")
+	}
+	return nil, err
+}
+
+// readRequestBody buffers the whole request body. With Config.StreamRequestBody enabled,
+// fasthttp hands bodies larger than BodyLimit to the handler as a stream instead of rejecting
+// them, so the limit is enforced here for every reader that buffers; only BodyItems reads past it.
+// A non-positive limit disables the check.
+func readRequestBody(fctx *fasthttp.RequestCtx, limit int) ([]byte, error) {
+	stream := fctx.RequestBodyStream()
+	if stream == nil || limit <= 0 {
+		return fctx.PostBody(), nil
+	}
+
+	body, err := io.ReadAll(io.LimitReader(stream, int64(limit)+1))
+	if err != nil {
+		return nil, newErrReport(RequestErr, schemaBody, "", "reader", err)
+	}
+
+	if len(body) > limit {
+		// The rest of the body is left unread, so the connection cannot be reused.
+		fctx.Request.SetBodyRaw(nil)
+		fctx.SetConnectionClose()
+		return nil, newErrReport(RequestErr, schemaBody, "", "max", fmt.Errorf("request body exceeds the %d byte limit", limit))
+	}
+
+	fctx.Request.SetBodyRaw(body)
+	return body, nil
+}
+
+// BodyItems decodes the request body one item at a time. The body may be newline-delimited
+// JSON (application/x-ndjson) or a top-level JSON array, and the route schema may declare it
+// as either Body []T or Body T. Each item is validated against the body item rules; errors are
+// reported with the item index as the leading key (e.g. "3.email") and iteration continues
+// with the next item unless the body itself can no longer be read. Rules on a Body []T field
+// (required, min, max...) are checked against the number of items: an empty body or too few
+// items is reported once the body ends, and iteration stops as soon as there is one item too many.
+//
+// Only one item is held in memory at a time. Enable Config.StreamRequestBody so bodies larger
+// than BodyLimit are read from the connection as they are consumed. BodyItems reads the body
+// directly, so use ValidateAndBind with explicit parts (Header, Query...) for the rest of the request.
+func BodyItems[T any](c Context) iter.Seq2[T, error] {
+	return func(yieldItem func(T, error) bool) {
+		var zero T
+
+		ctx, ok := c.(*context)
+		if !ok {
+			yieldItem(zero, errors.New("unknown context object passed"))
+			return
 		}
-	} else {
-		val, err = utils.PrimitiveFromStr(def.kind, qv)
-		if err != nil || utils.NotPrimitive(val) {
-			if err == nil {
-				err = errors.New("unsupported header type passed")
+
+		yield := func(v T, err error) bool {
+			if err != nil {
+				ctx.localizeErrors(appendValidationErrors(nil, err, RequestErr, schemaBody))
 			}
-			// Handle special cases.
-			switch def.format {
-			case utils.TimeObjectFormat:
-				val, err = time.Parse(def.pattern, qv)
-				if err != nil {
-					return newErrReport(RequestErr, field, def.field, "typeCast", err)
-				}
-			default:
-				return newErrReport(RequestErr, field, def.field, "typeCast", err)
+			return yieldItem(v, err)
+		}
+
+		if ctx.rules() == nil {
+			yield(zero, newErrReport(RequestErr, schemaReq, "", "required", errors.New("schema not properly registered to route handler")))
+			return
+		}
+
+		pdef := ctx.rules().getReqRules(schemaBody)
+		if pdef == nil || pdef.kind == reflect.Invalid {
+			yield(zero, newErrReport(RequestErr, schemaBody, "", "required", errors.New("request body schema is not defined")))
+			return
+		}
+
+		item := pdef
+		if (pdef.kind == reflect.Slice || pdef.kind == reflect.Array) && pdef.item != nil {
+			item = pdef.item
+		}
+
+		if !matchesRuleType(reflect.TypeFor[T](), item) {
+			yield(zero, newErrReport(RequestErr, schemaBody, "", "typeMismatch", errors.New("body item type does not match the request body schema")))
+			return
+		}
+
+		contentType := ctx.rules().reqContent()
+		if contentType == cont.ApplicationJson {
+			if actualCT := ctx.Request().Header.Get("Content-Type"); actualCT != "" {
+				contentType = cont.ContentType(actualCT)
+			}
+		}
+
+		var body io.Reader = ctx.fctx.RequestBodyStream()
+		if body == nil {
+			body = bytes.NewReader(ctx.fctx.PostBody())
+		}
+
+		dec := bodyItemDecoder{ctx: ctx, pc: &parserContext{c: ctx}, body: pdef, rules: item}
+		if sz, err := ctx.serverOpts.getSerializer(cont.ApplicationJson); err == nil {
+			if j, ok := sz.(*JSONBodyParser); ok {
+				dec.json = *j
 			}
 		}
+
+		if isSequentialJSON(string(contentType)) {
+			decodeNDJSONItems(body, dec, yield)
+			return
+		}
+
+		mediaType, _, _ := mime.ParseMediaType(string(contentType))
+		if !(&JSONBodyParser{}).Match(mediaType) {
+			yield(zero, newErrReport(RequestErr, schemaBody, string(contentType), "typeMismatch", errors.New("body items can only be read from application/x-ndjson or JSON array bodies")))
+			return
+		}
+		decodeJSONArrayItems(body, dec, yield)
 	}
+}
+
+type bodyItemDecoder struct {
+	ctx   *context
+	pc    *parserContext
+	body  *RuleDef
+	rules *RuleDef
+	json  JSONBodyParser
+	p     fastjson.Parser
+}
 
-	err = runValidation(val, RequestErr, field, def.field, def.rules)
+// decodeBodyItem validates a single JSON document against the item rules and binds it into a new T.
+func decodeBodyItem[T any](d *bodyItemDecoder, raw []byte, idx int) (T, error) {
+	var v T
+	key := strconv.Itoa(idx)
+
+	node, err := d.p.ParseBytes(raw)
 	if err != nil {
-		return err
+		return v, newErrReport(RequestErr, schemaBody, key, "parser", err)
 	}
 
-	if shouldBind {
-		sf := reqStruct.FieldByName(string(field)).FieldByName(def.fieldName)
-		rv := reflect.ValueOf(val)
-		if rv.Type().ConvertibleTo(sf.Type()) {
-			sf.Set(rv.Convert(sf.Type()))
-		} else {
-			slog.Error(newErrReport(ResponseErr, schemaBody, def.field, "typeMismatch",
-				errors.New("cannot convert "+rv.Type().String()+" to "+sf.Type().String())).Error())
+	rv := reflect.ValueOf(&v).Elem()
+	opts := RequestOptions{
+		ShouldBind:  true,
+		Context:     d.pc,
+		Body:        &rv,
+		SchemaRules: d.rules,
+	}
+	if _, err := d.json.walkStruct(node, schemaBody, opts, []string{key}); err != nil {
+		return v, err
+	}
+	return v, nil
+}
+
+// bodyItemUpperBounds are the collection rules that can fail as soon as one item too many is read.
+var bodyItemUpperBounds = []string{"max", "lte", "lt"}
+
+// checkCount validates the Body collection rules (required, min, max...) against the number of
+// items read so far. A streaming decoder only knows the count, so the rules see a placeholder
+// slice of that length. With final unset only the upper bounds are checked, so an oversized
+// body is rejected without reading the rest of it.
+func (d *bodyItemDecoder) checkCount(n int, final bool) error {
+	if d.body.kind != reflect.Slice && d.body.kind != reflect.Array {
+		if final && n == 0 && (d.body.required || d.body.present) {
+			return newErrReport(RequestErr, schemaBody, "", "required", errors.New("request body is required"))
 		}
+		return nil
 	}
 
-	return nil
+	rules := d.body.rules
+	if !final {
+		rules = make([]ruleOpts, 0, len(d.body.rules))
+		for _, r := range d.body.rules {
+			if slices.Contains(bodyItemUpperBounds, r.rule) {
+				rules = append(rules, r)
+			}
+		}
+	}
+	return runValidation(make([]struct{}, n), RequestErr, schemaBody, "", rules)
+}
+
+func decodeNDJSONItems[T any](body io.Reader, d bodyItemDecoder, yield func(T, error) bool) {
+	maxItem := int(d.json.MaxRequestSize)
+	if maxItem <= 0 {
+		maxItem = 1048576 // defaultReqSize
+	}
+
+	sc := bufio.NewScanner(body)
+	sc.Buffer(make([]byte, 0, 4096), maxItem)
+
+	idx := 0
+	for sc.Scan() {
+		line := bytes.TrimSpace(sc.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		if err := d.checkCount(idx+1, false); err != nil {
+			var zero T
+			yield(zero, err)
+			return
+		}
+
+		v, err := decodeBodyItem[T](&d, line, idx)
+		idx++
+		if !yield(v, err) {
+			return
+		}
+	}
+
+	var zero T
+	if err := sc.Err(); err != nil {
+		yield(zero, newErrReport(RequestErr, schemaBody, strconv.Itoa(idx), "reader", err))
+		return
+	}
+
+	if err := d.checkCount(idx, true); err != nil {
+		yield(zero, err)
+	}
+}
+
+func decodeJSONArrayItems[T any](body io.Reader, d bodyItemDecoder, yield func(T, error) bool) {
+	var zero T
+	jd := json.NewDecoder(body)
+
+	tok, err := jd.Token()
+	if err == io.EOF {
+		if err := d.checkCount(0, true); err != nil {
+			yield(zero, err)
+		}
+		return
+	} else if err != nil {
+		yield(zero, newErrReport(RequestErr, schemaBody, "", "parser", err))
+		return
+	} else if delim, ok := tok.(json.Delim); !ok || delim != '[' {
+		yield(zero, newErrReport(RequestErr, schemaBody, "", "typeMismatch", errors.New("request body must be a JSON array")))
+		return
+	}
+
+	var raw json.RawMessage
+	idx := 0
+	for ; jd.More(); idx++ {
+		if err := d.checkCount(idx+1, false); err != nil {
+			yield(zero, err)
+			return
+		}
+
+		raw = raw[:0]
+		if err := jd.Decode(&raw); err != nil {
+			// The decoder cannot resynchronise after a syntax error, so stop here.
+			yield(zero, newErrReport(RequestErr, schemaBody, strconv.Itoa(idx), "parser", err))
+			return
+		}
+
+		v, err := decodeBodyItem[T](&d, raw, idx)
+		if !yield(v, err) {
+			return
+		}
+	}
+
+	if _, err := jd.Token(); err != nil {
+		yield(zero, newErrReport(RequestErr, schemaBody, "", "parser", err))
+		return
+	}
+
+	if err := d.checkCount(idx, true); err != nil {
+		yield(zero, err)
+	}
 }
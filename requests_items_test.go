@@ -0,0 +1,254 @@
+package gofi
+
+import (
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+	"github.com/valyala/fasthttp/fasthttputil"
+)
+
+type ingestRecord struct {
+	ID    int    `json:"id" validate:"required"`
+	Email string `json:"email" validate:"required,email"`
+	Tier  string `json:"tier" default:"free"`
+}
+
+type ingestResult struct {
+	items []ingestRecord
+	errs  []error
+}
+
+func runBodyItems(t *testing.T, contentType string, body string) ingestResult {
+	t.Helper()
+
+	type schema struct {
+		Request struct {
+			Body []ingestRecord
+		}
+		Ok struct {
+			Body struct {
+				Count int `json:"count"`
+			}
+		}
+	}
+
+	return collectBodyItems[ingestRecord](t, &schema{}, contentType, body)
+}
+
+func collectBodyItems[T any](t *testing.T, schema any, contentType string, body string) ingestResult {
+	t.Helper()
+
+	var res ingestResult
+	mux := NewRouter()
+	handler := RouteOptions{
+		Schema: schema,
+		Handler: func(c Context) error {
+			for item, err := range BodyItems[T](c) {
+				if err != nil {
+					res.errs = append(res.errs, err)
+					continue
+				}
+				if rec, ok := any(item).(ingestRecord); ok {
+					res.items = append(res.items, rec)
+				}
+			}
+			return c.SendString(200, "ok")
+		},
+	}
+
+	_, err := mux.Inject(InjectOptions{
+		Path:    "/ingest",
+		Method:  "POST",
+		Headers: map[string]string{"Content-Type": contentType},
+		Body:    strings.NewReader(body),
+		Handler: &handler,
+	})
+	require.Nil(t, err)
+	return res
+}
+
+func TestBodyItems_NDJSON(t *testing.T) {
+	res := runBodyItems(t, "application/x-ndjson",
+		"{\"id\":1,\"email\":\"a@example.com\"}\n\n{\"id\":2,\"email\":\"not-an-email\"}\n{\"id\":3,\"email\":\"c@example.com\",\"tier\":\"pro\"}\n")
+
+	assert.Equal(t, []ingestRecord{
+		{ID: 1, Email: "a@example.com", Tier: "free"},
+		{ID: 3, Email: "c@example.com", Tier: "pro"},
+	}, res.items)
+
+	require.Len(t, res.errs, 1)
+	var verr ValidationError
+	require.ErrorAs(t, res.errs[0], &verr)
+	assert.Equal(t, "email", verr.Rule())
+	assert.Equal(t, "1.email", verr.SchemaValue())
+}
+
+func TestBodyItems_JSONArray(t *testing.T) {
+	res := runBodyItems(t, "application/json",
+		`[{"id":1,"email":"a@example.com"},{"email":"b@example.com"},{"id":3,"email":"c@example.com"}]`)
+
+	assert.Equal(t, []int{1, 3}, []int{res.items[0].ID, res.items[1].ID})
+	require.Len(t, res.errs, 1)
+	var verr ValidationError
+	require.ErrorAs(t, res.errs[0], &verr)
+	assert.Equal(t, "required", verr.Rule())
+	assert.Equal(t, "1.id", verr.SchemaValue())
+}
+
+func TestBodyItems_RejectsNonArrayJSON(t *testing.T) {
+	res := runBodyItems(t, "application/json", `{"id":1}`)
+
+	assert.Empty(t, res.items)
+	require.Len(t, res.errs, 1)
+	var verr ValidationError
+	require.ErrorAs(t, res.errs[0], &verr)
+	assert.Equal(t, "typeMismatch", verr.Rule())
+}
+
+func TestBodyItems_RejectsMismatchedItemType(t *testing.T) {
+	type schema struct {
+		Request struct {
+			Body []ingestRecord
+		}
+	}
+
+	res := collectBodyItems[string](t, &schema{}, "application/x-ndjson", "{\"id\":1,\"email\":\"a@example.com\"}\n")
+	require.Len(t, res.errs, 1)
+	var verr ValidationError
+	require.ErrorAs(t, res.errs[0], &verr)
+	assert.Equal(t, "typeMismatch", verr.Rule())
+}
+
+func TestBodyItems_AppliesBodyRules(t *testing.T) {
+	type schema struct {
+		Request struct {
+			Body []ingestRecord `validate:"required,min=2,max=3"`
+		}
+	}
+
+	line := "{\"id\":1,\"email\":\"a@example.com\"}\n"
+
+	for _, ct := range []string{"application/x-ndjson", "application/json"} {
+		res := collectBodyItems[ingestRecord](t, &schema{}, ct, "")
+		require.Len(t, res.errs, 1, ct)
+		var verr ValidationError
+		require.ErrorAs(t, res.errs[0], &verr)
+		assert.Equal(t, "required", verr.Rule(), ct)
+	}
+
+	res := collectBodyItems[ingestRecord](t, &schema{}, "application/x-ndjson", line)
+	require.Len(t, res.errs, 1)
+	var verr ValidationError
+	require.ErrorAs(t, res.errs[0], &verr)
+	assert.Equal(t, "min", verr.Rule())
+
+	// The fourth item breaks max=3, so the rest of the body is not read.
+	res = collectBodyItems[ingestRecord](t, &schema{}, "application/x-ndjson", strings.Repeat(line, 6))
+	assert.Len(t, res.items, 3)
+	require.Len(t, res.errs, 1)
+	require.ErrorAs(t, res.errs[0], &verr)
+	assert.Equal(t, "max", verr.Rule())
+
+	res = collectBodyItems[ingestRecord](t, &schema{}, "application/json", "["+strings.TrimSuffix(strings.Repeat(strings.TrimSpace(line)+",", 4), ",")+"]")
+	assert.Len(t, res.items, 3)
+	require.Len(t, res.errs, 1)
+	require.ErrorAs(t, res.errs[0], &verr)
+	assert.Equal(t, "max", verr.Rule())
+
+	res = collectBodyItems[ingestRecord](t, &schema{}, "application/json", "["+strings.TrimSpace(line)+","+strings.TrimSpace(line)+"]")
+	assert.Len(t, res.items, 2)
+	assert.Empty(t, res.errs)
+}
+
+func TestStreamRequestBody_KeepsBodyLimitOnBufferedRoutes(t *testing.T) {
+	type bufferedSchema struct {
+		Request struct {
+			Body struct {
+				Name string `json:"name"`
+			}
+		}
+		Ok struct {
+			Body struct {
+				Name string `json:"name"`
+			}
+		}
+	}
+	type ingestSchema struct {
+		Request struct {
+			Body []ingestRecord
+		}
+	}
+
+	r := NewRouter()
+	r.Configure(Config{BodyLimit: 256, StreamRequestBody: true})
+	r.Post("/buffered", RouteOptions{
+		Schema: &bufferedSchema{},
+		Handler: func(c Context) error {
+			s, err := ValidateAndBind[bufferedSchema](c)
+			if err != nil {
+				return err
+			}
+			s.Ok.Body.Name = s.Request.Body.Name
+			return c.Send(200, s.Ok)
+		},
+	})
+	r.Post("/raw", RouteOptions{
+		Handler: func(c Context) error {
+			return c.SendString(200, strconv.Itoa(len(c.Body())))
+		},
+	})
+	r.Post("/ingest", RouteOptions{
+		Schema: &ingestSchema{},
+		Handler: func(c Context) error {
+			n := 0
+			for _, err := range BodyItems[ingestRecord](c) {
+				if err != nil {
+					return err
+				}
+				n++
+			}
+			return c.SendString(200, strconv.Itoa(n))
+		},
+	})
+
+	ln := fasthttputil.NewInmemoryListener()
+	srv := &fasthttp.Server{Handler: r.Handler(), MaxRequestBodySize: 256, StreamRequestBody: true}
+	go srv.Serve(ln) //nolint:errcheck
+	t.Cleanup(func() { _ = srv.Shutdown() })
+
+	client := &fasthttp.Client{Dial: func(string) (net.Conn, error) { return ln.Dial() }}
+	post := func(path, contentType, body string) (int, string) {
+		t.Helper()
+		req, res := fasthttp.AcquireRequest(), fasthttp.AcquireResponse()
+		defer fasthttp.ReleaseRequest(req)
+		defer fasthttp.ReleaseResponse(res)
+		req.SetRequestURI("http://gofi" + path)
+		req.Header.SetMethod("POST")
+		req.Header.SetContentType(contentType)
+		req.SetBodyString(body)
+		require.NoError(t, client.Do(req, res))
+		return res.StatusCode(), string(res.Body())
+	}
+
+	large := `{"name":"` + strings.Repeat("x", 1024) + `"}`
+
+	code, body := post("/buffered", "application/json", `{"name":"small"}`)
+	assert.Equal(t, 200, code)
+	assert.JSONEq(t, `{"name":"small"}`, body)
+
+	code, _ = post("/buffered", "application/json", large)
+	assert.NotEqual(t, 200, code)
+
+	_, body = post("/raw", "application/json", large)
+	assert.Equal(t, "0", body)
+
+	line := "{\"id\":1,\"email\":\"a@example.com\"}\n"
+	code, body = post("/ingest", "application/x-ndjson", strings.Repeat(line, 50))
+	assert.Equal(t, 200, code)
+	assert.Equal(t, "50", body)
+}
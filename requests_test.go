@@ -12,6 +12,7 @@ import (
 
 	"github.com/michaelolof/gofi/utils"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestCookieRequest(t *testing.T) {
@@ -3036,3 +3037,49 @@ func TestTimeDecode_RFC3339Nano(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, 200, rec.StatusCode)
 }
+
+func TestRequiredCollectionAbsent(t *testing.T) {
+	type item struct {
+		Name string `json:"name"`
+	}
+	type testSchema struct {
+		Request struct {
+			Body struct {
+				Items []item          `json:"items" validate:"required"`
+				Names []string        `json:"names" validate:"required"`
+				Index map[string]item `json:"index" validate:"required"`
+			}
+		}
+	}
+
+	tests := []struct {
+		body    map[string]any
+		pointer string
+	}{
+		{map[string]any{}, "/items"},
+		{map[string]any{"items": []any{map[string]any{"name": "a"}}}, "/names"},
+		{map[string]any{"items": []any{map[string]any{"name": "a"}}, "names": []any{"a"}, "index": nil}, "/index"},
+	}
+	for _, tt := range tests {
+		var bindErr error
+		m := NewRouter()
+		_, err := m.Inject(InjectOptions{
+			Path:   "/test",
+			Method: "POST",
+			Body:   utils.TryAsReader(tt.body),
+			Handler: &RouteOptions{
+				Schema: &testSchema{},
+				Handler: func(c Context) error {
+					_, bindErr = ValidateAndBind[testSchema](c)
+					return bindErr
+				},
+			},
+		})
+		assert.Nil(t, err)
+
+		var verrs ValidationErrors
+		require.ErrorAs(t, bindErr, &verrs)
+		assert.Equal(t, tt.pointer, verrs[0].Pointer())
+		assert.Equal(t, "required", verrs[0].Rule())
+	}
+}
@@ -1,6 +1,7 @@
 package gofi
 
 import (
+	"bufio"
 	"errors"
 	"fmt"
 	"net/http"
@@ -16,7 +17,7 @@ func (c *context) Send(code int, obj any) error {
 		return newErrReport(ResponseErr, schemaBody, "", "required", errors.New("schema not properly registered to route handler"))
 	}
 
-	_, rules, err := c.rules().getRespRulesByCode(code)
+	key, rules, err := c.rules().getRespRulesByCode(code)
 	if err != nil {
 		return err
 	}
@@ -40,6 +41,10 @@ func (c *context) Send(code int, obj any) error {
 		return errors.New("bad response. invalid response type. response object must be a struct")
 	}
 
+	if err := c.runResponseHooks(key, rules, rv); err != nil {
+		return err
+	}
+
 	if err := c.validateAndEncodeHeaders(rules, rv.FieldByName(string(schemaHeaders))); err != nil {
 		return err
 	}
@@ -59,16 +64,38 @@ func (c *context) Send(code int, obj any) error {
 		bdef = v
 	}
 
-	bs, err := sz.ValidateAndEncodeResponse(obj, ResponseOptions{
-		Context:     &parserContext{c: c},
+	pc := &parserContext{c: c}
+	ropts := ResponseOptions{
+		Context:     pc,
 		SchemaRules: &bdef,
 		Body:        rv.FieldByName(string(schemaBody)),
-	})
+		ContentType: string(contentType),
+	}
+
+	if se, ok := sz.(BodyStreamEncoder); ok {
+		if err := se.ValidateResponse(obj, ropts); err != nil {
+			return err
+		}
+
+		ropts.Context = c.streamParserContext()
+		c.fctx.Response.Header.Set("Content-Type", string(contentType))
+		c.fctx.Response.SetStatusCode(code)
+		return c.SetBodyStreamWriter(func(w *bufio.Writer) error {
+			return se.StreamResponse(w, obj, ropts)
+		})
+	}
+
+	bs, err := c.encodeResponse(sz, key, obj, ropts)
 	if err != nil {
 		return err
 	}
 
-	c.fctx.Response.Header.Set("Content-Type", string(contentType))
+	ct := string(contentType)
+	if pc.contentType != "" {
+		ct = pc.contentType
+	}
+
+	c.fctx.Response.Header.Set("Content-Type", ct)
 	c.fctx.Response.SetStatusCode(code)
 	c.fctx.Response.SetBody(bs)
 
@@ -134,6 +161,21 @@ func (c *context) validateAndEncodeHeaders(rules ruleDefMap, headers reflect.Val
 			continue
 		}
 
+		if val.isMultiValue() {
+			if sv := reflect.Indirect(hf); (!sv.IsValid() || sv.Len() == 0) && c.headerAlreadyWritten(key) {
+				continue
+			}
+			vals, err := c.encodeHeaderValues(val, key, hf)
+			if err != nil {
+				return err
+			}
+			c.fctx.Response.Header.Del(key)
+			for _, v := range vals {
+				c.fctx.Response.Header.Add(key, v)
+			}
+			continue
+		}
+
 		hv := hf.Interface()
 		checkAndSet := func(val string, key string, rules []ruleOpts) error {
 			err := runValidation(val, ResponseErr, schemaHeaders, key, rules)
@@ -161,7 +203,11 @@ func (c *context) validateAndEncodeHeaders(rules ruleDefMap, headers reflect.Val
 		} else {
 			switch true {
 			case utils.IsPrimitiveKind(val.kind):
-				if utils.PrimitiveKindIsEmpty(val.kind, hv) && val.defVal != nil {
+				if utils.PrimitiveKindIsEmpty(val.kind, hv) && val.defFn != nil {
+					if dv, err := c.parseStrValue(val, val.defaultText()); err == nil {
+						hv = dv
+					}
+				} else if utils.PrimitiveKindIsEmpty(val.kind, hv) && val.defVal != nil {
 					hv = val.defVal
 				}
 				if utils.PrimitiveKindIsEmpty(val.kind, hv) && c.headerAlreadyWritten(key) {
@@ -280,9 +326,29 @@ func (c *context) validateAndEncodeCookie(rules ruleDefMap, cookies reflect.Valu
 		}
 
 		cv := cf.Interface()
+		if spec, ok := c.serverOpts.customSpecs.Find(string(val.format)); ok {
+			v, err := spec.Encode(cv)
+			if err != nil {
+				return newErrReport(ResponseErr, schemaCookies, key, "typeMismatch", err)
+			}
+			if v == "" && c.cookieAlreadyWritten(key) {
+				continue
+			}
+			if err := runValidation(v, ResponseErr, schemaCookies, key, val.rules); err != nil {
+				return err
+			}
+			cookie := &http.Cookie{Name: key, Value: v}
+			c.fctx.Response.Header.Add("Set-Cookie", cookie.String())
+			continue
+		}
+
 		switch true {
 		case utils.IsPrimitiveKind(val.kind):
-			if utils.PrimitiveKindIsEmpty(val.kind, cv) && val.defVal != nil {
+			if utils.PrimitiveKindIsEmpty(val.kind, cv) && val.defFn != nil {
+				if dv, err := c.parseStrValue(val, val.defaultText()); err == nil {
+					cv = dv
+				}
+			} else if utils.PrimitiveKindIsEmpty(val.kind, cv) && val.defVal != nil {
 				cv = val.defVal
 			}
 			if utils.PrimitiveKindIsEmpty(val.kind, cv) && c.cookieAlreadyWritten(key) {
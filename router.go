@@ -36,6 +36,10 @@ type Router interface {
 	// UseErrorHandler sets the general error handler for the router
 	UseErrorHandler(func(err error, c Context))
 
+	// UseProblemDetails sets ProblemErrorHandler as the error handler and documents the
+	// application/problem+json schema on every route's error responses.
+	UseProblemDetails()
+
 	// Inject allows you to inject a request handler into the router and get a response.
 	Inject(opts InjectOptions) (*InjectResponse, error)
 
@@ -44,6 +48,13 @@ type Router interface {
 	// primary way to test routing behavior from external packages.
 	Test(opts TestOptions) (*InjectResponse, error)
 
+	// Errors returns the problems found in the routes registered so far, such as malformed
+	// schema tags or conflicting paths.
+	Errors() []*RouteError
+	// Build returns the errors of Errors joined, or nil when every route was registered
+	// cleanly. Listen calls it and refuses to start on an error.
+	Build() error
+
 	// Listen starts the server on the given address
 	Listen(addr string) error
 
@@ -67,6 +78,27 @@ type Router interface {
 	Meta() RouterMeta
 
 	RegisterValidator(list ...Validator)
+	// RegisterAsyncValidator adds rules that need the request context or I/O, such as
+	// uniqueness checks. They run after the synchronous rules pass, in ValidateAndBind only.
+	// Register them before the routes that use them; Build reports routes registered earlier.
+	RegisterAsyncValidator(list ...AsyncValidator)
+	// RegisterModifier adds modifiers for the mod tag. Built-in modifiers of the same name
+	// take precedence.
+	RegisterModifier(list ...Modifier)
+	// RegisterDefault adds a dynamic default that default tags name as "$name", e.g.
+	// default:"$tenant". Built-in defaults of the same name (now, uuid) take precedence.
+	RegisterDefault(name string, fn DefaultFunc)
+	// RegisterAlias lets validate tags use name in place of a rule string, e.g.
+	// RegisterAlias("username", "required,min=3,max=32,alphanum"). Aliases are expanded when
+	// routes are compiled. An alias named after a built-in rule or forming a cycle is not kept
+	// and is reported by Build.
+	RegisterAlias(name string, rules string)
+	// RegisterMessages adds or overrides validation message templates for a language, keyed by
+	// rule name. See DefaultMessages for the template placeholders and the English catalog.
+	RegisterMessages(lang string, messages map[string]string)
+	// UseLanguageResolver picks the language for validation messages. By default the best match
+	// for the Accept-Language header among the registered languages is used.
+	UseLanguageResolver(fn func(c Context) string)
 	RegisterSpec(l ...CustomSpec)
 	RegisterBodyParser(l ...BodyParser)
 	Static(prefix, root string)
@@ -92,4 +124,28 @@ type Config struct {
 	// BodyLimit sets the maximum allowed size for a request body (in bytes).
 	// Default: 4 * 1024 * 1024 (4MB) if zero or not provided.
 	BodyLimit int
+
+	// StreamRequestBody passes request bodies larger than BodyLimit to the handler as a
+	// stream instead of rejecting them. Pair it with gofi.BodyItems on bulk ingest routes
+	// so large bodies are decoded item by item rather than buffered.
+	//
+	// fasthttp applies this to every route. Everywhere else that buffers the body
+	// (ValidateAndBind, Context.Body, the Request adapter), gofi still rejects bodies larger
+	// than BodyLimit, but only after the first BodyLimit bytes have been read. Only BodyItems
+	// reads past the limit.
+	StreamRequestBody bool
+
+	// AsyncValidationWorkers caps how many async validators run at once for a single request.
+	// Default: 4 if zero or not provided.
+	AsyncValidationWorkers int
+
+	// Strict rejects JSON body fields and query parameters that the route schema does not
+	// declare. RouteOptions.Strict overrides it for a route. Routes are compiled when they are
+	// registered, so configure it before adding routes.
+	Strict Strict
+
+	// VerifyGenerated runs the reflective binding and encoding alongside the code generated
+	// by cmd/gofigen, and fails the request when the two disagree. Use it in tests after
+	// generating code; it does the work twice, and async validators run twice.
+	VerifyGenerated bool
 }
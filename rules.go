@@ -18,6 +18,8 @@ type ruleOpts struct {
 	rule  string
 	args  []string
 	dator rules.ValidatorFn
+	// async is set for rules registered with RegisterAsyncValidator; dator is then a no-op.
+	async AsyncValidatorFn
 }
 
 func newRuleOpts(typ reflect.Type, kind reflect.Kind, rule string, args []string, muxOpts *muxOptions) ruleOpts {
@@ -31,13 +33,32 @@ func newRuleOpts(typ reflect.Type, kind reflect.Kind, rule string, args []string
 		customValidators = muxOpts.customValidators
 	}
 
-	return ruleOpts{
+	opts := ruleOpts{
 		typ:   typ,
 		kind:  kind,
 		rule:  rule,
 		args:  args,
 		dator: validators.NewContextValidatorFn(typ, kind, rule, anyArgs, customValidators),
 	}
+
+	if _, builtin := validators.Validators[rule]; !builtin && muxOpts != nil {
+		if v, ok := muxOpts.asyncValidators[rule]; ok {
+			opts.async = v(rules.ValidatorContext{Type: typ, Kind: kind, Options: anyArgs})
+		}
+	}
+	return opts
+}
+
+// knownRule reports whether rule names a built-in, cross-field, custom or async validator.
+func (o *muxOptions) knownRule(rule string) bool {
+	if _, ok := validators.Validators[rule]; ok || isCrossFieldRule(rule) {
+		return true
+	}
+	if _, ok := o.customValidators[rule]; ok {
+		return true
+	}
+	_, ok := o.asyncValidators[rule]
+	return ok
 }
 
 type fieldAccessor struct {
@@ -69,6 +90,30 @@ type RuleDef struct {
 	tags         map[string][]string
 	accessor     fieldAccessor
 	jsonKeyBytes []byte
+
+	// keys holds the map key rules declared between "keys" and "endkeys".
+	keys *RuleDef
+	// dive is the part of the validate tag after "dive", applied to items and map values.
+	dive string
+	// crossFields are the children carrying cross-field rules (eqfield, required_if...).
+	crossFields []*RuleDef
+	// hook is the Validate method of the struct type; hooked is set when this definition or
+	// any of its children has one.
+	hook   hookKind
+	hooked bool
+	// asynced is set when this definition or any of its children has async rules.
+	asynced bool
+	// mods are the modifiers of the mod tag, applied to string input before the rules run.
+	mods []modifierOpts
+	// style and explode are the serialization of a slice, map or struct query parameter.
+	style   string
+	explode bool
+	// strict is set on a request body struct that rejects unknown fields.
+	strict bool
+	// defFn is the provider of a "$name" default; defaulted is set when this definition or any
+	// of its children has a default.
+	defFn     DefaultFunc
+	defaulted bool
 }
 
 func preComputeJSONKey(name string) []byte {
@@ -142,6 +187,25 @@ func (r *RuleDef) hasRule(rule string) bool {
 	return false
 }
 
+func (r *RuleDef) hasCrossFieldRules() bool {
+	if r == nil {
+		return false
+	}
+
+	for _, l := range r.rules {
+		if isCrossFieldRule(l.rule) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasPrimitiveItems reports whether a slice holds primitive values that are read directly
+// from the JSON array.
+func (r *RuleDef) hasPrimitiveItems() bool {
+	return r.item != nil && utils.IsPrimitiveKind(r.item.kind) && r.item.format == ""
+}
+
 func (r *RuleDef) attach(name string, item *RuleDef) {
 	if r == nil && item == nil {
 		return
@@ -150,6 +214,9 @@ func (r *RuleDef) attach(name string, item *RuleDef) {
 	if r != nil {
 		r.properties[name] = item
 		r.orderedProps = append(r.orderedProps, item)
+		if item.hasCrossFieldRules() {
+			r.crossFields = append(r.crossFields, item)
+		}
 	}
 }
 
@@ -175,6 +242,19 @@ func (r *RuleDef) addProps(props *RuleDef) {
 	}
 }
 
+func (r *RuleDef) setKeys(keys *RuleDef) {
+	if r != nil {
+		r.keys = keys
+	}
+}
+
+func (r *RuleDef) diveTag() string {
+	if r == nil {
+		return ""
+	}
+	return r.dive
+}
+
 func (r *RuleDef) ruleOptions(rule string) []string {
 	if r == nil {
 		return nil
@@ -211,14 +291,41 @@ func getItemRuleDef(typ reflect.Type) *RuleDef {
 	return newRuleDef(sf, "", nil, nil, false, false, nil, nil, nil, nil)
 }
 
+// matchesRuleType reports whether values of typ can be bound or encoded with rules.
+// Pointers are ignored on both sides and an interface schema accepts any type.
+func matchesRuleType(typ reflect.Type, rules *RuleDef) bool {
+	if rules == nil || rules.typ == nil {
+		return true
+	}
+
+	want := rules.typ
+	for want.Kind() == reflect.Pointer {
+		want = want.Elem()
+	}
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+	return want.Kind() == reflect.Interface || want == typ
+}
+
 type ruleDefMap map[string]RuleDef
 
 type schemaRules struct {
-	req        map[string]RuleDef
-	responses  map[string]map[string]RuleDef
-	websocket  *compiledWebSocketContract
-	schemaPool *sync.Pool
-	schemaType reflect.Type
+	req       map[string]RuleDef
+	responses map[string]map[string]RuleDef
+	// respMedia holds the content type implied by a response's shape (e.g. an Events stream)
+	// for responses that do not declare a content-type header.
+	respMedia map[string]cont.ContentType
+	// reqHook and respHooks are the Validate methods of the Request and response structs.
+	reqHook   hookKind
+	respHooks map[string]hookKind
+	websocket *compiledWebSocketContract
+	// strictQuery rejects query parameters that the Query struct does not declare.
+	strictQuery bool
+	schemaPool  *sync.Pool
+	schemaType  reflect.Type
+	// gen holds the rules of the generated code of the schema, if it has some.
+	gen *genSchema
 }
 
 func newSchemaRules(typ reflect.Type) schemaRules {
@@ -233,6 +340,8 @@ func newSchemaRules(typ reflect.Type) schemaRules {
 	return schemaRules{
 		req:        make(map[string]RuleDef),
 		responses:  make(map[string]map[string]RuleDef),
+		respMedia:  make(map[string]cont.ContentType),
+		respHooks:  make(map[string]hookKind),
 		schemaPool: pool,
 		schemaType: typ,
 	}
@@ -259,6 +368,10 @@ func (s *schemaRules) setResps(key string, rules *RuleDef) {
 	}
 }
 
+func (s *schemaRules) setRespContent(key string, contentType cont.ContentType) {
+	s.respMedia[key] = contentType
+}
+
 func (s *schemaRules) getReqRules(key schemaField) *RuleDef {
 	if s == nil {
 		return nil
@@ -286,7 +399,7 @@ func (s *schemaRules) reqContent() cont.ContentType {
 }
 
 func (s *schemaRules) respContent(code int) cont.ContentType {
-	_, hsc, _ := s.getRespRulesByCode(code)
+	key, hsc, _ := s.getRespRulesByCode(code)
 	if hsc != nil {
 		if hs, ok := hsc[string(schemaHeaders)]; ok {
 			if v, ok := hs.properties["content-type"]; ok && len(v.defStr) > 0 {
@@ -297,6 +410,9 @@ func (s *schemaRules) respContent(code int) cont.ContentType {
 			}
 		}
 	}
+	if ct, ok := s.respMedia[key]; ok {
+		return ct
+	}
 	return cont.ApplicationJson
 }
 
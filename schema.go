@@ -2,7 +2,6 @@ package gofi
 
 import (
 	"errors"
-	"strings"
 
 	"github.com/michaelolof/gofi/cont"
 )
@@ -25,8 +24,13 @@ type openapiSchema struct {
 	Deprecated           *bool                    `json:"deprecated,omitempty"`
 	Description          string                   `json:"description,omitempty"`
 	Example              any                      `json:"example,omitempty"`
+	Nullable             bool                     `json:"nullable,omitempty"`
 
 	ParentRequired bool `json:"-"`
+	// closed documents additionalProperties: false for the object of a strict body.
+	closed bool
+	// mediaType is the response content type implied by the schema when no content-type header is declared.
+	mediaType string
 }
 
 type openapiDiscriminator struct {
@@ -62,6 +66,8 @@ type openapiParameter struct {
 	In       string        `json:"in"`
 	Name     string        `json:"name"`
 	Required *bool         `json:"required,omitempty"`
+	Style    string        `json:"style,omitempty"`
+	Explode  *bool         `json:"explode,omitempty"`
 	Schema   openapiSchema `json:"schema,omitempty"`
 }
 
@@ -130,6 +136,7 @@ type openapiOperationObject struct {
 	urlPath             string
 	method              string
 	bodySchema          openapiSchema
+	patchMedia          []cont.ContentType
 	websocketSchema     openapiSchema
 	responsesParameters map[string]openapiParameters
 	responsesSchema     map[string]openapiSchema
@@ -164,6 +171,9 @@ func (o *openapiOperationObject) normalize(method string, path string) {
 				},
 			},
 		}
+		if len(o.patchMedia) > 0 {
+			o.RequestBody.Content = patchDocSchemas(o.patchMedia, o.bodySchema)
+		}
 	}
 
 	if len(o.responsesParameters) > 0 || len(o.responsesSchema) > 0 {
@@ -204,6 +214,11 @@ func (o *openapiOperationObject) normalize(method string, path string) {
 					v.Description = sinfo.Description
 					if c, ok := v.Headers["content-type"]; ok {
 						contentType = c.value
+					} else if schema.mediaType != "" {
+						contentType = schema.mediaType
+					}
+					if isSequentialJSON(contentType) {
+						schema = sequentialItemsSchema(schema)
 					}
 					v.Content = map[string]openapiMediaObject{
 						contentType: {
@@ -213,6 +228,9 @@ func (o *openapiOperationObject) normalize(method string, path string) {
 					v.Required = schema.ParentRequired
 					o.Responses[sinfo.Code] = v
 				} else {
+					if schema.mediaType != "" {
+						contentType = schema.mediaType
+					}
 					o.Responses[sinfo.Code] = openapiResponseObject{
 						Required:    schema.ParentRequired,
 						Description: sinfo.Description,
@@ -245,6 +263,20 @@ func (o *openapiOperationObject) normalize(method string, path string) {
 
 }
 
+// sequentialItemsSchema documents a line-delimited JSON response as an array of its items.
+// Bodies declared as a single item T are wrapped so the spec reads the same as Body []T.
+func sequentialItemsSchema(schema openapiSchema) openapiSchema {
+	if schema.Type != "array" {
+		items := schema
+		items.ParentRequired = false
+		schema = openapiSchema{Type: "array", Items: &items, ParentRequired: schema.ParentRequired}
+	}
+	if schema.Description == "" {
+		schema.Description = "Newline-delimited JSON stream. Each line is one item."
+	}
+	return schema
+}
+
 type Info struct {
 	// Prevent path from being documented
 	Hidden       bool
@@ -273,6 +305,7 @@ const (
 	schemaPath      schemaField = "Path"
 	schemaBody      schemaField = "Body"
 	schemaWebSocket schemaField = "WebSocket"
+	schemaEvents    schemaField = "Events"
 )
 
 func (s schemaField) reqSchemaIn() string {
@@ -302,15 +335,17 @@ func runValidation(val any, typ errorType, schema schemaField, keypath string, r
 	var errs []error
 	for _, rule := range rules {
 		if err := rule.dator(val); err != nil {
-			errs = append(errs, newErrReport(typ, schema, keypath, rule.rule, err))
+			report := newErrReport(typ, schema, keypath, rule.rule, err)
+			report.args, report.value = rule.args, val
+			errs = append(errs, report)
 		}
 	}
 
 	return errors.Join(errs...)
 }
 
-// runValidationLazy is like runValidation but defers building the dot-joined keypath
-// until a failure is detected — zero allocation on the happy path.
+// runValidationLazy is like runValidation but defers building the keypath from keys until a
+// failure is detected — zero allocation on the happy path.
 func runValidationLazy(val any, typ errorType, schema schemaField, keys []string, rules []ruleOpts) error {
 	if len(rules) == 0 {
 		return nil
@@ -319,7 +354,9 @@ func runValidationLazy(val any, typ errorType, schema schemaField, keys []string
 	var errs []error
 	for _, rule := range rules {
 		if err := rule.dator(val); err != nil {
-			errs = append(errs, newErrReport(typ, schema, strings.Join(keys, "."), rule.rule, err))
+			report := newKeysReport(typ, schema, keys, rule.rule, err)
+			report.args, report.value = rule.args, val
+			errs = append(errs, report)
 		}
 	}
 
@@ -2,40 +2,70 @@ package gofi
 
 import (
 	"bufio"
+	"bytes"
 	"errors"
+	"iter"
+	"log/slog"
+	"mime"
 	"reflect"
+	"strconv"
+
+	"github.com/michaelolof/gofi/cont"
 )
 
+// SetBodyStreamWriter registers sw to write the response body. fasthttp only starts writing
+// the response once the handler has returned, so sw runs after that and this call returns
+// immediately. By then the request Context has gone back to the pool: sw must not use it
+// (take a c.Copy() if needed). The status line and headers are already on the wire when sw
+// runs, so an error it returns can no longer reach the error handler and is logged instead.
 func (c *context) SetBodyStreamWriter(sw func(w *bufio.Writer) error) error {
-	// We use channels to propagate any errors from the separated writer goroutine
-	// managed internally by fasthttp back to the caller synchronosly.
-	errCh := make(chan error, 1)
-
+	method, path := c.Method(), c.Path()
 	c.fctx.SetBodyStreamWriter(func(w *bufio.Writer) {
-		errCh <- sw(w)
-		close(errCh)
+		if err := sw(w); err != nil {
+			slog.Error("gofi: response stream ended early", "method", method, "path", path, "error", err)
+		}
 	})
+	return nil
+}
 
-	return <-errCh
+// streamParserContext returns a ParserContext for encoders that run inside a body stream
+// writer. The request context is released before the stream is written, so it is detached first.
+func (c *context) streamParserContext() *parserContext {
+	return &parserContext{c: c.Copy().(*context)}
 }
 
 func (c *context) SendStream(code int, obj any, sw func(w *bufio.Writer) error) error {
+	rules, err := c.prepareStream(code, obj)
+	if err != nil || len(rules) == 0 {
+		return err
+	}
+
+	contentType := c.rules().respContent(code)
+	c.fctx.Response.Header.Set("Content-Type", string(contentType))
+	c.fctx.Response.SetStatusCode(code)
+	return c.SetBodyStreamWriter(sw)
+}
+
+// prepareStream validates and writes the response headers and cookies declared for code
+// before a streamed body takes over the connection. A nil rule map means the status code
+// has no response schema and nothing should be streamed.
+func (c *context) prepareStream(code int, obj any) (ruleDefMap, error) {
 	if c.rules() == nil {
-		return newErrReport(ResponseErr, schemaBody, "", "required", errors.New("schema not properly registered to route handler"))
+		return nil, newErrReport(ResponseErr, schemaBody, "", "required", errors.New("schema not properly registered to route handler"))
 	}
 
 	_, rules, err := c.rules().getRespRulesByCode(code)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	if len(rules) == 0 {
-		return nil
+		return nil, nil
 	}
 
 	if obj == nil {
 		// TODO.  If there's is no response body defined, this should be fine
-		return errors.New("undefined schema when calling the gofi Send function")
+		return nil, errors.New("undefined schema when calling the gofi Send function")
 	}
 
 	// Handle if object is a pointer
@@ -45,19 +75,107 @@ func (c *context) SendStream(code int, obj any, sw func(w *bufio.Writer) error)
 	}
 
 	if rv.Kind() != reflect.Struct {
-		return errors.New("bad response. invalid response type. response object must be a struct")
+		return nil, errors.New("bad response. invalid response type. response object must be a struct")
 	}
 
 	if err := c.validateAndEncodeHeaders(rules, rv.FieldByName(string(schemaHeaders))); err != nil {
-		return err
+		return nil, err
 	}
 
 	if err := c.validateAndEncodeCookie(rules, rv.FieldByName(string(schemaCookies))); err != nil {
+		return nil, err
+	}
+
+	return rules, nil
+}
+
+// SendSeq streams the items produced by seq as newline-delimited JSON (application/x-ndjson).
+// obj is the response schema object for code; its headers and cookies are validated and
+// written before the stream starts, as with SendStream. The response body may be declared
+// as either Body []T or Body T, and each item is validated and encoded against the item
+// rules and flushed to the client as soon as it is written.
+//
+// seq is consumed after the handler returns. If seq yields an error or an item fails
+// validation, the stream is cut short and the error is logged; a client disconnect stops
+// the iteration silently.
+func SendSeq[T any](c Context, code int, obj any, seq iter.Seq2[T, error]) error {
+	ctx, ok := c.(*context)
+	if !ok {
+		return errors.New("unknown context object passed")
+	}
+
+	rules, err := ctx.prepareStream(code, obj)
+	if err != nil {
 		return err
 	}
 
-	contentType := c.rules().respContent(code)
-	c.fctx.Response.Header.Set("Content-Type", string(contentType))
-	c.fctx.Response.SetStatusCode(code)
-	return c.SetBodyStreamWriter(sw)
+	bdef, ok := rules[string(schemaBody)]
+	if !ok {
+		return newErrReport(ResponseErr, schemaBody, "", "required", errors.New("no response body schema defined for status code "+strconv.Itoa(code)))
+	}
+
+	item := &bdef
+	if (bdef.kind == reflect.Slice || bdef.kind == reflect.Array) && bdef.item != nil {
+		item = bdef.item
+	}
+
+	if !matchesRuleType(reflect.TypeFor[T](), item) {
+		return newErrReport(ResponseErr, schemaBody, "", "typeMismatch", errors.New("sequence item type does not match the response body schema"))
+	}
+
+	contentType := ctx.rules().respContent(code)
+	if !isSequentialJSON(string(contentType)) {
+		contentType = cont.ApplicationNdjson
+	}
+
+	ctx.fctx.Response.Header.Set("Content-Type", string(contentType))
+	ctx.fctx.Response.SetStatusCode(code)
+
+	pc := ctx.streamParserContext()
+	return ctx.SetBodyStreamWriter(func(w *bufio.Writer) error {
+		var enc JSONBodyParser
+		var buf bytes.Buffer
+
+		i := 0
+		for v, err := range seq {
+			if err != nil {
+				return err
+			}
+
+			key := strconv.Itoa(i)
+			buf.Reset()
+			if err := enc.encodeFieldValue(pc, &buf, reflect.ValueOf(&v).Elem(), item, []string{key}); err != nil {
+				var verr ValidationError
+				if !errors.As(err, &verr) {
+					err = newErrReport(ResponseErr, schemaBody, key, "encoder", err)
+				}
+				return err
+			}
+			buf.WriteByte('\n')
+
+			// A failed write or flush means the client has gone away. There is nobody left
+			// to report to, so stop pulling items and end the stream.
+			if _, err := w.Write(buf.Bytes()); err != nil {
+				return nil
+			}
+			if err := w.Flush(); err != nil {
+				return nil
+			}
+			i++
+		}
+		return nil
+	})
+}
+
+// isSequentialJSON reports whether contentType carries one JSON document per line.
+func isSequentialJSON(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	switch mediaType {
+	case string(cont.ApplicationNdjson), "application/jsonl", "application/jsonlines":
+		return true
+	}
+	return false
 }
@@ -0,0 +1,211 @@
+package gofi
+
+import (
+	"bytes"
+	"errors"
+	"iter"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type seqEvent struct {
+	ID   int    `json:"id" validate:"required"`
+	Kind string `json:"kind" default:"tick"`
+}
+
+type seqSchema struct {
+	Ok struct {
+		Header struct {
+			ContentType string `json:"content-type" default:"application/x-ndjson"`
+			CacheCtrl   string `json:"cache-control" default:"no-cache"`
+			RequestID   string `json:"x-request-id" validate:"required"`
+		}
+		Body []seqEvent
+	}
+}
+
+func seqOf[T any](items ...T) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for _, v := range items {
+			if !yield(v, nil) {
+				return
+			}
+		}
+	}
+}
+
+// captureLogs routes the default slog logger into a buffer for the duration of the test.
+func captureLogs(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	prev := slog.Default()
+	slog.SetDefault(slog.New(slog.NewTextHandler(&buf, nil)))
+	t.Cleanup(func() { slog.SetDefault(prev) })
+	return &buf
+}
+
+func TestSendSeq_StreamsItems(t *testing.T) {
+	mux := NewRouter()
+	handler := RouteOptions{
+		Schema: &seqSchema{},
+		Handler: func(c Context) error {
+			var s seqSchema
+			s.Ok.Header.RequestID = "req-1"
+			return SendSeq(c, 200, s.Ok, seqOf(seqEvent{ID: 1}, seqEvent{ID: 2, Kind: "done"}))
+		},
+	}
+
+	res, err := mux.Inject(InjectOptions{Path: "/events", Method: "GET", Handler: &handler})
+	assert.Nil(t, err)
+	assert.Equal(t, 200, res.StatusCode)
+	assert.Equal(t, "application/x-ndjson", res.Header("Content-Type"))
+	assert.Equal(t, "no-cache", res.Header("Cache-Control"))
+	assert.Equal(t, "req-1", res.Header("X-Request-Id"))
+	assert.Equal(t, "{\"id\":1,\"kind\":\"tick\"}\n{\"id\":2,\"kind\":\"done\"}\n", string(res.Body))
+}
+
+func TestSendSeq_StreamsManyItems(t *testing.T) {
+	const total = 500
+	seq := func(yield func(seqEvent, error) bool) {
+		for i := 1; i <= total; i++ {
+			if !yield(seqEvent{ID: i}, nil) {
+				return
+			}
+		}
+	}
+
+	mux := NewRouter()
+	handler := RouteOptions{
+		Schema: &seqSchema{},
+		Handler: func(c Context) error {
+			var s seqSchema
+			s.Ok.Header.RequestID = "req-1"
+			return SendSeq(c, 200, s.Ok, seq)
+		},
+	}
+
+	res, err := mux.Inject(InjectOptions{Path: "/events", Method: "GET", Handler: &handler})
+	require.Nil(t, err)
+	lines := strings.Split(strings.TrimSuffix(string(res.Body), "\n"), "\n")
+	require.Len(t, lines, total)
+	assert.Equal(t, `{"id":500,"kind":"tick"}`, lines[total-1])
+}
+
+func TestSendSeq_ValidatesHeadersUpFront(t *testing.T) {
+	mux := NewRouter()
+	handler := RouteOptions{
+		Schema: &seqSchema{},
+		Handler: func(c Context) error {
+			var s seqSchema
+			return SendSeq(c, 200, s.Ok, seqOf(seqEvent{ID: 1}))
+		},
+	}
+
+	res, err := mux.Inject(InjectOptions{Path: "/events", Method: "GET", Handler: &handler})
+	assert.Nil(t, err)
+	assert.Equal(t, 500, res.StatusCode)
+	assert.Contains(t, string(res.Body), "x-request-id")
+}
+
+func TestSendSeq_StopsOnInvalidItem(t *testing.T) {
+	logs := captureLogs(t)
+
+	mux := NewRouter()
+	handler := RouteOptions{
+		Schema: &seqSchema{},
+		Handler: func(c Context) error {
+			var s seqSchema
+			s.Ok.Header.RequestID = "req-1"
+			return SendSeq(c, 200, s.Ok, seqOf(seqEvent{ID: 1}, seqEvent{}, seqEvent{ID: 3}))
+		},
+	}
+
+	res, err := mux.Inject(InjectOptions{Path: "/events", Method: "GET", Handler: &handler})
+	assert.Nil(t, err)
+	assert.Equal(t, 200, res.StatusCode)
+	assert.Equal(t, "{\"id\":1,\"kind\":\"tick\"}\n", string(res.Body))
+	assert.Contains(t, logs.String(), "response stream ended early")
+	assert.Contains(t, logs.String(), "1.id")
+}
+
+func TestSendSeq_StopsOnSequenceError(t *testing.T) {
+	logs := captureLogs(t)
+
+	boom := errors.New("boom")
+	pulled := 0
+	seq := func(yield func(*seqEvent, error) bool) {
+		for _, v := range []int{1, 2, 3} {
+			pulled++
+			if v == 2 {
+				yield(nil, boom)
+				return
+			}
+			if !yield(&seqEvent{ID: v}, nil) {
+				return
+			}
+		}
+	}
+
+	mux := NewRouter()
+	handler := RouteOptions{
+		Schema: &seqSchema{},
+		Handler: func(c Context) error {
+			var s seqSchema
+			s.Ok.Header.RequestID = "req-1"
+			return SendSeq(c, 200, s.Ok, seq)
+		},
+	}
+
+	res, err := mux.Inject(InjectOptions{Path: "/events", Method: "GET", Handler: &handler})
+	assert.Nil(t, err)
+	assert.Equal(t, "{\"id\":1,\"kind\":\"tick\"}\n", string(res.Body))
+	assert.Equal(t, 2, pulled)
+	assert.Contains(t, logs.String(), "boom")
+}
+
+func TestSendSeq_RejectsMismatchedItemType(t *testing.T) {
+	mux := NewRouter()
+	var sendErr error
+	handler := RouteOptions{
+		Schema: &seqSchema{},
+		Handler: func(c Context) error {
+			var s seqSchema
+			s.Ok.Header.RequestID = "req-1"
+			sendErr = SendSeq(c, 200, s.Ok, seqOf(struct{ ID int }{ID: 1}))
+			return sendErr
+		},
+	}
+
+	_, err := mux.Inject(InjectOptions{Path: "/events", Method: "GET", Handler: &handler})
+	assert.Nil(t, err)
+
+	var verr ValidationError
+	require.ErrorAs(t, sendErr, &verr)
+	assert.Equal(t, "typeMismatch", verr.Rule())
+}
+
+func TestSendSeq_DocumentsItemSchema(t *testing.T) {
+	type schema struct {
+		Ok struct {
+			Header struct {
+				ContentType string `json:"content-type" default:"application/x-ndjson"`
+			}
+			Body seqEvent
+		}
+	}
+
+	r := newRouter()
+	cs := r.compileSchema(&schema{}, Info{})
+	cs.specs.normalize("GET", "/events")
+
+	media, ok := cs.specs.Responses["200"].Content["application/x-ndjson"]
+	require.True(t, ok)
+	assert.Equal(t, "array", media.Schema.Type)
+	require.NotNil(t, media.Schema.Items)
+	assert.Equal(t, "object", media.Schema.Items.Type)
+	assert.Contains(t, media.Schema.Items.Properties, "id")
+}
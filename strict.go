@@ -0,0 +1,175 @@
+package gofi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+
+	"github.com/valyala/fasthttp"
+	"github.com/valyala/fastjson"
+)
+
+// Strict selects the parts of a request that reject fields the schema does not declare.
+// Set it for every route with Config.Strict, or for one route with RouteOptions.Strict.
+type Strict struct {
+	// Body rejects JSON body properties that are not fields of the struct they appear in.
+	// A `strict:"true"` or `strict:"false"` tag on a struct field overrides it for that
+	// struct and the structs it contains.
+	Body bool
+	// Query rejects query parameters that are not fields of the Query struct.
+	Query bool
+}
+
+// strictTag returns the value of the strict tag of a field, and false when it has none.
+func (r *RuleDef) strictTag() (bool, bool, error) {
+	v := r.tags["strict"]
+	if len(v) == 0 {
+		return false, false, nil
+	}
+	strict, err := strconv.ParseBool(v[0])
+	if err != nil {
+		return false, false, fmt.Errorf("invalid strict tag '%s' on field '%s'", v[0], r.field)
+	}
+	return strict, true, nil
+}
+
+// setStrict resolves the strict tags of a request body, starting from the route's setting,
+// and closes the documented schema of every strict struct.
+func (r *RuleDef) setStrict(strict bool, schema *openapiSchema) error {
+	if r == nil {
+		return nil
+	}
+	if v, ok, err := r.strictTag(); err != nil {
+		return err
+	} else if ok {
+		strict = v
+	}
+
+	switch {
+	case r.isWrapper():
+		return r.item.setStrict(strict, schema)
+	case r.kind == reflect.Struct && schema.Type == "object":
+		r.strict = strict
+		schema.closed = strict
+		for name, p := range r.properties {
+			ps := schema.Properties[name]
+			if err := p.setStrict(strict, &ps); err != nil {
+				return err
+			}
+			schema.Properties[name] = ps
+		}
+	case (r.kind == reflect.Slice || r.kind == reflect.Array) && schema.Items != nil:
+		return r.item.setStrict(strict, schema.Items)
+	case r.kind == reflect.Map && schema.AdditionalProperties != nil:
+		return r.additionalProperties.setStrict(strict, schema.AdditionalProperties)
+	}
+	return nil
+}
+
+// MarshalJSON writes `additionalProperties: false` for the objects of a strict body.
+func (s openapiSchema) MarshalJSON() ([]byte, error) {
+	type schema openapiSchema
+	if !s.closed {
+		return json.Marshal(schema(s))
+	}
+	return json.Marshal(struct {
+		schema
+		AdditionalProperties bool `json:"additionalProperties"`
+	}{schema: schema(s)})
+}
+
+// checkUnknownFields rejects the first member of a JSON object that is not a field of def.
+func checkUnknownFields(node *fastjson.Value, def *RuleDef, schemaField schemaField, keys []string) error {
+	if node == nil {
+		return nil
+	}
+	obj, err := node.Object()
+	if err != nil {
+		return nil // reported by the fields that expect an object
+	}
+
+	var unknown string
+	obj.Visit(func(key []byte, _ *fastjson.Value) {
+		if unknown != "" {
+			return
+		}
+		if _, ok := def.properties[string(key)]; !ok {
+			unknown = string(key)
+		}
+	})
+	if unknown == "" {
+		return nil
+	}
+	path := append(append([]string(nil), keys...), unknown)
+	return newKeysReport(RequestErr, schemaField, path, "unknownField", fmt.Errorf("unknown field '%s'", unknown))
+}
+
+// checkUnknownQuery rejects query parameters that are not fields of the Query struct. The keys
+// of a deepObject parameter must also name fields of its struct.
+func checkUnknownQuery(args *fasthttp.Args, pdef *RuleDef) error {
+	var errs []error
+	seen := make(map[string]bool)
+	args.VisitAll(func(k, _ []byte) {
+		key := string(k)
+		if seen[key] {
+			return
+		}
+		seen[key] = true
+		if path, ok := queryKeyKnown(pdef, key); !ok {
+			errs = append(errs, newKeysReport(RequestErr, schemaQuery, path, "unknownField", fmt.Errorf("unknown query parameter '%s'", key)))
+		}
+	})
+	return errors.Join(errs...)
+}
+
+// queryKeyKnown reports whether a query key belongs to a field of the Query struct. When it
+// does not, it returns the path to the first unknown part of the key.
+func queryKeyKnown(pdef *RuleDef, key string) ([]string, bool) {
+	if pdef == nil {
+		return []string{key}, false
+	}
+
+	for _, def := range pdef.orderedProps {
+		switch {
+		case def.style == styleDeepObject:
+			segs, ok := bracketPath(key, def.field)
+			if !ok {
+				continue
+			}
+			return objectPathKnown(def, segs, []string{def.field})
+		case def.style == styleForm && def.explode && def.kind == reflect.Struct:
+			for _, p := range def.orderedProps {
+				if p.field == key {
+					return nil, true
+				}
+			}
+		case def.field == key:
+			return nil, true
+		}
+	}
+	return []string{key}, false
+}
+
+func objectPathKnown(def *RuleDef, segs []string, path []string) ([]string, bool) {
+	for _, s := range segs {
+		path = append(path, s)
+		switch def.kind {
+		case reflect.Struct:
+			p, ok := def.properties[s]
+			if !ok {
+				return path, false
+			}
+			def = p
+		case reflect.Map:
+			def = def.additionalProperties
+		default:
+			return nil, true
+		}
+		if def == nil {
+			return nil, true
+		}
+	}
+	return nil, true
+}
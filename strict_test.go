@@ -0,0 +1,181 @@
+package gofi
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type strictSchema struct {
+	Request struct {
+		Query struct {
+			Limit  int            `json:"limit"`
+			Tags   []string       `json:"tag"`
+			Filter *queryFilter   `json:"filter"`
+			Labels map[string]int `json:"labels"`
+		}
+		Body struct {
+			Email   string `json:"email"`
+			Profile struct {
+				Name string `json:"name"`
+			} `json:"profile"`
+			Extra struct {
+				Note string `json:"note"`
+			} `json:"extra" strict:"false"`
+			Items []struct {
+				Id int `json:"id"`
+			} `json:"items"`
+			Meta  map[string]string `json:"meta"`
+			Alias Optional[struct {
+				Nick string `json:"nick"`
+			}] `json:"alias"`
+		}
+	}
+}
+
+func injectStrict(t *testing.T, r Router, strict *Strict, query string, body string) error {
+	t.Helper()
+
+	if r == nil {
+		r = NewRouter()
+	}
+	var bindErr error
+	_, err := r.Inject(InjectOptions{
+		Path:    "/strict?" + query,
+		Method:  "POST",
+		Headers: map[string]string{"Content-Type": "application/json"},
+		Body:    strings.NewReader(body),
+		Handler: &RouteOptions{
+			Schema: &strictSchema{},
+			Strict: strict,
+			Handler: func(c Context) error {
+				_, bindErr = ValidateAndBind[strictSchema](c)
+				return nil
+			},
+		},
+	})
+	require.Nil(t, err)
+	return bindErr
+}
+
+func strictErrors(t *testing.T, err error) map[string]string {
+	t.Helper()
+
+	var verrs ValidationErrors
+	require.True(t, errors.As(err, &verrs), "%v", err)
+	got := map[string]string{}
+	for _, e := range verrs {
+		got[e.Location()+":"+e.Pointer()] = e.Rule()
+	}
+	return got
+}
+
+func TestStrict_Body(t *testing.T) {
+	strict := &Strict{Body: true}
+	valid := `{"email": "a@b.co", "profile": {"name": "Ada"}, "extra": {"note": "x", "more": 1}, "items": [{"id": 1}], "meta": {"any": "key"}, "alias": {"nick": "a"}}`
+	assert.NoError(t, injectStrict(t, nil, strict, "", valid))
+	assert.NoError(t, injectStrict(t, nil, nil, "", `{"emial": "a@b.co"}`))
+
+	for body, pointer := range map[string]string{
+		`{"emial": "a@b.co"}`:                       "/emial",
+		`{"profile": {"name": "Ada", "age": 3}}`:    "/profile/age",
+		`{"items": [{"id": 1}, {"id": 2, "x": 0}]}`: "/items/1/x",
+		`{"alias": {"nick": "a", "full": "b"}}`:     "/alias/full",
+	} {
+		assert.Equal(t, map[string]string{"body:" + pointer: "unknownField"}, strictErrors(t, injectStrict(t, nil, strict, "", body)), body)
+	}
+}
+
+func TestStrict_Query(t *testing.T) {
+	strict := &Strict{Query: true}
+	assert.NoError(t, injectStrict(t, nil, strict, "limit=1&tag=a&tag=b&filter[status]=open&filter[owner][name]=me&labels[x]=1", `{}`))
+	assert.NoError(t, injectStrict(t, nil, nil, "limt=1", `{}`))
+
+	assert.Equal(t, map[string]string{
+		"query:/limt":               "unknownField",
+		"query:/filter/owner/email": "unknownField",
+	}, strictErrors(t, injectStrict(t, nil, strict, "limt=1&limt=2&filter[owner][email]=x&labels[y]=2", `{}`)))
+}
+
+func TestStrict_RouterConfig(t *testing.T) {
+	r := NewRouter()
+	r.Configure(Config{Strict: Strict{Body: true, Query: true}})
+
+	assert.Equal(t, map[string]string{
+		"query:/page": "unknownField",
+		"body:/emial": "unknownField",
+	}, strictErrors(t, injectStrict(t, r, nil, "page=2", `{"emial": "x"}`)))
+
+	// A route can turn it off again.
+	assert.NoError(t, injectStrict(t, r, &Strict{}, "page=2", `{"emial": "x"}`))
+}
+
+func TestStrict_Tags(t *testing.T) {
+	type schema struct {
+		Request struct {
+			Query struct {
+				Limit int `json:"limit"`
+			} `strict:"true"`
+			Body struct {
+				Name string `json:"name"`
+			} `strict:"true"`
+		}
+	}
+
+	var bindErr error
+	_, err := NewRouter().Inject(InjectOptions{
+		Path:    "/strict?limit=1&offset=2",
+		Method:  "POST",
+		Headers: map[string]string{"Content-Type": "application/json"},
+		Body:    strings.NewReader(`{"name": "a", "nmae": "b"}`),
+		Handler: &RouteOptions{
+			Schema: &schema{},
+			Handler: func(c Context) error {
+				_, bindErr = ValidateAndBind[schema](c)
+				return nil
+			},
+		},
+	})
+	require.Nil(t, err)
+	assert.Equal(t, map[string]string{
+		"query:/offset": "unknownField",
+		"body:/nmae":    "unknownField",
+	}, strictErrors(t, bindErr))
+}
+
+func TestStrict_Docs(t *testing.T) {
+	r := NewRouter()
+	r.Post("/strict", RouteOptions{Schema: &strictSchema{}, Strict: &Strict{Body: true}, Handler: func(c Context) error { return nil }})
+	r.Post("/open", RouteOptions{Schema: &strictSchema{}, Handler: func(c Context) error { return nil }})
+
+	doc := OpenAPISpec(r, DocsOptions{})
+	raw, err := json.Marshal(doc)
+	require.NoError(t, err)
+
+	var spec struct {
+		Paths map[string]map[string]struct {
+			RequestBody struct {
+				Content map[string]struct {
+					Schema map[string]any `json:"schema"`
+				} `json:"content"`
+			} `json:"requestBody"`
+		} `json:"paths"`
+	}
+	require.NoError(t, json.Unmarshal(raw, &spec))
+
+	body := spec.Paths["/strict"]["post"].RequestBody.Content["*/*"].Schema
+	assert.Equal(t, false, body["additionalProperties"])
+	props := body["properties"].(map[string]any)
+	assert.Equal(t, false, props["profile"].(map[string]any)["additionalProperties"])
+	assert.NotContains(t, props["extra"].(map[string]any), "additionalProperties")
+	assert.Equal(t, false, props["items"].(map[string]any)["items"].(map[string]any)["additionalProperties"])
+	assert.Equal(t, map[string]any{"type": "string"}, props["meta"].(map[string]any)["additionalProperties"])
+	assert.Equal(t, false, props["alias"].(map[string]any)["additionalProperties"])
+
+	open := spec.Paths["/open"]["post"].RequestBody.Content["*/*"].Schema
+	assert.NotContains(t, open, "additionalProperties")
+}
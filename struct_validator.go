@@ -0,0 +1,185 @@
+package gofi
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"sync"
+)
+
+// StructValidator validates structs outside of a request, such as queue messages or CLI input.
+// Validate tags are compiled as they are for a request body, so dive, keys, cross-field rules,
+// aliases and Optional and Nullable fields behave the same way. Rules are compiled once per
+// struct type. Custom rules and aliases are registered on the StructValidator itself, apart
+// from any router.
+type StructValidator struct {
+	mu  sync.Mutex
+	mux *serveMux
+	// defs caches the compiled rules of every struct type validated, by type.
+	defs sync.Map // reflect.Type -> *structDef
+}
+
+type structDef struct {
+	rules *RuleDef
+	// err holds the malformed tags found while compiling.
+	err error
+}
+
+// NewStructValidator returns a StructValidator that knows the built-in rules only.
+func NewStructValidator() *StructValidator {
+	return &StructValidator{mux: newRouter()}
+}
+
+var defaultStructValidator = NewStructValidator()
+
+// ValidateStruct validates v, a struct or a pointer to one, with the built-in rules. See
+// StructValidator.Validate.
+func ValidateStruct(v any) error {
+	return defaultStructValidator.Validate(v)
+}
+
+// RegisterValidator adds custom rules. Types compiled before are compiled again on their next
+// validation.
+func (sv *StructValidator) RegisterValidator(list ...Validator) {
+	sv.mu.Lock()
+	defer sv.mu.Unlock()
+	sv.mux.RegisterValidator(list...)
+	sv.defs.Clear()
+}
+
+// RegisterAlias lets validate tags use name in place of rules. It returns an error if name is a
+// built-in rule or if the alias would form a cycle.
+func (sv *StructValidator) RegisterAlias(name string, rules string) error {
+	sv.mu.Lock()
+	defer sv.mu.Unlock()
+	if err := sv.mux.opts.aliases.Add(name, rules); err != nil {
+		return err
+	}
+	sv.defs.Clear()
+	return nil
+}
+
+// Validate validates v, a struct or a pointer to one, against its validate tags. Nested
+// structs, pointers, slices and maps are walked, and a nil pointer only fails required and
+// present, like a field missing from a request. Every failure is returned as
+// ValidationErrors of type StructErr, with messages rendered from DefaultMessages. Malformed
+// tags are reported as *RouteError values naming the struct type.
+func (sv *StructValidator) Validate(v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Pointer {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return errors.New("ValidateStruct: value must be a struct or pointer to struct")
+	}
+
+	def := sv.compile(rv.Type())
+	if def.err != nil {
+		return def.err
+	}
+
+	errs := appendValidationErrors(nil, validateValue(rv, def.rules, nil), StructErr, schemaBody)
+	if len(errs) == 0 {
+		return nil
+	}
+	localizeReports(errs, defaultMessages)
+	return errs
+}
+
+func (sv *StructValidator) compile(typ reflect.Type) *structDef {
+	if def, ok := sv.defs.Load(typ); ok {
+		return def.(*structDef)
+	}
+
+	sv.mu.Lock()
+	defer sv.mu.Unlock()
+	if def, ok := sv.defs.Load(typ); ok {
+		return def.(*structDef)
+	}
+
+	n := sv.mux.reg.begin("", typ.String())
+	rules := sv.mux.getFieldRuleDefs(reflect.StructField{Name: typ.Name(), Type: typ}, "", nil)
+	sv.mux.getTypeInfo(typ, nil, "", rules)
+	def := &structDef{rules: rules, err: sv.mux.reg.since(n)}
+	// Unknown rules do nothing, as they do in routes, and no async validator can name them later.
+	sv.mux.reg.errs, sv.mux.reg.unknown = sv.mux.reg.errs[:n], nil
+
+	sv.defs.Store(typ, def)
+	return def
+}
+
+// validateValue checks v against def and walks the fields, items and map entries it holds,
+// returning every failure.
+func validateValue(v reflect.Value, def *RuleDef, keys []string) error {
+	if def == nil {
+		return nil
+	}
+
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			v = reflect.Value{}
+			break
+		}
+		v = v.Elem()
+	}
+	if def.isWrapper() {
+		if !v.IsValid() {
+			return nil
+		}
+		inner, ok := wrappedValue(v)
+		if !ok {
+			return nil // rules apply to the wrapped value, and only when it is set
+		}
+		return validateValue(inner, def.item, keys)
+	}
+	if v.IsValid() && !v.CanInterface() {
+		return nil
+	}
+
+	var val any
+	if v.IsValid() {
+		val = v.Interface()
+	}
+	errs := []error{runValidationLazy(val, StructErr, schemaBody, keys, def.rules)}
+	if val == nil {
+		return errors.Join(errs...)
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		for _, child := range def.orderedProps {
+			fv, err := v.FieldByIndexErr(child.accessor.index)
+			if err != nil {
+				fv = reflect.Value{} // promoted through a nil embedded pointer
+			}
+			errs = append(errs, validateValue(fv, child, append(keys, child.field)))
+		}
+		errs = append(errs, validateCrossFields(def, StructErr, schemaBody, keys, structFieldLookup(v)))
+
+	case reflect.Slice, reflect.Array:
+		if def.item == nil {
+			break
+		}
+		for i := 0; i < v.Len(); i++ {
+			errs = append(errs, validateValue(v.Index(i), def.item, append(keys, strconv.Itoa(i))))
+		}
+
+	case reflect.Map:
+		// Entries are visited in key order so failures are reported in a stable order.
+		mkeys := v.MapKeys()
+		slices.SortFunc(mkeys, func(a, b reflect.Value) int {
+			return cmp.Compare(fmt.Sprint(a.Interface()), fmt.Sprint(b.Interface()))
+		})
+		for _, k := range mkeys {
+			key := fmt.Sprint(k.Interface())
+			errs = append(errs,
+				validateValue(k, def.keys, append(keys, key)),
+				validateValue(v.MapIndex(k), def.additionalProperties, append(keys, key)),
+			)
+		}
+	}
+	return errors.Join(errs...)
+}
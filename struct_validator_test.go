@@ -0,0 +1,168 @@
+package gofi
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type deepAddress struct {
+	City string `json:"city" validate:"required"`
+	Zip  string `json:"zip" validate:"len=5"`
+}
+
+type deepItem struct {
+	Min int `json:"min"`
+	Max int `json:"max" validate:"gtefield=min"`
+}
+
+type deepChild struct {
+	Name string `json:"name" validate:"required,min=3"`
+}
+
+type deepStruct struct {
+	Name     string            `json:"name" validate:"required,min=3"`
+	Address  deepAddress       `json:"address"`
+	Billing  *deepAddress      `json:"billing"`
+	Owner    *deepAddress      `json:"owner" validate:"required"`
+	Items    []deepItem        `json:"items"`
+	Tags     []string          `json:"tags" validate:"dive,alpha"`
+	Labels   map[string]string `json:"labels" validate:"dive,keys,alpha,endkeys,required"`
+	Start    time.Time         `json:"start"`
+	End      time.Time         `json:"end" validate:"gtfield=start"`
+	Children []deepChild       `json:"children"`
+}
+
+func validDeepStruct() deepStruct {
+	return deepStruct{
+		Name:    "gofi",
+		Address: deepAddress{City: "Lagos", Zip: "10001"},
+		Owner:   &deepAddress{City: "Abuja", Zip: "90001"},
+		Items:   []deepItem{{Min: 1, Max: 2}},
+		Tags:    []string{"go"},
+		Labels:  map[string]string{"env": "prod"},
+		Start:   time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		End:     time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestValidateStruct_Deep(t *testing.T) {
+	s := validDeepStruct()
+	require.NoError(t, ValidateStruct(&s))
+
+	s.Address.City = ""
+	s.Billing = &deepAddress{City: "Abuja", Zip: "123"}
+	s.Owner = nil
+	s.Items = append(s.Items, deepItem{Min: 5, Max: 1})
+	s.Tags = []string{"go", "v2"}
+	s.Labels = map[string]string{"e1": "prod"}
+	s.End = s.Start.Add(-time.Hour)
+	s.Children = []deepChild{{Name: "x"}}
+
+	err := ValidateStruct(s)
+	var verrs ValidationErrors
+	require.True(t, errors.As(err, &verrs), "got %v", err)
+
+	var got []string
+	for _, e := range verrs {
+		assert.Equal(t, StructErr, e.Type())
+		assert.Equal(t, "body", e.Location())
+		got = append(got, e.Pointer()+":"+e.Rule())
+	}
+	assert.Equal(t, []string{
+		"/address/city:required",
+		"/billing/zip:len",
+		"/owner:required",
+		"/items/1/max:gtefield",
+		"/tags/1:alpha",
+		"/labels/e1:alpha",
+		"/children/0/name:min",
+		"/end:gtfield",
+	}, got)
+}
+
+func TestValidateStruct_Errors(t *testing.T) {
+	s := validDeepStruct()
+	s.Name = "go"
+	s.Tags = []string{"v2"}
+
+	err := ValidateStruct(s)
+	var verrs ValidationErrors
+	require.True(t, errors.As(err, &verrs))
+	require.Len(t, verrs, 2)
+
+	e := verrs[0]
+	assert.Equal(t, "name", e.SchemaValue())
+	assert.Equal(t, "min", e.Rule())
+	assert.Equal(t, []string{"3"}, e.Args())
+	assert.Equal(t, "go", e.Value())
+	assert.Equal(t, "name must be at least 3", e.Message())
+	assert.Equal(t, e.Message()+"\n"+verrs[1].Error(), err.Error())
+}
+
+func TestValidateStruct_InvalidTags(t *testing.T) {
+	type unknownSibling struct {
+		To int `json:"to" validate:"gtfield=from"`
+	}
+	err := ValidateStruct(unknownSibling{})
+	var rerr *RouteError
+	require.True(t, errors.As(err, &rerr), "got %v", err)
+	assert.Contains(t, err.Error(), "unknown field 'from'")
+
+	type unclosedKeys struct {
+		Labels map[string]string `validate:"dive,keys,alpha"`
+	}
+	assert.ErrorContains(t, ValidateStruct(unclosedKeys{}), "endkeys")
+
+	assert.EqualError(t, ValidateStruct("not a struct"), "ValidateStruct: value must be a struct or pointer to struct")
+}
+
+type evenValidator struct{}
+
+func (v *evenValidator) Name() string { return "even" }
+
+func (v *evenValidator) Rule(_ ValidatorContext) func(val any) error {
+	return func(val any) error {
+		if n, ok := val.(int); ok && n%2 != 0 {
+			return errors.New("value must be even")
+		}
+		return nil
+	}
+}
+
+func TestStructValidator_Register(t *testing.T) {
+	type account struct {
+		Count  int    `json:"count" validate:"even"`
+		Handle string `json:"handle" validate:"handle"`
+	}
+
+	sv := NewStructValidator()
+	require.NoError(t, sv.RegisterAlias("handle", "required,min=3,alphanum"))
+	assert.Error(t, sv.RegisterAlias("required", "min=1"))
+
+	// Unknown rules are ignored until they are registered.
+	require.NoError(t, sv.Validate(account{Count: 1, Handle: "gofi"}))
+
+	sv.RegisterValidator(&evenValidator{})
+	err := sv.Validate(account{Count: 1, Handle: "a-b"})
+	var verrs ValidationErrors
+	require.True(t, errors.As(err, &verrs))
+	require.Len(t, verrs, 2)
+	assert.Equal(t, "/count", verrs[0].Pointer())
+	assert.Equal(t, "even", verrs[0].Rule())
+	assert.Equal(t, "/handle", verrs[1].Pointer())
+
+	// Rules registered on a StructValidator stay there.
+	assert.NoError(t, ValidateStruct(account{Count: 1, Handle: "a-b"}))
+}
+
+func BenchmarkValidateStruct(b *testing.B) {
+	s := validDeepStruct()
+	b.ReportAllocs()
+	for b.Loop() {
+		_ = ValidateStruct(&s)
+	}
+}
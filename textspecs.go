@@ -0,0 +1,193 @@
+package gofi
+
+import (
+	"encoding"
+	"fmt"
+	"math/big"
+	"net"
+	"net/netip"
+	"net/url"
+	"reflect"
+	"strconv"
+	"time"
+
+	"github.com/michaelolof/gofi/utils"
+)
+
+var (
+	textMarshalerType   = reflect.TypeFor[encoding.TextMarshaler]()
+	textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+)
+
+// textSpec is the CustomSpec given to fields whose type is encoded as text: any type
+// implementing encoding.TextUnmarshaler or encoding.TextMarshaler, plus time.Duration and
+// url.URL. These bind from headers, queries, paths, cookies and bodies without a spec tag.
+type textSpec struct {
+	specID string
+	typ    string
+	format string
+	decode func(s string) (any, error)
+	encode func(val any) (string, error)
+}
+
+func (t *textSpec) SpecID() string { return t.specID }
+func (t *textSpec) Type() string   { return t.typ }
+func (t *textSpec) Format() string { return t.format }
+
+func (t *textSpec) Encode(val any) (string, error) {
+	return t.encode(val)
+}
+
+func (t *textSpec) Decode(val any) (any, error) {
+	switch v := val.(type) {
+	case string:
+		return t.decode(v)
+	case []byte:
+		return t.decode(string(v))
+	default:
+		return t.decode(fmt.Sprint(v))
+	}
+}
+
+// textFormats are the OpenAPI formats of the standard library text types.
+var textFormats = map[reflect.Type]string{
+	reflect.TypeFor[time.Duration]():  "duration",
+	reflect.TypeFor[url.URL]():        "uri",
+	reflect.TypeFor[net.IP]():         "ip",
+	reflect.TypeFor[netip.Addr]():     "ip",
+	reflect.TypeFor[netip.Prefix]():   "cidr",
+	reflect.TypeFor[netip.AddrPort](): "ip-port",
+	reflect.TypeFor[big.Int]():        "bigint",
+	reflect.TypeFor[big.Float]():      "decimal",
+	reflect.TypeFor[big.Rat]():        "rational",
+}
+
+// newTextSpec returns the text spec for typ, or false if typ is not encoded as text.
+// time.Time keeps its own handling so the pattern tag applies to it.
+func newTextSpec(typ reflect.Type) (*textSpec, bool) {
+	if typ.Kind() == reflect.Pointer || typ.Name() == "" || typ == utils.TimeType {
+		return nil, false
+	}
+
+	spec := &textSpec{
+		specID: utils.TextFormatPrefix + typ.PkgPath() + "." + typ.Name(),
+		typ:    "string",
+		format: textFormats[typ],
+	}
+
+	switch typ {
+	case reflect.TypeFor[time.Duration]():
+		spec.decode = decodeDuration
+		spec.encode = func(val any) (string, error) {
+			return encodeTextValueOf(typ, val, func(v reflect.Value) (string, error) {
+				return time.Duration(v.Int()).String(), nil
+			})
+		}
+		return spec, true
+
+	case reflect.TypeFor[url.URL]():
+		spec.decode = func(s string) (any, error) {
+			u, err := url.Parse(s)
+			if err != nil {
+				return nil, err
+			}
+			return *u, nil
+		}
+		spec.encode = func(val any) (string, error) {
+			return encodeTextValueOf(typ, val, func(v reflect.Value) (string, error) {
+				return v.Addr().Interface().(*url.URL).String(), nil
+			})
+		}
+		return spec, true
+	}
+
+	ptr := reflect.PointerTo(typ)
+	canDecode := ptr.Implements(textUnmarshalerType)
+	canEncode := ptr.Implements(textMarshalerType)
+	if !canDecode && !canEncode {
+		return nil, false
+	}
+
+	spec.decode = func(s string) (any, error) {
+		if !canDecode {
+			return nil, fmt.Errorf("%s does not implement encoding.TextUnmarshaler", typ)
+		}
+		v := reflect.New(typ)
+		if err := v.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s)); err != nil {
+			return nil, err
+		}
+		return v.Elem().Interface(), nil
+	}
+	spec.encode = func(val any) (string, error) {
+		if !canEncode {
+			return "", fmt.Errorf("%s does not implement encoding.TextMarshaler", typ)
+		}
+		return encodeTextValueOf(typ, val, func(v reflect.Value) (string, error) {
+			b, err := v.Addr().Interface().(encoding.TextMarshaler).MarshalText()
+			return string(b), err
+		})
+	}
+	return spec, true
+}
+
+// encodeTextValueOf calls encode with an addressable value of type typ taken from val, which may
+// be a typ or a *typ. Nil pointers encode as an empty string.
+func encodeTextValueOf(typ reflect.Type, val any, encode func(v reflect.Value) (string, error)) (string, error) {
+	v := reflect.ValueOf(val)
+	switch {
+	case v.Kind() == reflect.Pointer && v.Type().Elem() == typ:
+		if v.IsNil() {
+			return "", nil
+		}
+		return encode(v.Elem())
+
+	case v.IsValid() && v.Type() == typ:
+		ptr := reflect.New(typ)
+		ptr.Elem().Set(v)
+		return encode(ptr.Elem())
+	}
+	return "", fmt.Errorf("cannot encode %T as %s", val, typ)
+}
+
+// decodeDuration parses a duration string such as "1h30m". Plain integers are read as
+// nanoseconds, the way encoding/json writes a time.Duration.
+func decodeDuration(s string) (any, error) {
+	d, err := time.ParseDuration(s)
+	if err == nil {
+		return d, nil
+	}
+	if n, nerr := strconv.ParseInt(s, 10, 64); nerr == nil {
+		return time.Duration(n), nil
+	}
+	return nil, err
+}
+
+// typeSpecID returns the ID of the spec that binds and encodes values of typ without a spec
+// tag: a spec registered for the type with DefineSpec, or the text spec of a type encoded as
+// text. It returns an empty string for every other type.
+func (s *serveMux) typeSpecID(typ reflect.Type) string {
+	if id, ok := s.opts.typeSpecs[typ]; ok {
+		return id
+	}
+
+	spec, ok := newTextSpec(typ)
+	if !ok {
+		return ""
+	}
+	if _, exists := s.opts.customSpecs[spec.specID]; !exists {
+		s.opts.customSpecs[spec.specID] = spec
+	}
+	return spec.specID
+}
+
+// validCookieType reports whether a cookie field of type typ can be bound: primitives,
+// http.Cookie and types with a spec.
+func (s *serveMux) validCookieType(typ reflect.Type) bool {
+	if utils.ValidCookieType(typ) {
+		return true
+	}
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+	return s.typeSpecID(typ) != ""
+}
@@ -0,0 +1,267 @@
+package gofi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// orderID is bound through encoding.TextUnmarshaler, e.g. "ord_42".
+type orderID struct{ n int }
+
+func (o *orderID) UnmarshalText(b []byte) error {
+	s, ok := strings.CutPrefix(string(b), "ord_")
+	if !ok {
+		return errors.New("order ids start with ord_")
+	}
+	n, err := strconv.Atoi(s)
+	o.n = n
+	return err
+}
+
+func (o orderID) MarshalText() ([]byte, error) {
+	return []byte("ord_" + strconv.Itoa(o.n)), nil
+}
+
+type textSchema struct {
+	Request struct {
+		Header struct {
+			Client netip.Addr `json:"x-client-ip" validate:"required"`
+		}
+		Path struct {
+			Order orderID `json:"order"`
+		}
+		Query struct {
+			Timeout time.Duration `json:"timeout"`
+			Amount  *big.Int      `json:"amount"`
+			Back    url.URL       `json:"back"`
+		}
+		Cookie struct {
+			Session orderID `json:"session"`
+		}
+		Body struct {
+			Ip      netip.Addr      `json:"ip"`
+			Allowed []netip.Prefix  `json:"allowed"`
+			Wait    time.Duration   `json:"wait"`
+			Retries []time.Duration `json:"retries"`
+			Total   *big.Int        `json:"total"`
+			Order   orderID         `json:"order"`
+		}
+	}
+
+	Ok struct {
+		Header struct {
+			Order orderID `json:"x-order"`
+		}
+		Body struct {
+			Ip    netip.Addr    `json:"ip"`
+			Wait  time.Duration `json:"wait"`
+			Total *big.Int      `json:"total"`
+			Order orderID       `json:"order"`
+			Back  url.URL       `json:"back"`
+		}
+	}
+}
+
+func injectText(t *testing.T, client string, body string, handler func(c Context) error) *InjectResponse {
+	t.Helper()
+
+	res, err := NewRouter().Inject(InjectOptions{
+		Path:    "/orders/:order",
+		Method:  "POST",
+		Paths:   map[string]string{"order": "ord_7"},
+		Query:   map[string]string{"timeout": "1m30s", "amount": "123456789012345678901234567890", "back": "https://example.com/a?b=c"},
+		Headers: map[string]string{"Content-Type": "application/json", "X-Client-Ip": client},
+		Cookies: []http.Cookie{{Name: "session", Value: "ord_9"}},
+		Body:    strings.NewReader(body),
+		Handler: &RouteOptions{Schema: &textSchema{}, Handler: handler},
+	})
+	require.Nil(t, err)
+	return res
+}
+
+func TestTextTypes_Bind(t *testing.T) {
+	var s *textSchema
+	var bindErr error
+	injectText(t, "10.0.0.1", `{
+		"ip": "::1",
+		"allowed": ["10.0.0.0/8", "192.168.0.0/16"],
+		"wait": "2s",
+		"retries": ["1s", 5000000000],
+		"total": 98765432109876543210,
+		"order": "ord_3"
+	}`, func(c Context) error {
+		s, bindErr = ValidateAndBind[textSchema](c)
+		return nil
+	})
+	require.NoError(t, bindErr)
+
+	req := s.Request
+	assert.Equal(t, netip.MustParseAddr("10.0.0.1"), req.Header.Client)
+	assert.Equal(t, orderID{7}, req.Path.Order)
+	assert.Equal(t, 90*time.Second, req.Query.Timeout)
+	require.NotNil(t, req.Query.Amount)
+	assert.Equal(t, "123456789012345678901234567890", req.Query.Amount.String())
+	assert.Equal(t, "https://example.com/a?b=c", req.Query.Back.String())
+	assert.Equal(t, orderID{9}, req.Cookie.Session)
+
+	assert.Equal(t, netip.MustParseAddr("::1"), req.Body.Ip)
+	assert.Equal(t, []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8"), netip.MustParsePrefix("192.168.0.0/16")}, req.Body.Allowed)
+	assert.Equal(t, 2*time.Second, req.Body.Wait)
+	assert.Equal(t, []time.Duration{time.Second, 5 * time.Second}, req.Body.Retries)
+	require.NotNil(t, req.Body.Total)
+	assert.Equal(t, "98765432109876543210", req.Body.Total.String())
+	assert.Equal(t, orderID{3}, req.Body.Order)
+}
+
+func TestTextTypes_InvalidValue(t *testing.T) {
+	var bindErr error
+	injectText(t, "not-an-ip", `{"order": "42"}`, func(c Context) error {
+		_, bindErr = ValidateAndBind[textSchema](c)
+		return nil
+	})
+
+	var verrs ValidationErrors
+	require.True(t, errors.As(bindErr, &verrs))
+	require.Len(t, verrs, 2)
+	assert.Equal(t, "x-client-ip", verrs[0].SchemaValue())
+	assert.Equal(t, "typeCast", verrs[0].Rule())
+	assert.Equal(t, "/order", verrs[1].Pointer())
+	assert.Equal(t, "order ids start with ord_", verrs[1].Message())
+}
+
+func TestTextTypes_Encode(t *testing.T) {
+	res := injectText(t, "10.0.0.1", `{}`, func(c Context) error {
+		var s textSchema
+		s.Ok.Header.Order = orderID{5}
+		s.Ok.Body.Ip = netip.MustParseAddr("192.0.2.1")
+		s.Ok.Body.Wait = 90 * time.Minute
+		s.Ok.Body.Total = big.NewInt(42)
+		s.Ok.Body.Order = orderID{5}
+		s.Ok.Body.Back = url.URL{Scheme: "https", Host: "example.com"}
+		return c.Send(200, s.Ok)
+	})
+	require.Equal(t, 200, res.StatusCode)
+	assert.Equal(t, "ord_5", res.Header("X-Order"))
+
+	var body map[string]any
+	require.NoError(t, json.Unmarshal(res.Body, &body))
+	assert.Equal(t, map[string]any{
+		"ip":    "192.0.2.1",
+		"wait":  "1h30m0s",
+		"total": "42",
+		"order": "ord_5",
+		"back":  "https://example.com",
+	}, body)
+}
+
+func TestTextTypes_Docs(t *testing.T) {
+	r := NewRouter()
+	r.Post("/orders/{order}", RouteOptions{Schema: &textSchema{}, Handler: func(c Context) error { return nil }})
+
+	doc := OpenAPISpec(r, DocsOptions{})
+	op := (*doc.Paths)["/orders/{order}"]["post"]
+
+	params := map[string]openapiSchema{}
+	for _, p := range op.Parameters {
+		params[p.Name] = p.Schema
+	}
+	assert.Equal(t, "ip", params["x-client-ip"].Format)
+	assert.Equal(t, "duration", params["timeout"].Format)
+	assert.Equal(t, "bigint", params["amount"].Format)
+	assert.Equal(t, "uri", params["back"].Format)
+	assert.Equal(t, "string", params["order"].Type)
+
+	body := op.RequestBody.Content["*/*"].Schema
+	assert.Equal(t, "string", body.Properties["ip"].Type)
+	assert.Equal(t, "cidr", body.Properties["allowed"].Items.Format)
+	assert.Equal(t, "string", body.Properties["wait"].Type)
+	assert.Empty(t, body.Properties["order"].Properties)
+}
+
+type money struct {
+	cents int64
+}
+
+var moneySpec = DefineSpec(TypedSpecDefinition[money]{
+	Format: "money",
+	Encode: func(m money) (string, error) {
+		return fmt.Sprintf("%d.%02d", m.cents/100, m.cents%100), nil
+	},
+	Decode: func(s string) (money, error) {
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return money{}, err
+		}
+		return money{cents: int64(f*100 + 0.5)}, nil
+	},
+})
+
+func TestDefineSpec(t *testing.T) {
+	type schema struct {
+		Request struct {
+			Query struct {
+				Min money `json:"min"`
+			}
+			Body struct {
+				Price  money   `json:"price"`
+				Prices []money `json:"prices"`
+			}
+		}
+		Ok struct {
+			Body struct {
+				Price *money `json:"price"`
+			}
+		}
+	}
+
+	r := NewRouter()
+	r.RegisterSpec(moneySpec)
+
+	var s *schema
+	var bindErr error
+	res, err := r.Inject(InjectOptions{
+		Path:    "/prices",
+		Method:  "POST",
+		Query:   map[string]string{"min": "1.50"},
+		Headers: map[string]string{"Content-Type": "application/json"},
+		Body:    strings.NewReader(`{"price": "10.25", "prices": [3, "4.5"]}`),
+		Handler: &RouteOptions{
+			Schema: &schema{},
+			Handler: func(c Context) error {
+				s, bindErr = ValidateAndBind[schema](c)
+				if bindErr != nil {
+					return bindErr
+				}
+				var out schema
+				out.Ok.Body.Price = &s.Request.Body.Price
+				return c.Send(200, out.Ok)
+			},
+		},
+	})
+	require.Nil(t, err)
+	require.NoError(t, bindErr)
+
+	assert.Equal(t, money{150}, s.Request.Query.Min)
+	assert.Equal(t, money{1025}, s.Request.Body.Price)
+	assert.Equal(t, []money{{300}, {450}}, s.Request.Body.Prices)
+	assert.JSONEq(t, `{"price":"10.25"}`, string(res.Body))
+
+	r.Post("/prices", RouteOptions{Schema: &schema{}, Handler: func(c Context) error { return nil }})
+	doc := OpenAPISpec(r, DocsOptions{})
+	params := (*doc.Paths)["/prices"]["post"].Parameters
+	require.Len(t, params, 1)
+	assert.Equal(t, "money", params[0].Schema.Format)
+	assert.Equal(t, "gofi.money", moneySpec.SpecID())
+}
@@ -0,0 +1,133 @@
+package gofi
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"unsafe"
+)
+
+// Response is what a Handle handler returns: a response field of the route schema S and the
+// status code to send it with. Build it with Reply or ReplyStatus, which take the field itself,
+// so a Response can only hold a response of S. The zero Response sends nothing, for handlers
+// that write the response themselves.
+type Response[S any] struct {
+	code int
+	// offset and typ identify the field within S, and body points to it.
+	offset uintptr
+	typ    reflect.Type
+	body   any
+}
+
+// Reply sends the response field of req with its status code, e.g. Reply(req, &req.Created)
+// sends 201. Use ReplyStatus for fields that cover a range of codes, such as Err or ClientError.
+func Reply[S, R any](req *S, field *R) Response[S] {
+	return ReplyStatus(0, req, field)
+}
+
+// ReplyStatus sends the response field of req with the given status code, which must belong
+// to the field.
+func ReplyStatus[S, R any](code int, req *S, field *R) Response[S] {
+	return Response[S]{
+		code:   code,
+		offset: uintptr(unsafe.Pointer(field)) - uintptr(unsafe.Pointer(req)),
+		typ:    reflect.TypeFor[R](),
+		body:   field,
+	}
+}
+
+// Handle builds the route options of a typed handler. The request is validated and bound to
+// S before fn runs, and the Response fn returns is sent with Context.Send. An error from
+// binding or from fn goes to the error handler. A schema that is not a struct is reported when
+// the route is registered. Set Info or Meta on the returned options as needed.
+//
+//	r.Post("/users", gofi.Handle(func(c gofi.Context, req *CreateUserSchema) (gofi.Response[CreateUserSchema], error) {
+//		req.Created.Body.Id = users.Add(req.Request.Body)
+//		return gofi.Reply(req, &req.Created), nil
+//	}))
+func Handle[S any](fn func(c Context, req *S) (Response[S], error)) RouteOptions {
+	typ := reflect.TypeFor[S]()
+	if typ.Kind() != reflect.Struct {
+		// The router reports the schema when the route is registered.
+		return RouteOptions{
+			Schema: new(S),
+			Handler: func(c Context) error {
+				return fmt.Errorf("schema %s passed to Handle must be a struct", typ)
+			},
+		}
+	}
+	fields := responseFields(typ)
+
+	return RouteOptions{
+		Schema: new(S),
+		Handler: func(c Context) error {
+			req, err := ValidateAndBind[S](c)
+			if err != nil {
+				return err
+			}
+			res, err := fn(c, req)
+			if err != nil {
+				return err
+			}
+			return res.send(c, typ, fields)
+		},
+	}
+}
+
+// responseFields returns the response fields of a schema by offset. Fields without size may
+// share the offset of the next field.
+func responseFields(typ reflect.Type) map[uintptr][]reflect.StructField {
+	fields := make(map[uintptr][]reflect.StructField)
+	for _, sf := range reflect.VisibleFields(typ) {
+		if _, ok := statuses[sf.Name]; ok && len(sf.Index) == 1 {
+			fields[sf.Offset] = append(fields[sf.Offset], sf)
+		}
+	}
+	return fields
+}
+
+func (r Response[S]) send(c Context, schema reflect.Type, fields map[uintptr][]reflect.StructField) error {
+	if r.body == nil {
+		return nil
+	}
+
+	// The first field of a response field shares its offset, so the type must match too.
+	var names []string
+	for _, sf := range fields[r.offset] {
+		if sf.Type == r.typ {
+			names = append(names, sf.Name)
+		}
+	}
+	if len(names) == 0 {
+		return newErrReport(ResponseErr, schemaBody, "", "typeMismatch", fmt.Errorf("%s is not a response field of %s", r.typ, schema))
+	}
+
+	code := r.code
+	if code == 0 {
+		if len(names) > 1 {
+			return newErrReport(ResponseErr, schemaBody, "", "typeMismatch", fmt.Errorf("the %v responses of %s have no size and cannot be told apart. use ReplyStatus", names, schema))
+		}
+		infos := statuses[names[0]]
+		n, err := strconv.Atoi(infos[0].Code)
+		if len(infos) != 1 || err != nil {
+			return newErrReport(ResponseErr, schemaBody, "", "typeMismatch", fmt.Errorf("the %s response covers several status codes. use ReplyStatus", names[0]))
+		}
+		code = n
+	} else {
+		ctx, ok := c.(*context)
+		if !ok {
+			return errors.New("unknown context object passed")
+		}
+		key, _, err := ctx.rules().getRespRulesByCode(code)
+		if err != nil {
+			return err
+		}
+		if !slices.Contains(names, key) {
+			return newErrReport(ResponseErr, schemaBody, "", "typeMismatch", fmt.Errorf("status %d is sent with the %s response of %s, not %s", code, key, schema, names[0]))
+		}
+	}
+
+	return c.Send(code, r.body)
+}
@@ -0,0 +1,179 @@
+package gofi
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type typedUser struct {
+	Id   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+type typedSchema struct {
+	Request struct {
+		Path struct {
+			Id int `json:"id" validate:"required"`
+		}
+		Body struct {
+			Name string `json:"name" validate:"required"`
+		}
+	}
+
+	Ok struct {
+		Body typedUser `validate:"required"`
+	}
+	Created struct {
+		Header struct {
+			Location string `json:"Location" validate:"required"`
+		}
+		Body typedUser `validate:"required"`
+	}
+	NotFound struct {
+		Body struct {
+			Message string `json:"message"`
+			Id      int    `json:"id"`
+		}
+	}
+	Err struct {
+		Body struct {
+			Message string `json:"message"`
+		}
+	}
+}
+
+func typedRouter(fn func(c Context, req *typedSchema) (Response[typedSchema], error)) Router {
+	r := NewRouter()
+	r.Post("/users/:id", Handle(fn))
+	return r
+}
+
+func sendTyped(t *testing.T, r Router, body string) *InjectResponse {
+	t.Helper()
+
+	res, err := r.Test(TestOptions{
+		Method:  "POST",
+		Path:    "/users/7",
+		Headers: map[string]string{"Content-Type": "application/json"},
+		Body:    strings.NewReader(body),
+	})
+	require.NoError(t, err)
+	return res
+}
+
+func TestHandle_Reply(t *testing.T) {
+	r := typedRouter(func(c Context, req *typedSchema) (Response[typedSchema], error) {
+		switch req.Request.Body.Name {
+		case "new":
+			req.Created.Header.Location = "/users/7"
+			req.Created.Body = typedUser{Id: req.Request.Path.Id, Name: "new"}
+			return Reply(req, &req.Created), nil
+		case "missing":
+			req.NotFound.Body.Message = "no such user"
+			req.NotFound.Body.Id = req.Request.Path.Id
+			return Reply(req, &req.NotFound), nil
+		case "conflict":
+			req.Err.Body.Message = "taken"
+			return ReplyStatus(409, req, &req.Err), nil
+		}
+		req.Ok.Body = typedUser{Id: req.Request.Path.Id, Name: req.Request.Body.Name}
+		return Reply(req, &req.Ok), nil
+	})
+
+	res := sendTyped(t, r, `{"name": "ada"}`)
+	assert.Equal(t, 200, res.StatusCode)
+	assert.JSONEq(t, `{"id": 7, "name": "ada"}`, string(res.Body))
+
+	res = sendTyped(t, r, `{"name": "new"}`)
+	assert.Equal(t, 201, res.StatusCode)
+	assert.Equal(t, "/users/7", res.HeaderMap.Get("Location"))
+
+	res = sendTyped(t, r, `{"name": "missing"}`)
+	assert.Equal(t, 404, res.StatusCode)
+	assert.JSONEq(t, `{"message": "no such user", "id": 7}`, string(res.Body))
+
+	res = sendTyped(t, r, `{"name": "conflict"}`)
+	assert.Equal(t, 409, res.StatusCode)
+	assert.JSONEq(t, `{"message": "taken"}`, string(res.Body))
+}
+
+func TestHandle_Errors(t *testing.T) {
+	called := false
+	r := typedRouter(func(c Context, req *typedSchema) (Response[typedSchema], error) {
+		called = true
+		switch req.Request.Body.Name {
+		case "fail":
+			return Response[typedSchema]{}, NewHTTPError(418, "teapot")
+		case "range":
+			return Reply(req, &req.Err), nil
+		case "wrong code":
+			return ReplyStatus(201, req, &req.Ok), nil
+		case "foreign":
+			return Reply(req, &typedUser{}), nil
+		case "raw":
+			return Response[typedSchema]{}, c.SendString(202, "raw")
+		}
+		return Reply(req, &req.Ok), nil
+	})
+
+	// Invalid requests never reach the handler.
+	res := sendTyped(t, r, `{}`)
+	assert.Equal(t, 500, res.StatusCode)
+	assert.Contains(t, string(res.Body), "name")
+	assert.False(t, called)
+
+	res = sendTyped(t, r, `{"name": "fail"}`)
+	assert.Equal(t, 418, res.StatusCode)
+
+	for _, name := range []string{"range", "wrong code", "foreign"} {
+		res = sendTyped(t, r, `{"name": "`+name+`"}`)
+		assert.Equal(t, 500, res.StatusCode, name)
+	}
+
+	res = sendTyped(t, r, `{"name": "raw"}`)
+	assert.Equal(t, 202, res.StatusCode)
+	assert.Equal(t, "raw", string(res.Body))
+}
+
+func TestHandle_ResponseSend(t *testing.T) {
+	type sameTypes struct {
+		Ok           struct{ Body string }
+		Accepted     struct{ Body string }
+		NoContent    struct{}
+		ResetContent struct{}
+	}
+
+	// Fields that share a type are told apart by where they are in the schema.
+	var req sameTypes
+	fields := responseFields(reflect.TypeFor[sameTypes]())
+	assert.Equal(t, "Accepted", fields[Reply(&req, &req.Accepted).offset][0].Name)
+
+	// Fields without size share an offset and need a status code.
+	err := Reply(&req, &req.NoContent).send(nil, reflect.TypeFor[sameTypes](), fields)
+	assert.ErrorContains(t, err, "use ReplyStatus")
+
+	assert.NoError(t, Response[sameTypes]{}.send(nil, reflect.TypeFor[sameTypes](), fields))
+}
+
+func TestHandle_NonStructSchema(t *testing.T) {
+	r := NewRouter()
+	r.Get("/count", Handle(func(c Context, req *int) (Response[int], error) { return Response[int]{}, nil }))
+
+	errs := r.Errors()
+	require.Len(t, errs, 1)
+	assert.EqualError(t, errs[0], "GET /count: schema int must be a struct")
+}
+
+func TestHandle_Docs(t *testing.T) {
+	r := typedRouter(func(c Context, req *typedSchema) (Response[typedSchema], error) { return Response[typedSchema]{}, nil })
+
+	doc := OpenAPISpec(r, DocsOptions{})
+	op := (*doc.Paths)["/users/:id"]["post"]
+	require.NotNil(t, op.RequestBody)
+	assert.Contains(t, op.Responses, "201")
+	assert.Contains(t, op.Responses, "404")
+}
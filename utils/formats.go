@@ -5,6 +5,7 @@ import (
 	"mime/multipart"
 	"net/http"
 	"reflect"
+	"strings"
 	"time"
 )
 
@@ -16,8 +17,19 @@ const (
 	CustomObjectFormat ObjectFormats = "custom-object"
 	ByteFormat         ObjectFormats = "byte"
 	RawJSONFormat      ObjectFormats = "raw-json"
+	OptionalFormat     ObjectFormats = "optional"
+	NullableFormat     ObjectFormats = "nullable"
+
+	// TextFormatPrefix marks the formats of types encoded as text, such as
+	// encoding.TextUnmarshaler implementations and time.Duration.
+	TextFormatPrefix = "text:"
 )
 
+// IsText reports whether values of the format are read and written as text whatever their Go kind.
+func (f ObjectFormats) IsText() bool {
+	return strings.HasPrefix(string(f), TextFormatPrefix)
+}
+
 var TimeType = reflect.TypeOf(time.Time{})
 var CookieType = reflect.TypeOf(http.Cookie{})
 var MultipartFile = reflect.TypeOf(multipart.FileHeader{})
@@ -9,6 +9,7 @@ import (
 	"reflect"
 	"strconv"
 	"strings"
+	"unicode/utf8"
 	"unsafe"
 )
 
@@ -199,3 +200,40 @@ func StringToBytes(s string) []byte {
 func BytesToString(b []byte) string {
 	return unsafe.String(unsafe.SliceData(b), len(b))
 }
+
+const maxRenderedValueLen = 64
+
+// RenderValue renders a value for error output. Long strings are truncated and collections are
+// summarised by their length.
+func RenderValue(val any) string {
+	if val == nil {
+		return ""
+	}
+
+	rv := reflect.ValueOf(val)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return "null"
+		}
+		rv = rv.Elem()
+	}
+
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return fmt.Sprintf("%s(len=%d)", rv.Type(), rv.Len())
+	case reflect.Struct, reflect.Interface, reflect.Func, reflect.Chan:
+		if s, ok := val.(fmt.Stringer); ok {
+			return truncateValue(s.String())
+		}
+		return rv.Type().String()
+	}
+	return truncateValue(fmt.Sprintf("%v", rv.Interface()))
+}
+
+func truncateValue(s string) string {
+	if utf8.RuneCountInString(s) <= maxRenderedValueLen {
+		return s
+	}
+	r := []rune(s)
+	return string(r[:maxRenderedValueLen]) + "…"
+}
@@ -0,0 +1,99 @@
+package validators
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Aliases maps alias names to the rule strings they stand for, e.g.
+// "username" -> "required,min=3,max=32,alphanum".
+type Aliases map[string]string
+
+var (
+	aliasMu sync.RWMutex
+	aliases = make(Aliases)
+)
+
+// RegisterAlias lets rule strings passed to Validate use name in place of rules. Aliases may
+// reference other aliases. It returns an error if name is a built-in rule or if the alias would
+// form a cycle.
+func RegisterAlias(name, rules string) error {
+	aliasMu.Lock()
+	defer aliasMu.Unlock()
+	return aliases.Add(name, rules)
+}
+
+// Add registers name as an alias for rules. Re-registering a name replaces its rules.
+func (a Aliases) Add(name, rules string) error {
+	name = strings.TrimSpace(name)
+	if name == "" || strings.ContainsAny(name, ",= ") {
+		return fmt.Errorf("invalid validation alias name '%s'", name)
+	}
+	if _, ok := Validators[name]; ok || isTagKeyword(name) {
+		return fmt.Errorf("validation alias '%s' shadows a built-in rule", name)
+	}
+
+	prev, existed := a[name]
+	a[name] = rules
+	if path := a.cycle(name, []string{name}); path != nil {
+		if existed {
+			a[name] = prev
+		} else {
+			delete(a, name)
+		}
+		return fmt.Errorf("validation alias cycle: %s", strings.Join(path, " -> "))
+	}
+	return nil
+}
+
+// Expand replaces every alias in a validate tag with its rules, recursively. Rules that are not
+// aliases are left as they are.
+func (a Aliases) Expand(tag string) string {
+	if len(a) == 0 || tag == "" {
+		return tag
+	}
+
+	parts := strings.Split(tag, ",")
+	out := make([]string, 0, len(parts))
+	expanded := false
+	for _, p := range parts {
+		if rules, ok := a[strings.TrimSpace(p)]; ok {
+			out = append(out, a.Expand(rules))
+			expanded = true
+			continue
+		}
+		out = append(out, p)
+	}
+	if !expanded {
+		return tag
+	}
+	return strings.Join(out, ",")
+}
+
+// cycle returns the alias chain leading from path back to one of its members, or nil.
+func (a Aliases) cycle(name string, path []string) []string {
+	for _, p := range strings.Split(a[name], ",") {
+		ref := strings.TrimSpace(p)
+		if _, ok := a[ref]; !ok {
+			continue
+		}
+		if slices.Contains(path, ref) {
+			return append(path, ref)
+		}
+		if c := a.cycle(ref, append(path, ref)); c != nil {
+			return c
+		}
+	}
+	return nil
+}
+
+// isTagKeyword reports whether name is part of the validate tag syntax rather than a rule.
+func isTagKeyword(name string) bool {
+	switch name {
+	case "dive", "keys", "endkeys":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,45 @@
+package validators
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRegisterAlias(t *testing.T) {
+	if err := RegisterAlias("test_username", "required,min=3,max=32,alphanum"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := RegisterAlias("test_handle", "test_username,lowercase"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := RegisterAlias("min", "required"); err == nil {
+		t.Errorf("expected min to be rejected as an alias name")
+	}
+
+	if err := Validate("gofi", "test_handle"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := Validate("go", "test_username"); err == nil {
+		t.Errorf("expected min to fail")
+	}
+	if err := Validate("Gofi", "test_handle"); err == nil {
+		t.Errorf("expected lowercase to fail")
+	}
+}
+
+func TestRegisterAlias_Cycle(t *testing.T) {
+	a := make(Aliases)
+	if err := a.Add("x", "y"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	err := a.Add("y", "required,x")
+	if err == nil || !strings.Contains(err.Error(), "y -> x -> y") {
+		t.Errorf("expected a cycle error, got %v", err)
+	}
+	if _, ok := a["y"]; ok {
+		t.Errorf("rejected alias should not be kept")
+	}
+	if err := a.Add("dive", "required"); err == nil {
+		t.Errorf("expected dive to be rejected as an alias name")
+	}
+}
@@ -0,0 +1,224 @@
+package rules
+
+import (
+	"cmp"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/michaelolof/gofi/utils"
+)
+
+// CrossFieldCheck validates val against sibling fields named in args. sibling returns the value
+// of a sibling field, or nil when it is absent.
+type CrossFieldCheck func(val any, args []string, sibling func(name string) any) error
+
+// CrossFieldChecks are the validate rules that compare a field with its siblings. Arguments name
+// sibling fields by their json name (or Go field name). They run once every field of the
+// enclosing struct (or request part) has been read.
+var CrossFieldChecks = map[string]CrossFieldCheck{
+	"eqfield":          equalField(true, "value must be equal to %s"),
+	"nefield":          equalField(false, "value must not be equal to %s"),
+	"gtfield":          compareField(func(c int) bool { return c > 0 }, "value must be greater than %s"),
+	"gtefield":         compareField(func(c int) bool { return c >= 0 }, "value must be greater than or equal to %s"),
+	"ltfield":          compareField(func(c int) bool { return c < 0 }, "value must be less than %s"),
+	"ltefield":         compareField(func(c int) bool { return c <= 0 }, "value must be less than or equal to %s"),
+	"required_if":      isRequiredIf,
+	"required_unless":  isRequiredUnless,
+	"required_with":    isRequiredWith,
+	"required_without": isRequiredWithout,
+	"excluded_with":    isExcludedWith,
+}
+
+func equalField(want bool, errFmt string) CrossFieldCheck {
+	return func(val any, args []string, sibling func(string) any) error {
+		if !HasFieldValue(val) || fieldValuesEqual(val, sibling(args[0])) == want {
+			return nil
+		}
+		return fmt.Errorf(errFmt, args[0])
+	}
+}
+
+func compareField(ok func(int) bool, errFmt string) CrossFieldCheck {
+	return func(val any, args []string, sibling func(string) any) error {
+		if !HasFieldValue(val) {
+			return nil
+		}
+
+		c, comparable := compareFieldValues(val, sibling(args[0]))
+		if !comparable {
+			return fmt.Errorf("value cannot be compared with %s", args[0])
+		}
+		if !ok(c) {
+			return fmt.Errorf(errFmt, args[0])
+		}
+		return nil
+	}
+}
+
+// siblingsMatch reports whether every "field value" pair in args matches.
+func siblingsMatch(args []string, sibling func(string) any) bool {
+	for i := 0; i+1 < len(args); i += 2 {
+		v := sibling(args[i])
+		if v == nil || fmt.Sprint(v) != args[i+1] {
+			return false
+		}
+	}
+	return true
+}
+
+func isRequiredIf(val any, args []string, sibling func(string) any) error {
+	if !HasFieldValue(val) && siblingsMatch(args, sibling) {
+		return fmt.Errorf("value is required when %s", describeFieldPairs(args))
+	}
+	return nil
+}
+
+func isRequiredUnless(val any, args []string, sibling func(string) any) error {
+	if !HasFieldValue(val) && !siblingsMatch(args, sibling) {
+		return fmt.Errorf("value is required unless %s", describeFieldPairs(args))
+	}
+	return nil
+}
+
+func isRequiredWith(val any, args []string, sibling func(string) any) error {
+	if HasFieldValue(val) {
+		return nil
+	}
+	for _, name := range args {
+		if HasFieldValue(sibling(name)) {
+			return fmt.Errorf("value is required when %s is present", name)
+		}
+	}
+	return nil
+}
+
+func isRequiredWithout(val any, args []string, sibling func(string) any) error {
+	if HasFieldValue(val) {
+		return nil
+	}
+	for _, name := range args {
+		if !HasFieldValue(sibling(name)) {
+			return fmt.Errorf("value is required when %s is missing", name)
+		}
+	}
+	return nil
+}
+
+func isExcludedWith(val any, args []string, sibling func(string) any) error {
+	if !HasFieldValue(val) {
+		return nil
+	}
+	for _, name := range args {
+		if HasFieldValue(sibling(name)) {
+			return fmt.Errorf("value must be omitted when %s is present", name)
+		}
+	}
+	return nil
+}
+
+func describeFieldPairs(args []string) string {
+	pairs := make([]string, 0, len(args)/2)
+	for i := 0; i+1 < len(args); i += 2 {
+		pairs = append(pairs, args[i]+" is "+args[i+1])
+	}
+	return strings.Join(pairs, " and ")
+}
+
+// HasFieldValue reports whether v holds a non-zero value. Absent fields are nil.
+func HasFieldValue(v any) bool {
+	if v == nil {
+		return false
+	}
+
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return false
+		}
+		rv = rv.Elem()
+	}
+
+	switch rv.Kind() {
+	case reflect.String, reflect.Slice, reflect.Map, reflect.Array:
+		return rv.Len() > 0
+	}
+	return !rv.IsZero()
+}
+
+func fieldValuesEqual(a, b any) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	if ta, ok := a.(time.Time); ok {
+		tb, ok := b.(time.Time)
+		return ok && ta.Equal(tb)
+	}
+
+	ra, rb := reflect.ValueOf(a), reflect.ValueOf(b)
+	if utils.KindIsNumber(ra.Kind()) && utils.KindIsNumber(rb.Kind()) {
+		fa, _ := utils.AnyValueToFloat(a)
+		fb, _ := utils.AnyValueToFloat(b)
+		return fa == fb
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// compareFieldValues orders two field values the way the gt/lt rules do: numbers by value,
+// strings and collections by length, and times chronologically.
+func compareFieldValues(a, b any) (int, bool) {
+	if ta, ok := a.(time.Time); ok {
+		tb, ok := b.(time.Time)
+		if !ok {
+			return 0, false
+		}
+		return ta.Compare(tb), true
+	}
+
+	fa, okA := fieldMagnitude(a)
+	fb, okB := fieldMagnitude(b)
+	if !okA || !okB {
+		return 0, false
+	}
+	return cmp.Compare(fa, fb), true
+}
+
+func fieldMagnitude(v any) (float64, bool) {
+	if v == nil {
+		return 0, false
+	}
+
+	rv := reflect.ValueOf(v)
+	switch {
+	case rv.Kind() == reflect.String, rv.Kind() == reflect.Slice, rv.Kind() == reflect.Map, rv.Kind() == reflect.Array:
+		return float64(rv.Len()), true
+	case utils.KindIsNumber(rv.Kind()):
+		f, err := utils.AnyValueToFloat(v)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// CrossFieldNames returns the sibling fields a cross-field rule refers to, or an error when
+// args do not fit the rule.
+func CrossFieldNames(rule string, args []string) ([]string, error) {
+	if len(args) == 0 {
+		return nil, fmt.Errorf("validate rule '%s' requires a field name", rule)
+	}
+
+	switch rule {
+	case "required_if", "required_unless":
+		if len(args)%2 != 0 {
+			return nil, fmt.Errorf("validate rule '%s' expects field and value pairs", rule)
+		}
+		names := make([]string, 0, len(args)/2)
+		for i := 0; i < len(args); i += 2 {
+			names = append(names, args[i])
+		}
+		return names, nil
+	case "eqfield", "nefield", "gtfield", "gtefield", "ltfield", "ltefield":
+		return args[:1], nil
+	}
+	return args, nil
+}
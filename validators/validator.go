@@ -20,6 +20,9 @@ func Validate(val any, rulesStr string) error {
 		valKind = valType.Kind()
 	}
 
+	aliasMu.RLock()
+	rulesStr = aliases.Expand(rulesStr)
+	aliasMu.RUnlock()
 	ruleDefs := parseRules(rulesStr)
 
 	for _, def := range ruleDefs {
@@ -33,6 +36,9 @@ func Validate(val any, rulesStr string) error {
 }
 
 // ValidateStruct validates a struct's fields based on 'validate' tags.
+//
+// Deprecated: ValidateStruct only checks top-level fields. Use gofi.ValidateStruct, which
+// validates nested values with the rules of request validation.
 func ValidateStruct(s any) error {
 	v := reflect.ValueOf(s)
 	if v.Kind() == reflect.Ptr {